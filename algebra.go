@@ -70,7 +70,7 @@ func (v Vector) Hadamard(v2 Vector) Vector {
 }
 
 func (v Vector) Sub(v2 Vector) Vector {
-	out := make(Vector, len(v))
+	out := make(Vector, 0, len(v))
 	for i := range v {
 		out = append(out, v[i]-v2[i])
 	}
@@ -89,15 +89,35 @@ func (v Vector) IsZero() bool {
 type Poly []Element
 type PolyCommit []Commit
 
+// The operations on polynomials create the new coefficients in the field of
+// the existing ones, such that polynomials can be defined over the scalar
+// field of any curve.
+
+// zeroOf returns the zero of the field of the coefficients, or of the field of
+// the default curve if all the polynomials are empty
+func zeroOf(ps ...Poly) Element {
+	for _, p := range ps {
+		if len(p) > 0 {
+			return p[0].Clone().Zero()
+		}
+	}
+	return NewElement()
+}
+
+// isZero returns true if the element is the zero of its field
+func isZero(e Element) bool {
+	return e.Equal(e.Clone().Zero())
+}
+
 func (p Poly) Mul(p2 Poly) Poly {
 	l := len(p) + len(p2) - 1
 	output := make(Poly, l)
 	for i := 0; i < l; i++ {
-		output[i] = NewElement()
+		output[i] = zeroOf(p, p2)
 	}
 	for i, v1 := range p {
 		for j, v2 := range p2 {
-			tmp := NewElement().Mul(v1, v2)
+			tmp := v1.Clone().Mul(v1, v2)
 			output[i+j] = output[i+j].Add(output[i+j], tmp)
 		}
 	}
@@ -106,7 +126,7 @@ func (p Poly) Mul(p2 Poly) Poly {
 
 func (p Poly) Eval(i Element) Element {
 	xi := i.Clone()
-	v := zero.Clone()
+	v := i.Clone().Zero()
 	for j := len(p) - 1; j >= 0; j-- {
 		v.Mul(v, xi)
 		v.Add(v, p[j])
@@ -121,12 +141,12 @@ func (p Poly) Div(p2 Poly) (q Poly, r Poly) {
 	divisor := p2
 	out := make(Poly, len(dividend))
 	for i, c := range dividend {
-		out[i] = NewElement().Set(c)
+		out[i] = c.Clone()
 	}
 	for i := 0; i < len(dividend)-(len(divisor)-1); i++ {
 		out[i].Div(out[i], divisor[0])
-		if coef := out[i]; !coef.Equal(zero) {
-			var a = NewElement()
+		if coef := out[i]; !isZero(coef) {
+			var a = coef.Clone()
 			for j := 1; j < len(divisor); j++ {
 				out[i+j].Add(out[i+j], a.Mul(a.Neg(divisor[j]), coef))
 			}
@@ -143,7 +163,10 @@ func (p *Poly) Div2(p2 Poly) (q Poly, r Poly) {
 		num := r[len(r)-1].Clone()
 		t := num.Div(num, p2[len(p2)-1])
 		degreeT := len(r) - len(p2)
-		tPoly := newPoly(degreeT)
+		tPoly := make(Poly, degreeT+1)
+		for i := range tPoly {
+			tPoly[i] = t.Clone().Zero()
+		}
 		tPoly[len(tPoly)-1] = t
 		q = q.Add(tPoly)
 		// tPoly is n-th coefficient of p / highest of p2 (degree m)
@@ -166,11 +189,11 @@ func (p Poly) Add(p2 Poly) Poly {
 
 	output := make(Poly, max)
 	for i := range p {
-		output[i] = NewElement().Set(p[i])
+		output[i] = p[i].Clone()
 	}
 	for i := range p2 {
 		if output[i] == nil {
-			output[i] = NewElement()
+			output[i] = p2[i].Clone().Zero()
 		}
 		output[i] = output[i].Add(output[i], p2[i])
 	}
@@ -185,16 +208,37 @@ func (p Poly) Sub(p2 Poly) Poly {
 
 	output := make(Poly, max)
 	for i := range p {
-		output[i] = NewElement().Set(p[i])
+		output[i] = p[i].Clone()
 	}
 	for i := range p2 {
 		if output[i] == nil {
-			output[i] = NewElement()
+			output[i] = p2[i].Clone().Zero()
 		}
 		output[i] = output[i].Sub(output[i], p2[i])
 	}
 	return output
 }
+
+// Scale returns the polynomial p with all its coefficients multiplied by s
+func (p Poly) Scale(s Element) Poly {
+	output := make(Poly, len(p))
+	for i := range p {
+		output[i] = s.Clone().Mul(p[i], s)
+	}
+	return output
+}
+
+// ScaleVar returns the polynomial p(c * x), whose coefficients are p_i * c^i
+func (p Poly) ScaleVar(c Element) Poly {
+	output := make(Poly, len(p))
+	ci := c.Clone().One()
+	for i := range p {
+		output[i] = c.Clone().Mul(p[i], ci)
+		ci = ci.Mul(ci, c)
+	}
+	return output
+}
+
 func (p Poly) Equal(p2 Poly) bool {
 	if len(p) != len(p) {
 		return false
@@ -216,10 +260,12 @@ func (p Poly) Clone() Poly {
 	return o
 }
 
-func newPoly(d int) Poly {
+// newPoly returns the zero polynomial of degree d over the scalar field of
+// the curve, with all its coefficients allocated
+func newPoly(c Curve, d int) Poly {
 	o := make(Poly, d+1)
 	for i := 0; i <= d; i++ {
-		o[i] = NewElement()
+		o[i] = c.NewElement()
 	}
 	return o
 }
@@ -230,7 +276,7 @@ func newPoly(d int) Poly {
 func (p Poly) Normalize() Poly {
 	maxi := len(p)
 	for i := len(p) - 1; i >= 0; i-- {
-		if !p[i].Equal(zero) {
+		if !isZero(p[i]) {
 			return p[:maxi]
 		}
 		maxi--
@@ -256,14 +302,15 @@ func Interpolate(ys []Element) Poly {
 	for i, y := range ys {
 		pairs = append(pairs, pair{I: i + 1, V: y})
 	}
-	x, y := xyScalar(Group, pairs)
+	f := fieldOf(ys[0])
+	x, y := xyScalar(f, pairs)
 
-	var accPoly = Poly([]Element{zero})
+	var accPoly = Poly([]Element{f.Scalar()})
 	//den := g.Scalar()
 	// Notations follow the Wikipedia article on Lagrange interpolation
 	// https://en.wikipedia.org/wiki/Lagrange_polynomial
 	for j := range x {
-		basis := lagrangeBasis(Group, j, x)
+		basis := lagrangeBasis(f, j, x)
 		for i := range basis {
 			basis[i] = basis[i].Mul(basis[i], y[j])
 		}
@@ -288,7 +335,7 @@ func (s byIndexScalar) Less(i, j int) bool { return s[i].I < s[j].I }
 // xyScalar returns the list of (x_i, y_i) pairs indexed. The first map returned
 // is the list of x_i and the second map is the list of y_i, both indexed in
 // their respective map at index i.
-func xyScalar(g kyber.Group, shares []pair) (map[int]Element, map[int]Element) {
+func xyScalar(g scalarGroup, shares []pair) (map[int]Element, map[int]Element) {
 	// we are sorting first the shares since the shares may be unrelated for
 	// some applications. In this case, all participants needs to interpolate on
 	// the exact same order shares.
@@ -311,11 +358,30 @@ func xyScalar(g kyber.Group, shares []pair) (map[int]Element, map[int]Element) {
 	return x, y
 }
 
+// scalarGroup creates the scalars of a field: kyber.Group is one, fieldOf
+// returns one from an element of the field
+type scalarGroup interface {
+	Scalar() kyber.Scalar
+}
+
+type elementField struct {
+	e Element
+}
+
+func (f elementField) Scalar() kyber.Scalar {
+	return f.e.Clone().Zero()
+}
+
+// fieldOf returns the field of the element
+func fieldOf(e Element) scalarGroup {
+	return elementField{e}
+}
+
 // lagrangeBasis returns a PriPoly containing the Lagrange coefficients for the
 // i-th position. xs is a mapping between the indices and the values that the
 // interpolation is using, computed with xyScalar().
-func lagrangeBasis(g kyber.Group, i int, xs map[int]Element) Poly {
-	var basis = Poly([]Element{one.Clone()})
+func lagrangeBasis(g scalarGroup, i int, xs map[int]Element) Poly {
+	var basis = Poly([]Element{g.Scalar().One()})
 	// compute lagrange basis l_j
 	den := g.Scalar().One()
 	var acc = g.Scalar().One()
@@ -324,7 +390,7 @@ func lagrangeBasis(g kyber.Group, i int, xs map[int]Element) Poly {
 			continue
 		}
 		// multiply by x -i
-		basis = basis.Mul(Poly([]Element{NewElement().Neg(xm), one.Clone()}))
+		basis = basis.Mul(Poly([]Element{g.Scalar().Neg(xm), g.Scalar().One()}))
 		den.Sub(xs[i], xm) // den = xi - xm
 		den.Inv(den)       // den = 1 / den
 		acc.Mul(acc, den)  // acc = acc * den
@@ -371,8 +437,8 @@ func (p Poly) Commit(base Commit) PolyCommit {
 func GeneratePowersCommit(base Commit, e Element, shift Element, power int) []Commit {
 	var gi = make([]Commit, 0, power+1)
 	gi = append(gi, base.Clone().Mul(shift, nil))
-	var si = one.Clone()
-	var tmp = NewElement()
+	var si = e.Clone().One()
+	var tmp = e.Clone().Zero()
 	for i := 0; i < power; i++ {
 		// s * (tmp) = s * ( s * ( .. ) )
 		si = si.Mul(si, e)
@@ -382,3 +448,35 @@ func GeneratePowersCommit(base Commit, e Element, shift Element, power int) []Co
 	}
 	return gi
 }
+
+// interpolatePoints returns the polynomial p of degree len(xs)-1 such that
+// p(xs[i]) = ys[i] for all i. Contrary to Interpolate, the points can be any
+// distinct elements of the scalar field of the curve.
+func interpolatePoints(c Curve, xs, ys []Element) Poly {
+	if len(xs) != len(ys) {
+		panic("mismatch of length between points and evaluations")
+	}
+	var xm = make(map[int]Element, len(xs))
+	for i, x := range xs {
+		xm[i] = x
+	}
+	var accPoly = Poly([]Element{c.NewElement()})
+	for j := range xs {
+		basis := lagrangeBasis(c.Suite.G1(), j, xm)
+		for i := range basis {
+			basis[i] = basis[i].Mul(basis[i], ys[j])
+		}
+		accPoly = accPoly.Add(basis)
+	}
+	return accPoly
+}
+
+// vanishingPoly returns the minimal polynomial (x - p_1)(x - p_2)... that
+// vanishes on all the given points of the scalar field of the curve
+func vanishingPoly(c Curve, points []Element) Poly {
+	var z = Poly([]Element{c.Element(1)})
+	for _, p := range points {
+		z = z.Mul(Poly([]Element{c.NewElement().Neg(p), c.Element(1)}))
+	}
+	return z
+}
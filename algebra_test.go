@@ -195,9 +195,13 @@ func TestAlgebraPolyDiv(t *testing.T) {
 }
 
 func randomPoly(d int) Poly {
+	return randomPolyWith(BLS12381, d)
+}
+
+func randomPolyWith(c Curve, d int) Poly {
 	var poly = make(Poly, 0, d+1)
 	for i := 0; i <= d; i++ {
-		poly = append(poly, NewElement().Pick(random.New()))
+		poly = append(poly, c.NewElement().Pick(random.New()))
 	}
 	return poly
 }
@@ -0,0 +1,206 @@
+package playsnark
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+)
+
+// Proof is a proof generated by a Backend. Each backend only verifies its own
+// proofs.
+type Proof interface {
+	// Backend returns the name of the backend which generated the proof
+	Backend() string
+	encoding.BinaryMarshaler
+}
+
+// ProvingKey is the part of the setup used by the prover
+type ProvingKey interface {
+	// Prove returns a proof that the prover knows the solution of the circuit,
+	// ordered as the variables of the R1CS: "const", the inputs, the outputs
+	// and the intermediate variables.
+	Prove(sol Vector) (Proof, error)
+	encoding.BinaryMarshaler
+}
+
+// VerifyingKey is the part of the setup used by the verifier
+type VerifyingKey interface {
+	// Verify returns true if the proof is valid for the public values,
+	// "const" followed by the inputs and outputs.
+	Verify(p Proof, io Vector) bool
+	encoding.BinaryMarshaler
+}
+
+// Backend is a proof system for the circuits compiled to a QAP. It allows to
+// write the application code once for all the proof systems.
+type Backend interface {
+	Name() string
+	// Setup runs the trusted setup for the circuit. The keys run on the curve
+	// of the QAP.
+	Setup(qap QAP) (ProvingKey, VerifyingKey)
+	// ProvingKey, VerifyingKey and Proof decode the keys and proofs
+	// marshalled with MarshalBinary for the circuit of the QAP
+	ProvingKey(qap QAP, buff []byte) (ProvingKey, error)
+	VerifyingKey(qap QAP, buff []byte) (VerifyingKey, error)
+	Proof(qap QAP, buff []byte) (Proof, error)
+}
+
+// BackendByName returns the backend with the given name
+func BackendByName(name string) (Backend, error) {
+	for _, b := range Backends {
+		if b.Name() == name {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown backend %q", name)
+}
+
+// Groth16 is the backend of Groth16Prove and Groth16Verify
+var Groth16 Backend = groth16Backend{}
+
+// PHGR13 is the backend of PHGR13Prove and PHGR13Verify
+var PHGR13 Backend = phgr13Backend{}
+
+// Backends lists all the backends
+var Backends = []Backend{Groth16, PHGR13}
+
+// errInvalidSolution is returned when proving with a solution which doesn't
+// satisfy the circuit
+var errInvalidSolution = errors.New("solution does not satisfy the circuit")
+
+// checkSolution returns an error if the solution doesn't satisfy the circuit.
+// The proof systems panic or generate invalid proofs otherwise.
+func checkSolution(qap QAP, sol Vector) error {
+	if len(sol) != qap.nbVars {
+		return errors.New("solution has the wrong number of variables")
+	}
+	if !qap.IsValid(sol) {
+		return errInvalidSolution
+	}
+	return nil
+}
+
+type groth16Backend struct{}
+
+func (groth16Backend) Name() string {
+	return "groth16"
+}
+
+func (groth16Backend) Setup(qap QAP) (ProvingKey, VerifyingKey) {
+	setup := NewGroth16TrustedSetup(qap)
+	return groth16ProvingKey{setup: setup, qap: qap}, groth16VerifyingKey{setup.VerifyingKey()}
+}
+
+func (groth16Backend) ProvingKey(qap QAP, buff []byte) (ProvingKey, error) {
+	setup, err := unmarshalGroth16Setup(qap, buff)
+	return groth16ProvingKey{setup: setup, qap: qap}, err
+}
+
+func (groth16Backend) VerifyingKey(qap QAP, buff []byte) (VerifyingKey, error) {
+	vk, err := unmarshalGroth16VerifyingKey(qap, buff)
+	return groth16VerifyingKey{vk}, err
+}
+
+func (groth16Backend) Proof(qap QAP, buff []byte) (Proof, error) {
+	return unmarshalGroth16Proof(qap.curve, buff)
+}
+
+// Backend implements the Proof interface
+func (p Groth16Proof) Backend() string {
+	return Groth16.Name()
+}
+
+type groth16ProvingKey struct {
+	setup Groth16Setup
+	qap   QAP
+}
+
+func (pk groth16ProvingKey) Prove(sol Vector) (Proof, error) {
+	if err := checkSolution(pk.qap, sol); err != nil {
+		return nil, err
+	}
+	return Groth16Prove(pk.setup, pk.qap, sol), nil
+}
+
+func (pk groth16ProvingKey) MarshalBinary() ([]byte, error) {
+	return pk.setup.MarshalBinary()
+}
+
+type groth16VerifyingKey struct {
+	vk Groth16VerifyingKey
+}
+
+func (vk groth16VerifyingKey) Verify(p Proof, io Vector) bool {
+	proof, ok := p.(Groth16Proof)
+	if !ok {
+		return false
+	}
+	return vk.vk.Verify(proof, io)
+}
+
+func (vk groth16VerifyingKey) MarshalBinary() ([]byte, error) {
+	return vk.vk.MarshalBinary()
+}
+
+type phgr13Backend struct{}
+
+func (phgr13Backend) Name() string {
+	return "phgr13"
+}
+
+func (phgr13Backend) Setup(qap QAP) (ProvingKey, VerifyingKey) {
+	setup := NewPHGR13TrustedSetup(qap)
+	return phgr13ProvingKey{ek: setup.EK, qap: qap}, phgr13VerifyingKey{vk: setup.VK, qap: qap}
+}
+
+func (phgr13Backend) ProvingKey(qap QAP, buff []byte) (ProvingKey, error) {
+	ek, err := unmarshalPHGR13EvalKey(qap, buff)
+	return phgr13ProvingKey{ek: ek, qap: qap}, err
+}
+
+func (phgr13Backend) VerifyingKey(qap QAP, buff []byte) (VerifyingKey, error) {
+	vk, err := unmarshalPHGR13VerifKey(qap, buff)
+	return phgr13VerifyingKey{vk: vk, qap: qap}, err
+}
+
+func (phgr13Backend) Proof(qap QAP, buff []byte) (Proof, error) {
+	return unmarshalPHGR13Proof(qap.curve, buff)
+}
+
+// Backend implements the Proof interface
+func (p PHGR13Proof) Backend() string {
+	return PHGR13.Name()
+}
+
+type phgr13ProvingKey struct {
+	ek  PHGR13EvalKey
+	qap QAP
+}
+
+func (pk phgr13ProvingKey) Prove(sol Vector) (Proof, error) {
+	if err := checkSolution(pk.qap, sol); err != nil {
+		return nil, err
+	}
+	return PHGR13Prove(pk.ek, pk.qap, sol), nil
+}
+
+func (pk phgr13ProvingKey) MarshalBinary() ([]byte, error) {
+	return pk.ek.MarshalBinary()
+}
+
+type phgr13VerifyingKey struct {
+	vk  PHGR13VerifKey
+	qap QAP
+}
+
+func (vk phgr13VerifyingKey) Verify(p Proof, io Vector) bool {
+	proof, ok := p.(PHGR13Proof)
+	if !ok || len(io) != vk.qap.nbIO {
+		return false
+	}
+	return PHGR13Verify(vk.vk, vk.qap, proof, io)
+}
+
+func (vk phgr13VerifyingKey) MarshalBinary() ([]byte, error) {
+	return vk.vk.MarshalBinary()
+}
@@ -0,0 +1,138 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// productCircuit returns the circuit x * y + x = out and its solution for
+// x = 4 and y = 5
+func productCircuit() (R1CS, Vector) {
+	c := NewR1CS()
+	c.NewInput("x")
+	c.NewInput("y")
+	c.NewOutput("out")
+	c.NewVar("u")
+	c.Mul("x", "y", "u")
+	c.Add("u", "x", "out")
+	// const, x, y, out, u
+	return c, Vector{1, 4, 5, 24, 20}
+}
+
+func TestBackendByName(t *testing.T) {
+	for _, b := range Backends {
+		found, err := BackendByName(b.Name())
+		require.NoError(t, err)
+		require.Equal(t, b, found)
+	}
+	_, err := BackendByName("plonk")
+	require.Error(t, err)
+}
+
+// TestBackends is the conformance test suite every backend must pass
+func TestBackends(t *testing.T) {
+	r1cs := createR1CS()
+	product, productSol := productCircuit()
+	var circuits = []struct {
+		name string
+		r1cs R1CS
+		sol  Vector
+	}{
+		{"cubic", r1cs, createWitness(r1cs)},
+		{"product", product, productSol},
+	}
+	var tests = []struct {
+		name string
+		test func(t *testing.T, b Backend, qap QAP, sol Vector)
+	}{
+		{"Valid", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			pk, vk := b.Setup(qap)
+			proof, err := pk.Prove(sol)
+			require.NoError(t, err)
+			require.Equal(t, b.Name(), proof.Backend())
+			require.True(t, vk.Verify(proof, sol[:qap.nbIO]))
+		}},
+		{"WrongPublicValue", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			pk, vk := b.Setup(qap)
+			proof, err := pk.Prove(sol)
+			require.NoError(t, err)
+			io := append(Vector{}, sol[:qap.nbIO]...)
+			io[len(io)-1]++
+			require.False(t, vk.Verify(proof, io))
+		}},
+		{"WrongNumberOfPublicValues", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			pk, vk := b.Setup(qap)
+			proof, err := pk.Prove(sol)
+			require.NoError(t, err)
+			require.False(t, vk.Verify(proof, sol[:qap.nbIO-1]))
+			require.False(t, vk.Verify(proof, sol[:qap.nbIO+1]))
+		}},
+		{"InvalidSolution", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			pk, _ := b.Setup(qap)
+			wrong := append(Vector{}, sol...)
+			wrong[len(wrong)-1]++
+			_, err := pk.Prove(wrong)
+			require.Error(t, err)
+			_, err = pk.Prove(sol[:len(sol)-1])
+			require.Error(t, err)
+		}},
+		{"OtherSetup", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			pk, _ := b.Setup(qap)
+			_, vk := b.Setup(qap)
+			proof, err := pk.Prove(sol)
+			require.NoError(t, err)
+			require.False(t, vk.Verify(proof, sol[:qap.nbIO]))
+		}},
+		{"Marshal", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			pk, vk := b.Setup(qap)
+			buff, err := pk.MarshalBinary()
+			require.NoError(t, err)
+			pk, err = b.ProvingKey(qap, buff)
+			require.NoError(t, err)
+			_, err = b.ProvingKey(qap, buff[:len(buff)-1])
+			require.Error(t, err)
+			buff, err = vk.MarshalBinary()
+			require.NoError(t, err)
+			vk, err = b.VerifyingKey(qap, buff)
+			require.NoError(t, err)
+			_, err = b.VerifyingKey(qap, append(buff, 0))
+			require.Error(t, err)
+
+			proof, err := pk.Prove(sol)
+			require.NoError(t, err)
+			buff, err = proof.MarshalBinary()
+			require.NoError(t, err)
+			proof, err = b.Proof(qap, buff)
+			require.NoError(t, err)
+			require.True(t, vk.Verify(proof, sol[:qap.nbIO]))
+		}},
+		{"OtherBackend", func(t *testing.T, b Backend, qap QAP, sol Vector) {
+			_, vk := b.Setup(qap)
+			for _, other := range Backends {
+				if other.Name() == b.Name() {
+					continue
+				}
+				pk, _ := other.Setup(qap)
+				proof, err := pk.Prove(sol)
+				require.NoError(t, err)
+				require.False(t, vk.Verify(proof, sol[:qap.nbIO]))
+			}
+		}},
+	}
+	for _, b := range Backends {
+		t.Run(b.Name(), func(t *testing.T) {
+			for _, c := range Curves {
+				for _, circuit := range circuits {
+					qap := ToQAPWith(c, circuit.r1cs)
+					require.True(t, qap.IsValid(circuit.sol))
+					for _, test := range tests {
+						t.Run(c.Name+"/"+circuit.name+"/"+test.name, func(t *testing.T) {
+							test.test(t, b, qap, circuit.sol)
+						})
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Package bn254 exposes the BN254 pairing friendly curve, also called
+// alt_bn128, behind the interfaces of github.com/drand/kyber used by the
+// playsnark package. BN254 is the curve supported by the Ethereum precompiles,
+// which makes it the curve of choice for proofs verified on chain.
+//
+// The arithmetic comes from github.com/consensys/gnark-crypto: the types of
+// this package only adapt its scalars and points to kyber. The points are
+// marshalled as expected by the precompiles (EIP-196 and EIP-197): the
+// affine coordinates as 32 bytes big endian words, the imaginary part first
+// for the coordinates of G2, and all zeros for the point at infinity.
+package bn254
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+)
+
+// Suite is the BN254 pairing suite
+type Suite struct{}
+
+// NewSuite returns the BN254 pairing suite
+func NewSuite() *Suite {
+	return &Suite{}
+}
+
+// G1 returns the group G1 of BN254, whose points are on the curve over the base
+// field.
+func (s *Suite) G1() kyber.Group {
+	return g1Group{}
+}
+
+// G2 returns the group G2 of BN254, whose points are on the twist over the
+// quadratic extension of the base field.
+func (s *Suite) G2() kyber.Group {
+	return g2Group{}
+}
+
+// GT returns the target group of the pairing
+func (s *Suite) GT() kyber.Group {
+	return gtGroup{}
+}
+
+// Pair returns e(p1,p2) with p1 in G1 and p2 in G2
+func (s *Suite) Pair(p1, p2 kyber.Point) kyber.Point {
+	return new(gtPoint).pair(p1.(*g1Point), p2.(*g2Point))
+}
+
+func (s *Suite) String() string {
+	return "BN254"
+}
+
+type g1Group struct{}
+
+func (g1Group) String() string       { return "BN254.G1" }
+func (g1Group) ScalarLen() int       { return fr.Bytes }
+func (g1Group) Scalar() kyber.Scalar { return new(scalar) }
+func (g1Group) PointLen() int        { return g1Size }
+func (g1Group) Point() kyber.Point   { return new(g1Point).Null() }
+
+type g2Group struct{}
+
+func (g2Group) String() string       { return "BN254.G2" }
+func (g2Group) ScalarLen() int       { return fr.Bytes }
+func (g2Group) Scalar() kyber.Scalar { return new(scalar) }
+func (g2Group) PointLen() int        { return g2Size }
+func (g2Group) Point() kyber.Point   { return new(g2Point).Null() }
+
+type gtGroup struct{}
+
+func (gtGroup) String() string       { return "BN254.GT" }
+func (gtGroup) ScalarLen() int       { return fr.Bytes }
+func (gtGroup) Scalar() kyber.Scalar { return new(scalar) }
+func (gtGroup) PointLen() int        { return gtSize }
+func (gtGroup) Point() kyber.Point   { return new(gtPoint).Null() }
+
+// scalar is an element of the scalar field of BN254, the same for all the
+// groups
+type scalar struct {
+	s fr.Element
+}
+
+func (s *scalar) bigInt() *big.Int {
+	return s.s.ToBigIntRegular(new(big.Int))
+}
+
+func (s *scalar) MarshalBinary() ([]byte, error) {
+	b := s.s.Bytes()
+	return b[:], nil
+}
+
+func (s *scalar) UnmarshalBinary(buff []byte) error {
+	if len(buff) != fr.Bytes {
+		return errors.New("bn254: invalid scalar length")
+	}
+	v := new(big.Int).SetBytes(buff)
+	if v.Cmp(fr.Modulus()) >= 0 {
+		return errors.New("bn254: scalar out of range")
+	}
+	s.s.SetBigInt(v)
+	return nil
+}
+
+func (s *scalar) String() string {
+	return s.s.String()
+}
+
+func (s *scalar) MarshalSize() int {
+	return fr.Bytes
+}
+
+func (s *scalar) MarshalTo(w io.Writer) (int, error) {
+	return marshalTo(s, w)
+}
+
+func (s *scalar) UnmarshalFrom(r io.Reader) (int, error) {
+	return unmarshalFrom(s, r)
+}
+
+func (s *scalar) Equal(s2 kyber.Scalar) bool {
+	return s.s.Equal(&s2.(*scalar).s)
+}
+
+func (s *scalar) Set(a kyber.Scalar) kyber.Scalar {
+	s.s.Set(&a.(*scalar).s)
+	return s
+}
+
+func (s *scalar) Clone() kyber.Scalar {
+	return new(scalar).Set(s)
+}
+
+func (s *scalar) SetInt64(v int64) kyber.Scalar {
+	s.s.SetBigInt(big.NewInt(v))
+	return s
+}
+
+func (s *scalar) Zero() kyber.Scalar {
+	s.s.SetZero()
+	return s
+}
+
+func (s *scalar) Add(a, b kyber.Scalar) kyber.Scalar {
+	s.s.Add(&a.(*scalar).s, &b.(*scalar).s)
+	return s
+}
+
+func (s *scalar) Sub(a, b kyber.Scalar) kyber.Scalar {
+	s.s.Sub(&a.(*scalar).s, &b.(*scalar).s)
+	return s
+}
+
+func (s *scalar) Neg(a kyber.Scalar) kyber.Scalar {
+	s.s.Neg(&a.(*scalar).s)
+	return s
+}
+
+func (s *scalar) One() kyber.Scalar {
+	s.s.SetOne()
+	return s
+}
+
+func (s *scalar) Mul(a, b kyber.Scalar) kyber.Scalar {
+	s.s.Mul(&a.(*scalar).s, &b.(*scalar).s)
+	return s
+}
+
+func (s *scalar) Div(a, b kyber.Scalar) kyber.Scalar {
+	s.s.Div(&a.(*scalar).s, &b.(*scalar).s)
+	return s
+}
+
+func (s *scalar) Inv(a kyber.Scalar) kyber.Scalar {
+	s.s.Inverse(&a.(*scalar).s)
+	return s
+}
+
+func (s *scalar) Pick(rand cipher.Stream) kyber.Scalar {
+	s.s.SetBigInt(random.Int(fr.Modulus(), rand))
+	return s
+}
+
+// SetBytes sets the scalar to the big endian integer reduced modulo the order
+// of the field
+func (s *scalar) SetBytes(buff []byte) kyber.Scalar {
+	s.s.SetBytes(buff)
+	return s
+}
+
+// marshalTo and unmarshalFrom implement the streaming methods of kyber on top
+// of the binary marshalling
+func marshalTo(m kyber.Marshaling, w io.Writer) (int, error) {
+	buff, err := m.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+	return w.Write(buff)
+}
+
+func unmarshalFrom(m kyber.Marshaling, r io.Reader) (int, error) {
+	buff := make([]byte, m.MarshalSize())
+	n, err := io.ReadFull(r, buff)
+	if err != nil {
+		return n, err
+	}
+	return n, m.UnmarshalBinary(buff)
+}
@@ -0,0 +1,76 @@
+package bn254
+
+import (
+	"testing"
+
+	"github.com/drand/kyber"
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBN254Pairing(t *testing.T) {
+	s := NewSuite()
+	a := s.G1().Scalar().Pick(random.New())
+	b := s.G2().Scalar().Pick(random.New())
+	ab := s.G1().Scalar().Mul(a, b)
+	// e(a*G1, b*G2) = e(ab*G1, G2)
+	left := s.Pair(s.G1().Point().Mul(a, nil), s.G2().Point().Mul(b, nil))
+	right := s.Pair(s.G1().Point().Mul(ab, nil), s.G2().Point().Base())
+	require.True(t, left.Equal(right))
+	require.False(t, left.Equal(s.Pair(s.G1().Point().Base(), s.G2().Point().Base())))
+
+	// the target group is written additively
+	sum := s.GT().Point().Add(left, right)
+	double := s.Pair(s.G1().Point().Mul(s.G1().Scalar().Add(ab, ab), nil), s.G2().Point().Base())
+	require.True(t, sum.Equal(double))
+}
+
+func TestBN254Scalar(t *testing.T) {
+	g := NewSuite().G1()
+	minusOne := g.Scalar().SetInt64(-1)
+	require.True(t, g.Scalar().Add(minusOne, g.Scalar().One()).Equal(g.Scalar().Zero()))
+	x := g.Scalar().Pick(random.New())
+	require.True(t, g.Scalar().Mul(x, g.Scalar().Inv(x)).Equal(g.Scalar().One()))
+	buff, err := x.MarshalBinary()
+	require.NoError(t, err)
+	y := g.Scalar()
+	require.NoError(t, y.UnmarshalBinary(buff))
+	require.True(t, x.Equal(y))
+	require.True(t, x.Clone().Equal(x))
+}
+
+func TestBN254Marshal(t *testing.T) {
+	s := NewSuite()
+	// the generator of G1 is (1,2) as in EIP-197
+	buff, err := s.G1().Point().Base().MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, buff, 64)
+	require.Equal(t, byte(1), buff[31])
+	require.Equal(t, byte(2), buff[63])
+	// the point at infinity is encoded with zeros
+	buff, err = s.G2().Point().Null().MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 128), buff)
+
+	for _, g := range []kyber.Group{s.G1(), s.G2()} {
+		p := g.Point().Pick(random.New())
+		buff, err := p.MarshalBinary()
+		require.NoError(t, err)
+		q := g.Point()
+		require.NoError(t, q.UnmarshalBinary(buff))
+		require.True(t, p.Equal(q))
+		require.NoError(t, q.UnmarshalBinary(make([]byte, g.PointLen())))
+		require.True(t, q.Equal(g.Point().Null()))
+	}
+}
+
+func TestBN254Hash(t *testing.T) {
+	g := NewSuite().G1()
+	hash := func(msg string) kyber.Point {
+		return g.Point().(interface{ Hash([]byte) kyber.Point }).Hash([]byte(msg))
+	}
+	p := hash("a")
+	require.True(t, p.Equal(hash("a")))
+	require.False(t, p.Equal(hash("b")))
+	require.False(t, p.Equal(g.Point().Null()))
+}
@@ -0,0 +1,374 @@
+package bn254
+
+import (
+	"crypto/cipher"
+	"errors"
+	"io"
+
+	bn "github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/drand/kyber"
+)
+
+const (
+	g1Size = bn.SizeOfG1AffineUncompressed
+	g2Size = bn.SizeOfG2AffineUncompressed
+	gtSize = bn.SizeOfGT
+)
+
+// hashDST is the domain separation tag of the hash to G1
+var hashDST = []byte("PLAYSNARK-BN254G1_XMD:SHA-256_SVDW_RO_")
+
+// errEmbed is returned since the points can't embed data
+var errEmbed = errors.New("bn254: embedding data is not supported")
+
+// isZero returns true if the encoding is the one of the point at infinity
+func isZero(buff []byte) bool {
+	for _, b := range buff {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// g1Point is a point of G1 in jacobian coordinates
+type g1Point struct {
+	p bn.G1Jac
+}
+
+func (p *g1Point) affine() *bn.G1Affine {
+	return new(bn.G1Affine).FromJacobian(&p.p)
+}
+
+func (p *g1Point) MarshalBinary() ([]byte, error) {
+	return p.affine().Marshal(), nil
+}
+
+func (p *g1Point) UnmarshalBinary(buff []byte) error {
+	if len(buff) != g1Size {
+		return errors.New("bn254: invalid G1 point length")
+	}
+	var a bn.G1Affine
+	if !isZero(buff) {
+		if err := a.Unmarshal(buff); err != nil {
+			return err
+		}
+	}
+	p.p.FromAffine(&a)
+	return nil
+}
+
+func (p *g1Point) String() string {
+	return p.p.String()
+}
+
+func (p *g1Point) MarshalSize() int {
+	return g1Size
+}
+
+func (p *g1Point) MarshalTo(w io.Writer) (int, error) {
+	return marshalTo(p, w)
+}
+
+func (p *g1Point) UnmarshalFrom(r io.Reader) (int, error) {
+	return unmarshalFrom(p, r)
+}
+
+func (p *g1Point) Equal(p2 kyber.Point) bool {
+	return p.p.Equal(&p2.(*g1Point).p)
+}
+
+func (p *g1Point) Null() kyber.Point {
+	p.p.FromAffine(&bn.G1Affine{})
+	return p
+}
+
+func (p *g1Point) Base() kyber.Point {
+	g, _, _, _ := bn.Generators()
+	p.p.Set(&g)
+	return p
+}
+
+func (p *g1Point) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(new(scalar).Pick(rand), nil)
+}
+
+func (p *g1Point) Set(a kyber.Point) kyber.Point {
+	p.p.Set(&a.(*g1Point).p)
+	return p
+}
+
+func (p *g1Point) Clone() kyber.Point {
+	return new(g1Point).Set(p)
+}
+
+func (p *g1Point) EmbedLen() int {
+	return 0
+}
+
+func (p *g1Point) Embed(data []byte, rand cipher.Stream) kyber.Point {
+	return p.Pick(rand)
+}
+
+func (p *g1Point) Data() ([]byte, error) {
+	return nil, errEmbed
+}
+
+func (p *g1Point) Add(a, b kyber.Point) kyber.Point {
+	var res bn.G1Jac
+	res.Set(&a.(*g1Point).p)
+	res.AddAssign(&b.(*g1Point).p)
+	p.p.Set(&res)
+	return p
+}
+
+func (p *g1Point) Sub(a, b kyber.Point) kyber.Point {
+	var res bn.G1Jac
+	res.Set(&a.(*g1Point).p)
+	res.SubAssign(&b.(*g1Point).p)
+	p.p.Set(&res)
+	return p
+}
+
+func (p *g1Point) Neg(a kyber.Point) kyber.Point {
+	p.p.Neg(&a.(*g1Point).p)
+	return p
+}
+
+// Mul returns s * q, or s times the base point if q is nil
+func (p *g1Point) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(g1Point).Base()
+	}
+	p.p.ScalarMultiplication(&q.(*g1Point).p, s.(*scalar).bigInt())
+	return p
+}
+
+// Hash maps the message to a point of G1 whose discrete logarithm is unknown
+func (p *g1Point) Hash(msg []byte) kyber.Point {
+	a, err := bn.HashToCurveG1Svdw(msg, hashDST)
+	if err != nil {
+		panic(err)
+	}
+	p.p.FromAffine(&a)
+	return p
+}
+
+// g2Point is a point of G2 in jacobian coordinates
+type g2Point struct {
+	p bn.G2Jac
+}
+
+func (p *g2Point) affine() *bn.G2Affine {
+	return new(bn.G2Affine).FromJacobian(&p.p)
+}
+
+func (p *g2Point) MarshalBinary() ([]byte, error) {
+	return p.affine().Marshal(), nil
+}
+
+func (p *g2Point) UnmarshalBinary(buff []byte) error {
+	if len(buff) != g2Size {
+		return errors.New("bn254: invalid G2 point length")
+	}
+	var a bn.G2Affine
+	if !isZero(buff) {
+		if err := a.Unmarshal(buff); err != nil {
+			return err
+		}
+	}
+	p.p.FromAffine(&a)
+	return nil
+}
+
+func (p *g2Point) String() string {
+	return p.p.String()
+}
+
+func (p *g2Point) MarshalSize() int {
+	return g2Size
+}
+
+func (p *g2Point) MarshalTo(w io.Writer) (int, error) {
+	return marshalTo(p, w)
+}
+
+func (p *g2Point) UnmarshalFrom(r io.Reader) (int, error) {
+	return unmarshalFrom(p, r)
+}
+
+func (p *g2Point) Equal(p2 kyber.Point) bool {
+	return p.p.Equal(&p2.(*g2Point).p)
+}
+
+func (p *g2Point) Null() kyber.Point {
+	p.p.FromAffine(&bn.G2Affine{})
+	return p
+}
+
+func (p *g2Point) Base() kyber.Point {
+	_, g, _, _ := bn.Generators()
+	p.p.Set(&g)
+	return p
+}
+
+func (p *g2Point) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(new(scalar).Pick(rand), nil)
+}
+
+func (p *g2Point) Set(a kyber.Point) kyber.Point {
+	p.p.Set(&a.(*g2Point).p)
+	return p
+}
+
+func (p *g2Point) Clone() kyber.Point {
+	return new(g2Point).Set(p)
+}
+
+func (p *g2Point) EmbedLen() int {
+	return 0
+}
+
+func (p *g2Point) Embed(data []byte, rand cipher.Stream) kyber.Point {
+	return p.Pick(rand)
+}
+
+func (p *g2Point) Data() ([]byte, error) {
+	return nil, errEmbed
+}
+
+func (p *g2Point) Add(a, b kyber.Point) kyber.Point {
+	var res bn.G2Jac
+	res.Set(&a.(*g2Point).p)
+	res.AddAssign(&b.(*g2Point).p)
+	p.p.Set(&res)
+	return p
+}
+
+func (p *g2Point) Sub(a, b kyber.Point) kyber.Point {
+	var res bn.G2Jac
+	res.Set(&a.(*g2Point).p)
+	res.SubAssign(&b.(*g2Point).p)
+	p.p.Set(&res)
+	return p
+}
+
+func (p *g2Point) Neg(a kyber.Point) kyber.Point {
+	p.p.Neg(&a.(*g2Point).p)
+	return p
+}
+
+// Mul returns s * q, or s times the base point if q is nil
+func (p *g2Point) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(g2Point).Base()
+	}
+	p.p.ScalarMultiplication(&q.(*g2Point).p, s.(*scalar).bigInt())
+	return p
+}
+
+// gtPoint is an element of the target group. The group is multiplicative but
+// written additively as in kyber: Add multiplies, Mul exponentiates and Null
+// is one.
+type gtPoint struct {
+	p bn.GT
+}
+
+// pair sets the point to e(a,b)
+func (p *gtPoint) pair(a *g1Point, b *g2Point) *gtPoint {
+	res, err := bn.Pair([]bn.G1Affine{*a.affine()}, []bn.G2Affine{*b.affine()})
+	if err != nil {
+		panic(err)
+	}
+	p.p = res
+	return p
+}
+
+func (p *gtPoint) MarshalBinary() ([]byte, error) {
+	return p.p.Marshal(), nil
+}
+
+func (p *gtPoint) UnmarshalBinary(buff []byte) error {
+	return p.p.Unmarshal(buff)
+}
+
+func (p *gtPoint) String() string {
+	return p.p.String()
+}
+
+func (p *gtPoint) MarshalSize() int {
+	return gtSize
+}
+
+func (p *gtPoint) MarshalTo(w io.Writer) (int, error) {
+	return marshalTo(p, w)
+}
+
+func (p *gtPoint) UnmarshalFrom(r io.Reader) (int, error) {
+	return unmarshalFrom(p, r)
+}
+
+func (p *gtPoint) Equal(p2 kyber.Point) bool {
+	return p.p.Equal(&p2.(*gtPoint).p)
+}
+
+func (p *gtPoint) Null() kyber.Point {
+	p.p.SetOne()
+	return p
+}
+
+// Base sets the point to e(G1,G2) with the generators of G1 and G2
+func (p *gtPoint) Base() kyber.Point {
+	return p.pair(new(g1Point).Base().(*g1Point), new(g2Point).Base().(*g2Point))
+}
+
+func (p *gtPoint) Pick(rand cipher.Stream) kyber.Point {
+	return p.Mul(new(scalar).Pick(rand), nil)
+}
+
+func (p *gtPoint) Set(a kyber.Point) kyber.Point {
+	p.p.Set(&a.(*gtPoint).p)
+	return p
+}
+
+func (p *gtPoint) Clone() kyber.Point {
+	return new(gtPoint).Set(p)
+}
+
+func (p *gtPoint) EmbedLen() int {
+	return 0
+}
+
+func (p *gtPoint) Embed(data []byte, rand cipher.Stream) kyber.Point {
+	return p.Pick(rand)
+}
+
+func (p *gtPoint) Data() ([]byte, error) {
+	return nil, errEmbed
+}
+
+func (p *gtPoint) Add(a, b kyber.Point) kyber.Point {
+	p.p.Mul(&a.(*gtPoint).p, &b.(*gtPoint).p)
+	return p
+}
+
+func (p *gtPoint) Sub(a, b kyber.Point) kyber.Point {
+	var inv bn.GT
+	inv.Inverse(&b.(*gtPoint).p)
+	p.p.Mul(&a.(*gtPoint).p, &inv)
+	return p
+}
+
+func (p *gtPoint) Neg(a kyber.Point) kyber.Point {
+	p.p.Inverse(&a.(*gtPoint).p)
+	return p
+}
+
+// Mul returns q^s, or e(G1,G2)^s if q is nil
+func (p *gtPoint) Mul(s kyber.Scalar, q kyber.Point) kyber.Point {
+	if q == nil {
+		q = new(gtPoint).Base()
+	}
+	p.p.Exp(&q.(*gtPoint).p, *s.(*scalar).bigInt())
+	return p
+}
@@ -2,6 +2,7 @@ package bproof
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 )
 
@@ -24,20 +25,21 @@ type LinearCombination interface {
 // product represents the multiplication of a variable by a coefficient. It is a
 // term of a linear combination.
 type product struct {
-	Witness variable
+	Witness Variable
 	Coeff   Scalar
 }
 
-func newOneProduct(v variable) product {
+func newOneProduct(v Variable) product {
 	return product{Witness: v, Coeff: NewScalar().One()}
 }
-func newMinusProduct(v variable) product {
+func newMinusProduct(v Variable) product {
 	a := NewScalar().One()
 	return product{Witness: v, Coeff: a.Neg(a)}
 }
 
-// kind represents the type of variable that is possible: Left Right OUT or
-// Constant (i.e. a "k" coefficient in a linear combination).
+// kind represents the type of variable that is possible: Left Right OUT,
+// Constant (i.e. a "k" coefficient in a linear combination) or Committed (i.e.
+// a high level variable committed outside of the circuit).
 type kind int
 
 const (
@@ -45,15 +47,18 @@ const (
 	VRIGHT
 	VOUT
 	CST
+	VCOMMITTED
 )
 
-type variable struct {
+// Variable is a reference to one of the wires of a multiplier of the circuit.
+// A variable is itself a linear combination with a single term.
+type Variable struct {
 	Index int
 	Kind  kind
 }
 
-func (v *variable) Products() []product {
-	return []product{newOneProduct(*v)}
+func (v Variable) Products() []product {
+	return []product{newOneProduct(v)}
 }
 
 type linearCombination []product
@@ -67,11 +72,23 @@ type Constraint []Scalar
 type Circuit struct {
 	nbVars      int
 	constraints []LinearCombination
+	// number of high level variables committed with Commit
+	nbCommitted int
+
+	// callbacks building the randomized constraints of the second phase, see
+	// SpecifyRandomizedConstraints
+	deferred []func(RandomizedConstraintSystem) error
+	// nbPhaseOne is the number of multipliers allocated during the first
+	// phase, i.e. before the prover commits and runs the deferred callbacks
+	nbPhaseOne int
+	// randomized is true once the second phase has started
+	randomized bool
 
 	// filled later by "Flatten"
 	wl []Constraint
 	wr []Constraint
 	wo []Constraint
+	wv []Constraint
 	wk Constraint
 }
 
@@ -80,6 +97,13 @@ type ProverCircuit struct {
 	a []Scalar
 	b []Scalar
 	c []Scalar
+
+	// committed values, their blinding factors and their commitments with
+	// the Pedersen generators pc
+	pc    PedersenGens
+	v     []Scalar
+	gamma []Scalar
+	V     []Point
 }
 
 func NewCircuit() *Circuit {
@@ -87,14 +111,42 @@ func NewCircuit() *Circuit {
 }
 
 func NewProverCircuit() *ProverCircuit {
-	return &ProverCircuit{Circuit: *NewCircuit()}
+	return NewProverCircuitWith(NewPedersenGens())
+}
+
+// NewProverCircuitWith returns a prover committing to values with the given
+// Pedersen generators, which must be the ones given to Prove.
+func NewProverCircuitWith(pc PedersenGens) *ProverCircuit {
+	return &ProverCircuit{Circuit: *NewCircuit(), pc: pc}
+}
+
+// newCommitted creates a new high level variable
+func (c *Circuit) newCommitted() Variable {
+	v := Variable{Index: c.nbCommitted, Kind: VCOMMITTED}
+	c.nbCommitted++
+	return v
+}
+
+// Commit commits to the value with the given blinding factor using the
+// Pedersen generators of the prover and returns the commitment
+//
+//	V = value * B + blinding * B~
+//
+// and a variable referencing the committed value that can be used in linear
+// combinations. The verifier creates the same variable from V only.
+func (c *ProverCircuit) Commit(value, blinding Scalar) (Point, Variable) {
+	V := c.pc.Commit(value, blinding)
+	c.v = append(c.v, value)
+	c.gamma = append(c.gamma, blinding)
+	c.V = append(c.V, V)
+	return V, c.newCommitted()
 }
 
 // Mul creates three new variables in the circuit such that a * b =
 // variable in this constraint systems, and then we create a linear
 // combination to say that the "previous" variable equals the "new
 // variable"- this operation is called "wiring"
-func (c *Circuit) Mul(a, b LinearCombination) (left variable, right variable, out variable) {
+func (c *Circuit) Mul(a, b LinearCombination) (left Variable, right Variable, out Variable) {
 	left, right, out = c.newMultiplier()
 	c.wire(a, left)
 	c.wire(b, right)
@@ -102,31 +154,47 @@ func (c *Circuit) Mul(a, b LinearCombination) (left variable, right variable, ou
 }
 
 func (c *Circuit) Add(a, b LinearCombination) LinearCombination {
-	lc := linearCombination(append(a.Products(), b.Products()...))
-	return &lc
+	return concat(a.Products(), b.Products())
+}
+
+// Sub returns the linear combination a - b
+func (c *Circuit) Sub(a, b LinearCombination) LinearCombination {
+	return c.Add(a, c.MulCst(b, NewScalar().SetInt64(-1)))
 }
 
+// MulCst returns a new linear combination where all coefficients of a are
+// multiplied by cst
 func (c *Circuit) MulCst(a LinearCombination, cst Scalar) LinearCombination {
+	var lc linearCombination
 	for _, p := range a.Products() {
-		p.Coeff = p.Coeff.Mul(p.Coeff, cst)
+		lc = append(lc, product{Witness: p.Witness, Coeff: NewScalar().Mul(p.Coeff, cst)})
 	}
-	return a
+	return &lc
 }
 
 func (c *Circuit) AddCst(a LinearCombination, cst Scalar) LinearCombination {
 	// No need to specify index since the constant in the linear constraint is
 	// not a vector it's a single value
-	p := product{Coeff: cst, Witness: variable{Kind: CST}}
-	lc := linearCombination(append(a.Products(), p))
+	p := product{Coeff: cst, Witness: Variable{Kind: CST}}
+	return concat(a.Products(), []product{p})
+}
+
+// concat returns a new linear combination containing the products of a and b.
+// The products are copied such that appending to the result never modifies a
+// linear combination given as input.
+func concat(a, b []product) LinearCombination {
+	lc := make(linearCombination, 0, len(a)+len(b))
+	lc = append(lc, a...)
+	lc = append(lc, b...)
 	return &lc
 }
 
 // NewMultiplier creates a left and output variable to use for multiplication
 // and additions
-func (c *Circuit) newMultiplier() (left variable, right variable, out variable) {
-	left = variable{Index: c.nbVars, Kind: VLEFT}
-	right = variable{Index: c.nbVars, Kind: VRIGHT}
-	out = variable{Index: c.nbVars, Kind: VOUT}
+func (c *Circuit) newMultiplier() (left Variable, right Variable, out Variable) {
+	left = Variable{Index: c.nbVars, Kind: VLEFT}
+	right = Variable{Index: c.nbVars, Kind: VRIGHT}
+	out = Variable{Index: c.nbVars, Kind: VOUT}
 	c.nbVars += 1
 	return left, right, out
 }
@@ -137,23 +205,59 @@ func (c *Circuit) Constraint(lc LinearCombination) {
 	c.constraints = append(c.constraints, lc)
 }
 
+// SpecifyRandomizedConstraints registers a callback that will create
+// constraints during the second phase of the circuit. Gadgets like a shuffle
+// need a random challenge from the verifier inside their constraints, but the
+// challenge must only be known once the prover can not change the values of
+// the variables anymore. So the prover first commits to all the multipliers of
+// the first phase, then derives the challenges from the transcript containing
+// these commitments and only then builds the randomized constraints. The
+// verifier runs the same callbacks with the same challenges.
+func (c *Circuit) SpecifyRandomizedConstraints(cb func(RandomizedConstraintSystem) error) error {
+	if c.randomized {
+		return errors.New("bproof: can not specify randomized constraints during the second phase")
+	}
+	c.deferred = append(c.deferred, cb)
+	return nil
+}
+
+// randomize ends the first phase of the circuit and runs all the deferred
+// callbacks with the given randomized constraint system, which must wrap this
+// circuit.
+func (c *Circuit) randomize(rcs RandomizedConstraintSystem) error {
+	if c.randomized {
+		return errors.New("bproof: circuit already randomized")
+	}
+	c.nbPhaseOne = c.nbVars
+	c.randomized = true
+	for _, cb := range c.deferred {
+		if err := cb(rcs); err != nil {
+			return err
+		}
+	}
+	c.deferred = nil
+	return nil
+}
+
 // wire takes a linear combination and sets the variable v as equal to the
 // result of that linear combination. In practice it means
 // LC + (-1) * v = 0
-func (c *Circuit) wire(input LinearCombination, v variable) {
-	lc := linearCombination(append(input.Products(), newMinusProduct(v)))
-	fmt.Println("New Wire Added: ", lc)
-	c.Constraint(&lc)
+func (c *Circuit) wire(input LinearCombination, v Variable) {
+	lc := concat(input.Products(), []product{newMinusProduct(v)})
+	c.Constraint(lc)
 }
 
 // Allocate creates a left right and output variable where left is set to a,
 // right is set to b, and out is set to a * b.
 // At the beginnin of a circuit, one can create a "one" variable .
-func (c *ProverCircuit) Allocate(a, b Scalar) (left LinearCombination, right LinearCombination, out LinearCombination) {
+// The prover must know the assignments of the variables, it panics otherwise.
+func (c *ProverCircuit) Allocate(a, b Scalar) (left Variable, right Variable, out Variable) {
+	if a == nil || b == nil {
+		panic("bproof: prover allocating a multiplier without assignments")
+	}
 	o := NewScalar().Mul(a, b)
 	c.pushWitness(a, b, o)
-	av, bv, outv := c.newMultiplier()
-	return &av, &bv, &outv
+	return c.newMultiplier()
 }
 
 // Eval takes a linear combination and evaluates its value with the witness
@@ -170,20 +274,22 @@ func (c *ProverCircuit) eval(a LinearCombination) Scalar {
 			s = s.Add(s, NewScalar().Mul(c.c[p.Witness.Index], p.Coeff))
 		case CST:
 			s = s.Add(s, p.Coeff)
+		case VCOMMITTED:
+			s = s.Add(s, NewScalar().Mul(c.v[p.Witness.Index], p.Coeff))
 		}
 	}
 	return s
 }
 
-func (c *ProverCircuit) Mul(a, b LinearCombination) (l variable, r variable, o variable) {
+func (c *ProverCircuit) Mul(a, b LinearCombination) (l Variable, r Variable, o Variable) {
 	va := c.eval(a)
 	vb := c.eval(b)
 	out := NewScalar().Mul(va, vb)
-	fmt.Println("Prover.Mul(): a", va, "  - b", vb, " --> ", out)
 	c.pushWitness(va, vb, out)
 	l, r, o = c.Circuit.Mul(a, b)
 	return
 }
+
 func (c *ProverCircuit) pushWitness(a, b, out Scalar) {
 	c.a = append(c.a, a)
 	c.b = append(c.b, b)
@@ -191,10 +297,11 @@ func (c *ProverCircuit) pushWitness(a, b, out Scalar) {
 }
 
 // Flatten will compute the fully fledged arithemtic circuit representation
-// w_l w_r w_o are matrices of size N x Q and w_k \in F^Q where N is the number
-// of variable and Q is the number of linear constraints.
+// w_l w_r w_o are matrices of size N x Q, w_v is a matrix of size M x Q and
+// w_k \in F^Q where N is the number of variable, M the number of committed
+// variables and Q is the number of linear constraints.
 // for constraint K
-// SUM a_i * w_l_K_i + SUM b_i * w_r_i + SUM c_i * w_o_i = w_k
+// SUM a_i * w_l_K_i + SUM b_i * w_r_i + SUM c_i * w_o_i = SUM v_j * w_v_j + w_k
 // Note that this implementation is not optimized at all: it generates full
 // length vector while they are supposedly very sparse.
 func (c *Circuit) Flatten() {
@@ -204,6 +311,7 @@ func (c *Circuit) Flatten() {
 	wl := make([]Constraint, q)
 	wr := make([]Constraint, q)
 	wo := make([]Constraint, q)
+	wv := make([]Constraint, q)
 	wk := make(Constraint, q)
 	for i := 0; i < q; i++ {
 		wl[i] = make(Constraint, n)
@@ -214,28 +322,36 @@ func (c *Circuit) Flatten() {
 			wr[i][j] = zero()
 			wo[i][j] = zero()
 		}
+		wv[i] = constVec(zero(), c.nbCommitted)
 		wk[i] = zero()
 
 		for _, product := range c.constraints[i].Products() {
+			// a variable can appear multiple times in a linear combination so
+			// we accumulate the coefficients
 			v := product.Witness
 			switch product.Witness.Kind {
 			case VLEFT:
-				wl[i][v.Index] = product.Coeff
+				wl[i][v.Index] = wl[i][v.Index].Add(wl[i][v.Index], product.Coeff)
 			case VRIGHT:
-				wr[i][v.Index] = product.Coeff
+				wr[i][v.Index] = wr[i][v.Index].Add(wr[i][v.Index], product.Coeff)
 			case VOUT:
-				wo[i][v.Index] = product.Coeff
+				wo[i][v.Index] = wo[i][v.Index].Add(wo[i][v.Index], product.Coeff)
 			case CST:
 				// wl + wr + wo = wk
 				// When this circuit adds constant it just adds them to wk, so
 				// to satisfy the constraint we negate wk
-				wk[i] = NewScalar().Neg(product.Coeff)
+				wk[i] = wk[i].Sub(wk[i], product.Coeff)
+			case VCOMMITTED:
+				// same as constants, committed variables are on the right
+				// side of the equation so we negate their coefficient
+				wv[i][v.Index] = wv[i][v.Index].Sub(wv[i][v.Index], product.Coeff)
 			}
 		}
 	}
 	c.wl = wl
 	c.wr = wr
 	c.wo = wo
+	c.wv = wv
 	c.wk = wk
 }
 
@@ -246,6 +362,7 @@ func (c *Circuit) String() string {
 		b.WriteString(fmt.Sprintf("\t- w_l: %v\n", c.wl[k]))
 		b.WriteString(fmt.Sprintf("\t- w_r: %v\n", c.wr[k]))
 		b.WriteString(fmt.Sprintf("\t- w_o: %v\n", c.wo[k]))
+		b.WriteString(fmt.Sprintf("\t- w_v: %v\n", c.wv[k]))
 		b.WriteString(fmt.Sprintf("\t- w_k: %v\n", c.wk[k]))
 	}
 	return b.String()
@@ -257,5 +374,6 @@ func (c *ProverCircuit) String() string {
 	b.WriteString(fmt.Sprintf("Witness A: %v\n", c.a))
 	b.WriteString(fmt.Sprintf("Witness B: %v\n", c.b))
 	b.WriteString(fmt.Sprintf("Witness C: %v\n", c.c))
+	b.WriteString(fmt.Sprintf("Committed V: %v\n", c.v))
 	return b.String()
 }
@@ -1,23 +1,24 @@
 package bproof
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
-// IsSatisfied looks if
+// isSatisfied looks if
 // 1. All multiplication constraints are satisfied
 //		--> the vector a_i * b_i = c_i for all i
 // 2. All linear combinations are satisfied
-// SUM a_i * w_l_K_i + SUM b_i * w_r_i + SUM c_i * w_o_i = w_k
-func (c *ProverCircuit) IsSatisfied(t *testing.T) bool {
+// SUM a_i * w_l_K_i + SUM b_i * w_r_i + SUM c_i * w_o_i = SUM v_j * w_v_j + w_k
+func (c *ProverCircuit) isSatisfied() bool {
 	n := c.Circuit.nbVars
 	// Check 1
 	for i := 0; i < n; i++ {
 		r := NewScalar().Mul(c.a[i], c.b[i])
-		require.True(t, r.Equal(c.c[i]))
+		if !r.Equal(c.c[i]) {
+			return false
+		}
 	}
 
 	// Check 2
@@ -39,7 +40,14 @@ func (c *ProverCircuit) IsSatisfied(t *testing.T) bool {
 			// w_o_i * c_i
 			sum.Add(sum, NewScalar().Mul(wo[i], c.c[i]))
 		}
-		require.True(t, sum.Equal(wk))
+		// right side
+		exp := wk.Clone()
+		for j, v := range c.v {
+			exp = exp.Add(exp, NewScalar().Mul(c.Circuit.wv[k][j], v))
+		}
+		if !sum.Equal(exp) {
+			return false
+		}
 	}
 	return true
 }
@@ -47,6 +55,5 @@ func (c *ProverCircuit) IsSatisfied(t *testing.T) bool {
 func TestCircuitProverValid(t *testing.T) {
 	prover := GenerateCircuit()
 	prover.Flatten()
-	fmt.Println(prover)
-	prover.IsSatisfied(t)
+	require.True(t, prover.isSatisfied())
 }
@@ -0,0 +1,44 @@
+package bproof
+
+// ConstraintSystem is the interface used to build a circuit. It is implemented
+// by the prover, which knows the values of all the variables, and by the
+// verifier, which does not. Gadgets written against this interface can then be
+// run on both sides such that the prover and the verifier derive exactly the
+// same constraints.
+type ConstraintSystem interface {
+	// Mul allocates a new multiplier whose left and right wires are
+	// constrained to be equal to the linear combinations a and b.
+	Mul(a, b LinearCombination) (left Variable, right Variable, out Variable)
+	// Allocate allocates a new multiplier whose left and right wires are set
+	// to a and b. Only the prover needs to give the assignments, the verifier
+	// gives nil values.
+	Allocate(a, b Scalar) (left Variable, right Variable, out Variable)
+	// Constraint constrains the linear combination to be equal to zero.
+	Constraint(lc LinearCombination)
+	// SpecifyRandomizedConstraints defers the creation of constraints
+	// depending on challenges to the second phase of the circuit.
+	SpecifyRandomizedConstraints(cb func(RandomizedConstraintSystem) error) error
+
+	// helpers to build linear combinations
+	Add(a, b LinearCombination) LinearCombination
+	Sub(a, b LinearCombination) LinearCombination
+	MulCst(a LinearCombination, cst Scalar) LinearCombination
+	AddCst(a LinearCombination, cst Scalar) LinearCombination
+}
+
+// RandomizedConstraintSystem is the constraint system given to the callbacks
+// of the second phase: on top of the regular operations, it can derive
+// challenges from the transcript which is bound to the commitments of all the
+// variables of the first phase. These challenges can then be used as
+// coefficients inside the constraints.
+type RandomizedConstraintSystem interface {
+	ConstraintSystem
+	ChallengeScalar(label string) Scalar
+}
+
+// Gadget is a piece of circuit that can be built by the prover and by the
+// verifier. The values a gadget needs to allocate its variables are only set on
+// the prover side and left nil on the verifier side.
+type Gadget interface {
+	Define(cs ConstraintSystem) error
+}
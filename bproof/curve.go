@@ -6,6 +6,7 @@ import (
 )
 
 type Scalar = kyber.Scalar
+type Point = kyber.Point
 
 var Curve = edwards25519.NewBlakeSHA256Ed25519()
 
@@ -13,6 +14,10 @@ func NewScalar() Scalar {
 	return Curve.Scalar()
 }
 
+func NewPoint() Point {
+	return Curve.Point().Null()
+}
+
 func zero() Scalar {
 	return NewScalar().Zero()
 }
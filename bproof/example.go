@@ -1,26 +1,44 @@
 package bproof
 
-// GenerateCircuit generates a sample circuit for the computation
+// SquareSum is a sample gadget for the computation
 // x^2 + y^2 + 5 = z <=>
 // x^2 + y^2 + 5 - z = 0
+// where x and y are only known by the prover and z is public.
+type SquareSum struct {
+	X Scalar
+	Y Scalar
+	Z Scalar
+}
+
+// Define allocates the squares of x and y and constrains their sum
+func (s *SquareSum) Define(cs ConstraintSystem) error {
+	// A multiplier allocated with (x,x) does not force the prover to use the
+	// same value for both wires so we also constrain left - right = 0.
+	square := func(v Scalar) LinearCombination {
+		l, r, o := cs.Allocate(v, v)
+		cs.Constraint(cs.Sub(l, r))
+		return o
+	}
+	x2 := square(s.X)
+	y2 := square(s.Y)
+	x2y2 := cs.Add(x2, y2)
+	// x^2 + y^2 + (5 - z) = 0
+	cst := NewScalar().Sub(NewScalar().SetInt64(5), s.Z)
+	cs.Constraint(cs.AddCst(x2y2, cst))
+	return nil
+}
+
+// GenerateCircuit generates a sample prover circuit for the computation
+// x^2 + y^2 + 5 = z with x = 2 and y = 3 so z = 18
 func GenerateCircuit() *ProverCircuit {
 	c := NewProverCircuit()
-	// 2^2 + 3^2 + 5 = 18 = z
-	x := NewScalar().SetInt64(2)
-	y := NewScalar().SetInt64(3)
-	_, _, x2 := c.Allocate(x, x)
-	_, _, y2 := c.Allocate(y, y)
-	x2y2 := c.Add(x2, y2)
-	left := c.AddCst(x2y2, NewScalar().SetInt64(5))
-
-	// Solution here is to use the "left" Linear combination as a variable and
-	// constraint on the following
-	//        left     * right = z
-	// (x^2 + y^2 + 5) * 1     = z
-	// For that, it's a hack but I need declare a "one" variable such that
-	// 1*1=1, it's then a var i can re-use everywhere in my circuit
-	// I could change the circuit to have a "one" variable though.
-	one, _, _ := c.Allocate(NewScalar().SetInt64(1), NewScalar().SetInt64(1))
-	_, _, _ = c.Mul(left, one)
+	gadget := &SquareSum{
+		X: NewScalar().SetInt64(2),
+		Y: NewScalar().SetInt64(3),
+		Z: NewScalar().SetInt64(18),
+	}
+	if err := gadget.Define(c); err != nil {
+		panic(err)
+	}
 	return c
 }
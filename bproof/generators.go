@@ -0,0 +1,80 @@
+package bproof
+
+import (
+	"fmt"
+)
+
+// PedersenGens contains the two bases used to commit to a single value v with
+// a blinding factor r:
+//
+//	C = v * B + r * BBlinding
+//
+// The commitment is hiding because r is random and binding as long as nobody
+// knows the discrete log of BBlinding in base B. That's why BBlinding is
+// derived by hashing to the curve and not by multiplying B by a known scalar.
+type PedersenGens struct {
+	B         Point
+	BBlinding Point
+}
+
+// NewPedersenGens returns the Pedersen generators where B is the base point of
+// the curve.
+func NewPedersenGens() PedersenGens {
+	return PedersenGens{
+		B:         NewPoint().Base(),
+		BBlinding: hashToPoint("bproof.pedersen.blinding"),
+	}
+}
+
+// Commit returns value * B + blinding * BBlinding
+func (p PedersenGens) Commit(value, blinding Scalar) Point {
+	vb := NewPoint().Mul(value, p.B)
+	rb := NewPoint().Mul(blinding, p.BBlinding)
+	return vb.Add(vb, rb)
+}
+
+// Equal returns true if both generators are the same
+func (p PedersenGens) Equal(p2 PedersenGens) bool {
+	return p.B.Equal(p2.B) && p.BBlinding.Equal(p2.BBlinding)
+}
+
+// Generators contains all the bases needed by the Bulletproofs arguments: the
+// Pedersen generators to commit to single values and the vectors of generators
+// G and H to commit to vectors of values:
+//
+//	C = <a,G> + <b,H> + r * BBlinding
+//
+// where <a,G> = SUM a_i * G_i
+type Generators struct {
+	Pedersen PedersenGens
+	G        []Point
+	H        []Point
+}
+
+// NewGenerators returns the generators allowing to commit to vectors of length
+// up to capacity. All generators are derived deterministically from a public
+// label such that nobody knows any discrete log relation between them.
+func NewGenerators(capacity int) *Generators {
+	g := &Generators{
+		Pedersen: NewPedersenGens(),
+		G:        make([]Point, capacity),
+		H:        make([]Point, capacity),
+	}
+	for i := 0; i < capacity; i++ {
+		g.G[i] = hashToPoint(fmt.Sprintf("bproof.generators.G.%d", i))
+		g.H[i] = hashToPoint(fmt.Sprintf("bproof.generators.H.%d", i))
+	}
+	return g
+}
+
+// Capacity returns the maximum length of the vectors these generators can
+// commit to.
+func (g *Generators) Capacity() int {
+	return len(g.G)
+}
+
+// hashToPoint returns a point whose discrete log is unknown by seeding the
+// random point generation with the given label.
+func hashToPoint(label string) Point {
+	return NewPoint().Pick(Curve.XOF([]byte(label)))
+}
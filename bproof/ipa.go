@@ -0,0 +1,120 @@
+package bproof
+
+import (
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// This file implements the inner product argument from the Bulletproofs paper
+// https://eprint.iacr.org/2017/1066.pdf (protocol 2, section 3).
+// Given public generators G, H and Q and a commitment
+//		P = <a,G> + <b,H> + <a,b> * Q
+// the prover convinces the verifier it knows the vectors a and b. Instead of
+// sending the vectors (linear size), the prover sends 2 * log(n) points: at
+// each round, it splits the vectors in two halves, sends two "cross terms" L
+// and R and then folds everything in half using a challenge u from the
+// verifier.
+
+// InnerProductProof contains the cross terms of each round and the final
+// scalars a and b of length one vectors.
+type InnerProductProof struct {
+	L []Point
+	R []Point
+	A Scalar
+	B Scalar
+}
+
+// proveInnerProduct returns a proof of knowledge of a and b such that
+// P = <a,G> + <b,H> + <a,b> * Q. The length of the vectors must be a power of
+// two.
+func proveInnerProduct(tr *transcript.Transcript, Q Point, G, H []Point, a, b []Scalar) *InnerProductProof {
+	n := len(a)
+	if !isPowerOfTwo(n) || len(b) != n || len(G) != n || len(H) != n {
+		panic("inner product argument requires vectors of the same power of two length")
+	}
+	// we copy all the inputs since we fold them in place
+	a = append([]Scalar{}, a...)
+	b = append([]Scalar{}, b...)
+	G = append([]Point{}, G...)
+	H = append([]Point{}, H...)
+
+	proof := new(InnerProductProof)
+	for n > 1 {
+		n = n / 2
+		aLo, aHi := a[:n], a[n:]
+		bLo, bHi := b[:n], b[n:]
+		gLo, gHi := G[:n], G[n:]
+		hLo, hHi := H[:n], H[n:]
+
+		// L = <a_lo, G_hi> + <b_hi, H_lo> + <a_lo, b_hi> * Q
+		cL := innerProduct(aLo, bHi)
+		L := multiExp(aLo, gHi)
+		L = L.Add(L, multiExp(bHi, hLo))
+		L = L.Add(L, NewPoint().Mul(cL, Q))
+		// R = <a_hi, G_lo> + <b_lo, H_hi> + <a_hi, b_lo> * Q
+		cR := innerProduct(aHi, bLo)
+		R := multiExp(aHi, gLo)
+		R = R.Add(R, multiExp(bLo, hHi))
+		R = R.Add(R, NewPoint().Mul(cR, Q))
+
+		proof.L = append(proof.L, L)
+		proof.R = append(proof.R, R)
+		tr.AppendPoint("L", L)
+		tr.AppendPoint("R", R)
+		u := tr.ChallengeScalar("u", Curve)
+		uInv := NewScalar().Inv(u)
+
+		// a' = a_lo * u + a_hi * u^-1
+		// b' = b_lo * u^-1 + b_hi * u
+		// G' = G_lo * u^-1 + G_hi * u
+		// H' = H_lo * u + H_hi * u^-1
+		// such that the new commitment <a',G'> + <b',H'> + <a',b'> * Q is equal
+		// to P + u^2 * L + u^-2 * R
+		for i := 0; i < n; i++ {
+			aLo[i] = NewScalar().Add(NewScalar().Mul(aLo[i], u), NewScalar().Mul(aHi[i], uInv))
+			bLo[i] = NewScalar().Add(NewScalar().Mul(bLo[i], uInv), NewScalar().Mul(bHi[i], u))
+			gLo[i] = NewPoint().Add(NewPoint().Mul(uInv, gLo[i]), NewPoint().Mul(u, gHi[i]))
+			hLo[i] = NewPoint().Add(NewPoint().Mul(u, hLo[i]), NewPoint().Mul(uInv, hHi[i]))
+		}
+		a, b, G, H = aLo, bLo, gLo, hLo
+	}
+	proof.A = a[0]
+	proof.B = b[0]
+	return proof
+}
+
+// verify returns true if the proof shows knowledge of a and b such that
+// P = <a,G> + <b,H> + <a,b> * Q. The verifier recomputes all the challenges,
+// folds the generators the same way the prover did and checks the final
+// equation with vectors of length one:
+//
+//	P + SUM(u_j^2 * L_j + u_j^-2 * R_j) == a * G' + b * H' + a * b * Q
+func (ipp *InnerProductProof) verify(tr *transcript.Transcript, Q, P Point, G, H []Point) bool {
+	n := len(G)
+	if len(H) != n || len(ipp.L) != len(ipp.R) || 1<<uint(len(ipp.L)) != n {
+		return false
+	}
+	G = append([]Point{}, G...)
+	H = append([]Point{}, H...)
+	acc := P.Clone()
+	for j := range ipp.L {
+		tr.AppendPoint("L", ipp.L[j])
+		tr.AppendPoint("R", ipp.R[j])
+		u := tr.ChallengeScalar("u", Curve)
+		uInv := NewScalar().Inv(u)
+		u2 := NewScalar().Mul(u, u)
+		u2Inv := NewScalar().Mul(uInv, uInv)
+		acc = acc.Add(acc, NewPoint().Mul(u2, ipp.L[j]))
+		acc = acc.Add(acc, NewPoint().Mul(u2Inv, ipp.R[j]))
+
+		n = n / 2
+		for i := 0; i < n; i++ {
+			G[i] = NewPoint().Add(NewPoint().Mul(uInv, G[i]), NewPoint().Mul(u, G[n+i]))
+			H[i] = NewPoint().Add(NewPoint().Mul(u, H[i]), NewPoint().Mul(uInv, H[n+i]))
+		}
+		G, H = G[:n], H[:n]
+	}
+	exp := NewPoint().Mul(ipp.A, G[0])
+	exp = exp.Add(exp, NewPoint().Mul(ipp.B, H[0]))
+	exp = exp.Add(exp, NewPoint().Mul(NewScalar().Mul(ipp.A, ipp.B), Q))
+	return exp.Equal(acc)
+}
@@ -0,0 +1,42 @@
+package bproof
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVec(n int) []Scalar {
+	v := make([]Scalar, n)
+	for i := range v {
+		v[i] = NewScalar().Pick(random.New())
+	}
+	return v
+}
+
+func TestInnerProductProof(t *testing.T) {
+	n := 16
+	gens := NewGenerators(n)
+	Q := hashToPoint("test.Q")
+	a := randomVec(n)
+	b := randomVec(n)
+	// P = <a,G> + <b,H> + <a,b> * Q
+	P := multiExp(a, gens.G)
+	P = P.Add(P, multiExp(b, gens.H))
+	P = P.Add(P, NewPoint().Mul(innerProduct(a, b), Q))
+
+	proof := proveInnerProduct(transcript.New("test"), Q, gens.G, gens.H, a, b)
+	require.Len(t, proof.L, 4)
+	require.True(t, proof.verify(transcript.New("test"), Q, P, gens.G, gens.H))
+
+	// different transcript leads to different challenges
+	require.False(t, proof.verify(transcript.New("other"), Q, P, gens.G, gens.H))
+	// commitment to a different inner product
+	P2 := P.Clone().Add(P, Q)
+	require.False(t, proof.verify(transcript.New("test"), Q, P2, gens.G, gens.H))
+	// tampered final scalar
+	proof.A = proof.A.Add(proof.A, one())
+	require.False(t, proof.verify(transcript.New("test"), Q, P, gens.G, gens.H))
+}
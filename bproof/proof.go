@@ -0,0 +1,393 @@
+package bproof
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// This file implements the arithmetic circuit proof from the Bulletproofs
+// paper https://eprint.iacr.org/2017/1066.pdf (protocol 3, section 5.2) with
+// the two phases extension of the dalek implementation that allows the
+// randomized constraints.
+// The prover shows it knows vectors a_L, a_R and a_O and the openings v of the
+// commitments V such that
+//
+//	a_L o a_R = a_O
+//	W_L * a_L + W_R * a_R + W_O * a_O = W_V * v + W_K
+//
+// where o is the hadamard product and W_L, W_R, W_O, W_V and W_K are the
+// matrices and vector obtained by Flatten. Each row of these matrices is one
+// linear constraint.
+// The main idea is the same as in the range proof: all the equations are
+// combined with powers of random challenges y and z into a single inner product
+// <l(X), r(X)> = t(X) whose coefficient of degree 2 only depends on public
+// information when the circuit is satisfied.
+
+// CircuitProof contains all the elements the prover sends to show that it
+// knows a witness satisfying a circuit.
+type CircuitProof struct {
+	// Commitments to the multipliers of the first phase
+	//	AI1 = <a_L, G> + <a_R, H> + i1 * B~
+	//	AO1 = <a_O, G> + o1 * B~
+	//	S1 = <s_L, G> + <s_R, H> + s1 * B~
+	AI1 Point
+	AO1 Point
+	S1  Point
+	// Same commitments for the multipliers of the second phase, using the
+	// generators following the ones of the first phase. They are the identity
+	// if there are no randomized constraints.
+	AI2 Point
+	AO2 Point
+	S2  Point
+	// Commitments to the coefficients of t(X), except the second one which is
+	// computed by the verifier itself.
+	T1 Point
+	T3 Point
+	T4 Point
+	T5 Point
+	T6 Point
+	// TX = t(x) and TXBlinding is its blinding factor
+	TX         Scalar
+	TXBlinding Scalar
+	// EBlinding is the blinding factor of the commitment to l(x) and r(x)
+	EBlinding Scalar
+	// IPP proves that TX is the inner product of l(x) and r(x)
+	IPP *InnerProductProof
+}
+
+// randomizedProver is the constraint system given to the randomized callbacks
+// of the prover
+type randomizedProver struct {
+	*ProverCircuit
+	tr *transcript.Transcript
+}
+
+func (r *randomizedProver) ChallengeScalar(label string) Scalar {
+	return r.tr.ChallengeScalar(label, Curve)
+}
+
+// randomizedVerifier is the constraint system given to the randomized
+// callbacks of the verifier
+type randomizedVerifier struct {
+	*VerifierCircuit
+	tr *transcript.Transcript
+}
+
+func (r *randomizedVerifier) ChallengeScalar(label string) Scalar {
+	return r.tr.ChallengeScalar(label, Curve)
+}
+
+// Prove returns a proof that the prover knows a witness satisfying the
+// circuit. It first commits to the multipliers of the first phase, then runs
+// the randomized constraints and commits to the multipliers of the second
+// phase before running the protocol on the whole circuit. The verifier must
+// use a transcript in the same state.
+func (c *ProverCircuit) Prove(gens *Generators, tr *transcript.Transcript) (*CircuitProof, error) {
+	if len(c.V) > 0 && !c.pc.Equal(gens.Pedersen) {
+		return nil, errors.New("bproof: values committed with other Pedersen generators")
+	}
+	appendCircuitCommitments(tr, c.V)
+	pc := gens.Pedersen
+	proof := new(CircuitProof)
+
+	// blinding vectors for all multipliers, they are drawn as the multipliers
+	// are committed
+	var sL, sR []Scalar
+	// phaseCommit commits to the multipliers in [start,end) and returns the
+	// blinding factors
+	phaseCommit := func(start, end int) (AI, AO, S Point, i, o, s Scalar) {
+		if start == end {
+			return NewPoint(), NewPoint(), NewPoint(), zero(), zero(), zero()
+		}
+		G := gens.G[start:end]
+		H := gens.H[start:end]
+		for k := start; k < end; k++ {
+			sL = append(sL, NewScalar().Pick(random.New()))
+			sR = append(sR, NewScalar().Pick(random.New()))
+		}
+		i = NewScalar().Pick(random.New())
+		o = NewScalar().Pick(random.New())
+		s = NewScalar().Pick(random.New())
+		AI = NewPoint().Mul(i, pc.BBlinding)
+		AI = AI.Add(AI, multiExp(c.a[start:end], G))
+		AI = AI.Add(AI, multiExp(c.b[start:end], H))
+		AO = NewPoint().Mul(o, pc.BBlinding)
+		AO = AO.Add(AO, multiExp(c.c[start:end], G))
+		S = NewPoint().Mul(s, pc.BBlinding)
+		S = S.Add(S, multiExp(sL[start:end], G))
+		S = S.Add(S, multiExp(sR[start:end], H))
+		return
+	}
+
+	// First phase
+	n1 := c.nbVars
+	if gens.Capacity() < n1 {
+		return nil, fmt.Errorf("bproof: generators capacity %d too small for %d multipliers", gens.Capacity(), n1)
+	}
+	var i1, o1, s1 Scalar
+	proof.AI1, proof.AO1, proof.S1, i1, o1, s1 = phaseCommit(0, n1)
+	tr.AppendPoint("A_I1", proof.AI1)
+	tr.AppendPoint("A_O1", proof.AO1)
+	tr.AppendPoint("S1", proof.S1)
+
+	// Second phase
+	if err := c.randomize(&randomizedProver{ProverCircuit: c, tr: tr}); err != nil {
+		return nil, err
+	}
+	n := c.nbVars
+	padded := nextPowerOfTwo(n)
+	if gens.Capacity() < padded {
+		return nil, fmt.Errorf("bproof: generators capacity %d too small for %d multipliers", gens.Capacity(), padded)
+	}
+	var i2, o2, s2 Scalar
+	proof.AI2, proof.AO2, proof.S2, i2, o2, s2 = phaseCommit(n1, n)
+	tr.AppendPoint("A_I2", proof.AI2)
+	tr.AppendPoint("A_O2", proof.AO2)
+	tr.AppendPoint("S2", proof.S2)
+
+	y := tr.ChallengeScalar("y", Curve)
+	z := tr.ChallengeScalar("z", Curve)
+	c.Flatten()
+	wL, wR, wO, wV, _ := c.flattenWithChallenge(z)
+	yn := powers(y, n)
+	yInv := powers(NewScalar().Inv(y), n)
+
+	// l(X) = l1 * X + l2 * X^2 + l3 * X^3
+	// r(X) = r0 + r1 * X + r3 * X^3
+	// with
+	//	l1 = a_L + y^-n o w_R		r0 = w_O - y^n
+	//	l2 = a_O					r1 = y^n o a_R + w_L
+	//	l3 = s_L					r3 = y^n o s_R
+	l1 := addVec(c.a, hadamard(yInv, wR))
+	l2 := c.c
+	l3 := sL
+	r0 := addVec(wO, scaleVec(yn, NewScalar().SetInt64(-1)))
+	r1 := addVec(hadamard(yn, c.b), wL)
+	r3 := hadamard(yn, sR)
+
+	// t(X) = <l(X), r(X)> = SUM_{i=1}^{6} t_i * X^i
+	t := make([]Scalar, 7)
+	t[1] = innerProduct(l1, r0)
+	t[2] = NewScalar().Add(innerProduct(l1, r1), innerProduct(l2, r0))
+	t[3] = NewScalar().Add(innerProduct(l2, r1), innerProduct(l3, r0))
+	t[4] = NewScalar().Add(innerProduct(l1, r3), innerProduct(l3, r1))
+	t[5] = innerProduct(l2, r3)
+	t[6] = innerProduct(l3, r3)
+	// t_2 = <w_V, v> + w_c + delta(y,z) is not committed, the verifier
+	// computes its commitment from the commitments V and the public information
+	// of the circuit, so its blinding factor is <w_V, gamma>
+	tau := make([]Scalar, 7)
+	T := make([]Point, 7)
+	for _, i := range []int{1, 3, 4, 5, 6} {
+		tau[i] = NewScalar().Pick(random.New())
+		T[i] = pc.Commit(t[i], tau[i])
+	}
+	tau[2] = innerProduct(wV, c.gamma)
+	proof.T1, proof.T3, proof.T4, proof.T5, proof.T6 = T[1], T[3], T[4], T[5], T[6]
+	tr.AppendPoint("T_1", proof.T1)
+	tr.AppendPoint("T_3", proof.T3)
+	tr.AppendPoint("T_4", proof.T4)
+	tr.AppendPoint("T_5", proof.T5)
+	tr.AppendPoint("T_6", proof.T6)
+
+	// u combines the commitments of the two phases and x is the evaluation
+	// point
+	u := tr.ChallengeScalar("u", Curve)
+	x := tr.ChallengeScalar("x", Curve)
+	xs := powers(x, 7)
+
+	proof.TXBlinding = zero()
+	for i := 1; i <= 6; i++ {
+		proof.TXBlinding.Add(proof.TXBlinding, NewScalar().Mul(tau[i], xs[i]))
+	}
+	// e = x * (i1 + u * i2) + x^2 * (o1 + u * o2) + x^3 * (s1 + u * s2)
+	phases := func(a, b Scalar) Scalar {
+		return NewScalar().Add(a, NewScalar().Mul(u, b))
+	}
+	e := NewScalar().Mul(xs[1], phases(i1, i2))
+	e = e.Add(e, NewScalar().Mul(xs[2], phases(o1, o2)))
+	e = e.Add(e, NewScalar().Mul(xs[3], phases(s1, s2)))
+	proof.EBlinding = e
+
+	l := addVec(scaleVec(l1, xs[1]), addVec(scaleVec(l2, xs[2]), scaleVec(l3, xs[3])))
+	r := addVec(r0, addVec(scaleVec(r1, xs[1]), scaleVec(r3, xs[3])))
+	proof.TX = innerProduct(l, r)
+	// the padding multipliers are all zero
+	for k := n; k < padded; k++ {
+		l = append(l, zero())
+		r = append(r, zero())
+	}
+
+	tr.AppendScalar("t_x", proof.TX)
+	tr.AppendScalar("t_x_blinding", proof.TXBlinding)
+	tr.AppendScalar("e_blinding", proof.EBlinding)
+	w := tr.ChallengeScalar("w", Curve)
+	Q := NewPoint().Mul(w, pc.B)
+	G, H := circuitGenerators(gens, n1, padded, u, y)
+	proof.IPP = proveInnerProduct(tr, Q, G, H, l, r)
+	return proof, nil
+}
+
+// Verify returns true if the proof shows that the prover knows a witness
+// satisfying this circuit. The verifier runs the randomized constraints with
+// the challenges derived from the commitments of the first phase and then
+// checks two equations:
+//
+// 1. t(x) has the correct coefficient of degree 2:
+//
+//	TX * B + TXBlinding * B~ == x^2 * (<w_V, V> + (w_c + delta(y,z)) * B) + SUM_{i!=2} x^i * T_i
+//
+// 2. TX is the inner product of l(x) and r(x), via the inner product argument
+// on the commitment
+//
+//	P = x * AI + x^2 * AO + x^3 * S + <x * y^-n o w_R, G'> + <w_O - y^n + x * w_L, H'> - EBlinding * B~
+//
+// where AI = AI1 + u * AI2 (same for AO and S), G'_i = u_i * G_i and
+// H'_i = u_i * y^-i * H_i with u_i being 1 for the multipliers of the first
+// phase and u for the others.
+func (c *VerifierCircuit) Verify(proof *CircuitProof, gens *Generators, tr *transcript.Transcript) bool {
+	if len(c.V) != c.nbCommitted {
+		return false
+	}
+	if proof.AI1 == nil || proof.AO1 == nil || proof.S1 == nil ||
+		proof.AI2 == nil || proof.AO2 == nil || proof.S2 == nil ||
+		proof.T1 == nil || proof.T3 == nil || proof.T4 == nil ||
+		proof.T5 == nil || proof.T6 == nil ||
+		proof.TX == nil || proof.TXBlinding == nil || proof.EBlinding == nil ||
+		proof.IPP == nil || proof.IPP.A == nil || proof.IPP.B == nil {
+		return false
+	}
+	appendCircuitCommitments(tr, c.V)
+	pc := gens.Pedersen
+	n1 := c.nbVars
+	tr.AppendPoint("A_I1", proof.AI1)
+	tr.AppendPoint("A_O1", proof.AO1)
+	tr.AppendPoint("S1", proof.S1)
+	if err := c.randomize(&randomizedVerifier{VerifierCircuit: c, tr: tr}); err != nil {
+		return false
+	}
+	n := c.nbVars
+	padded := nextPowerOfTwo(n)
+	if gens.Capacity() < padded {
+		return false
+	}
+	tr.AppendPoint("A_I2", proof.AI2)
+	tr.AppendPoint("A_O2", proof.AO2)
+	tr.AppendPoint("S2", proof.S2)
+	y := tr.ChallengeScalar("y", Curve)
+	z := tr.ChallengeScalar("z", Curve)
+	tr.AppendPoint("T_1", proof.T1)
+	tr.AppendPoint("T_3", proof.T3)
+	tr.AppendPoint("T_4", proof.T4)
+	tr.AppendPoint("T_5", proof.T5)
+	tr.AppendPoint("T_6", proof.T6)
+	u := tr.ChallengeScalar("u", Curve)
+	x := tr.ChallengeScalar("x", Curve)
+	tr.AppendScalar("t_x", proof.TX)
+	tr.AppendScalar("t_x_blinding", proof.TXBlinding)
+	tr.AppendScalar("e_blinding", proof.EBlinding)
+	w := tr.ChallengeScalar("w", Curve)
+	xs := powers(x, 7)
+
+	c.Flatten()
+	wL, wR, wO, wV, wc := c.flattenWithChallenge(z)
+	yn := powers(y, n)
+	yInv := powers(NewScalar().Inv(y), n)
+
+	// Check 1
+	// delta(y,z) = <y^-n o w_R, w_L>
+	delta := innerProduct(hadamard(yInv, wR), wL)
+	left := pc.Commit(proof.TX, proof.TXBlinding)
+	right := NewPoint().Mul(NewScalar().Mul(xs[2], NewScalar().Add(wc, delta)), pc.B)
+	right = right.Add(right, multiExp(scaleVec(wV, xs[2]), c.V))
+	for i, Ti := range map[int]Point{1: proof.T1, 3: proof.T3, 4: proof.T4, 5: proof.T5, 6: proof.T6} {
+		right = right.Add(right, NewPoint().Mul(xs[i], Ti))
+	}
+	if !left.Equal(right) {
+		return false
+	}
+
+	// Check 2
+	phases := func(a, b Point) Point {
+		return NewPoint().Add(a, NewPoint().Mul(u, b))
+	}
+	P := NewPoint().Mul(xs[1], phases(proof.AI1, proof.AI2))
+	P = P.Add(P, NewPoint().Mul(xs[2], phases(proof.AO1, proof.AO2)))
+	P = P.Add(P, NewPoint().Mul(xs[3], phases(proof.S1, proof.S2)))
+	G, H := circuitGenerators(gens, n1, padded, u, y)
+	// public part of l(x) on G' and of r(x) on H'
+	gExp := scaleVec(hadamard(yInv, wR), x)
+	hExp := addVec(addVec(wO, scaleVec(yn, NewScalar().SetInt64(-1))), scaleVec(wL, x))
+	P = P.Add(P, multiExp(gExp, G[:n]))
+	P = P.Add(P, multiExp(hExp, H[:n]))
+	P = P.Sub(P, NewPoint().Mul(proof.EBlinding, pc.BBlinding))
+	Q := NewPoint().Mul(w, pc.B)
+	P = P.Add(P, NewPoint().Mul(proof.TX, Q))
+	return proof.IPP.verify(tr, Q, P, G, H)
+}
+
+// flattenWithChallenge combines all the linear constraints with the powers of
+// z: it returns w_L = z^Q * W_L, w_R = z^Q * W_R, w_O = z^Q * W_O,
+// w_V = z^Q * W_V and w_c = <z^Q, W_K> where z^Q = [z, z^2, ..., z^q]. The
+// circuit must be flattened first.
+func (c *Circuit) flattenWithChallenge(z Scalar) (wL, wR, wO, wV []Scalar, wc Scalar) {
+	n := c.nbVars
+	wL = constVec(zero(), n)
+	wR = constVec(zero(), n)
+	wO = constVec(zero(), n)
+	wV = constVec(zero(), c.nbCommitted)
+	wc = zero()
+	zq := z.Clone()
+	for q := range c.wl {
+		wL = addVec(wL, scaleVec(c.wl[q], zq))
+		wR = addVec(wR, scaleVec(c.wr[q], zq))
+		wO = addVec(wO, scaleVec(c.wo[q], zq))
+		wV = addVec(wV, scaleVec(c.wv[q], zq))
+		wc = wc.Add(wc, NewScalar().Mul(c.wk[q], zq))
+		zq = zq.Mul(zq, z)
+	}
+	return
+}
+
+// appendCircuitCommitments starts the transcript of a circuit proof by binding
+// it to the commitments of the high level variables
+func appendCircuitCommitments(tr *transcript.Transcript, V []Point) {
+	tr.AppendMessage("dom-sep", []byte("bproof.r1cs"))
+	tr.AppendUint64("m", uint64(len(V)))
+	for _, v := range V {
+		tr.AppendPoint("V", v)
+	}
+}
+
+// circuitGenerators returns the generators G' and H' used in the inner
+// product argument: G'_i = u_i * G_i and H'_i = u_i * y^-i * H_i where u_i is
+// 1 for the multipliers of the first phase and u for the others.
+func circuitGenerators(gens *Generators, n1, padded int, u, y Scalar) (G, H []Point) {
+	yInv := powers(NewScalar().Inv(y), padded)
+	G = make([]Point, padded)
+	H = make([]Point, padded)
+	for i := 0; i < padded; i++ {
+		hFactor := yInv[i]
+		if i < n1 {
+			G[i] = gens.G[i].Clone()
+		} else {
+			G[i] = NewPoint().Mul(u, gens.G[i])
+			hFactor = NewScalar().Mul(hFactor, u)
+		}
+		H[i] = NewPoint().Mul(hFactor, gens.H[i])
+	}
+	return
+}
+
+// nextPowerOfTwo returns the smallest power of two greater or equal to n, and
+// at least one.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p *= 2
+	}
+	return p
+}
@@ -0,0 +1,172 @@
+package bproof
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitProof(t *testing.T) {
+	gens := NewGenerators(8)
+	prover := GenerateCircuit()
+	require.True(t, prover.isSatisfied())
+	proof, err := prover.Prove(gens, transcript.New("test.squaresum"))
+	require.NoError(t, err)
+
+	verify := func(z int64, label string) bool {
+		verifier := NewVerifierCircuit()
+		require.NoError(t, (&SquareSum{Z: NewScalar().SetInt64(z)}).Define(verifier))
+		return verifier.Verify(proof, gens, transcript.New(label))
+	}
+	require.True(t, verify(18, "test.squaresum"))
+	// different public output
+	require.False(t, verify(19, "test.squaresum"))
+	// different transcript
+	require.False(t, verify(18, "test.other"))
+
+	// tampered proof
+	proof.TX = proof.TX.Add(proof.TX, one())
+	require.False(t, verify(18, "test.squaresum"))
+}
+
+func TestCircuitProofIncomplete(t *testing.T) {
+	gens := NewGenerators(8)
+	proof, err := GenerateCircuit().Prove(gens, transcript.New("test.squaresum"))
+	require.NoError(t, err)
+	verify := func(p *CircuitProof) bool {
+		verifier := NewVerifierCircuit()
+		require.NoError(t, (&SquareSum{Z: NewScalar().SetInt64(18)}).Define(verifier))
+		return verifier.Verify(p, gens, transcript.New("test.squaresum"))
+	}
+	require.True(t, verify(proof))
+
+	// a proof missing any of its elements is rejected without panicking
+	zeroes := map[string]func(p *CircuitProof){
+		"AI1":        func(p *CircuitProof) { p.AI1 = nil },
+		"AO1":        func(p *CircuitProof) { p.AO1 = nil },
+		"S1":         func(p *CircuitProof) { p.S1 = nil },
+		"AI2":        func(p *CircuitProof) { p.AI2 = nil },
+		"AO2":        func(p *CircuitProof) { p.AO2 = nil },
+		"S2":         func(p *CircuitProof) { p.S2 = nil },
+		"T1":         func(p *CircuitProof) { p.T1 = nil },
+		"T3":         func(p *CircuitProof) { p.T3 = nil },
+		"T4":         func(p *CircuitProof) { p.T4 = nil },
+		"T5":         func(p *CircuitProof) { p.T5 = nil },
+		"T6":         func(p *CircuitProof) { p.T6 = nil },
+		"TX":         func(p *CircuitProof) { p.TX = nil },
+		"TXBlinding": func(p *CircuitProof) { p.TXBlinding = nil },
+		"EBlinding":  func(p *CircuitProof) { p.EBlinding = nil },
+		"IPP":        func(p *CircuitProof) { p.IPP = nil },
+	}
+	for name, zero := range zeroes {
+		p := *proof
+		zero(&p)
+		require.NotPanics(t, func() { require.False(t, verify(&p)) }, name)
+	}
+	require.False(t, verify(&CircuitProof{}))
+}
+
+func TestCircuitProofInvalidWitness(t *testing.T) {
+	gens := NewGenerators(8)
+	// 2^2 + 3^2 + 5 != 19
+	prover := NewProverCircuit()
+	gadget := &SquareSum{
+		X: NewScalar().SetInt64(2),
+		Y: NewScalar().SetInt64(3),
+		Z: NewScalar().SetInt64(19),
+	}
+	require.NoError(t, gadget.Define(prover))
+	require.False(t, prover.isSatisfied())
+	proof, err := prover.Prove(gens, transcript.New("test"))
+	require.NoError(t, err)
+	verifier := NewVerifierCircuit()
+	require.NoError(t, (&SquareSum{Z: NewScalar().SetInt64(19)}).Define(verifier))
+	require.False(t, verifier.Verify(proof, gens, transcript.New("test")))
+
+	// generators too small
+	prover = GenerateCircuit()
+	_, err = prover.Prove(NewGenerators(1), transcript.New("test"))
+	require.Error(t, err)
+}
+
+func TestCircuitProofCommitted(t *testing.T) {
+	gens := NewGenerators(16)
+	// the prover commits to x and y and shows y is a permutation of x, the
+	// verifier only sees the commitments
+	prove := func(x, y []int64) ([]Point, *CircuitProof) {
+		prover := NewProverCircuit()
+		var V []Point
+		var xs, ys []LinearCombination
+		for i := range x {
+			Vx, vx := prover.Commit(NewScalar().SetInt64(x[i]), NewScalar().Pick(random.New()))
+			Vy, vy := prover.Commit(NewScalar().SetInt64(y[i]), NewScalar().Pick(random.New()))
+			V = append(V, Vx, Vy)
+			xs = append(xs, vx)
+			ys = append(ys, vy)
+		}
+		require.NoError(t, Shuffle(prover, xs, ys))
+		proof, err := prover.Prove(gens, transcript.New("test.committed"))
+		require.NoError(t, err)
+		require.Equal(t, len(V), prover.nbCommitted)
+		return V, proof
+	}
+	verify := func(V []Point, proof *CircuitProof) bool {
+		verifier := NewVerifierCircuit()
+		var xs, ys []LinearCombination
+		for i := 0; i < len(V); i += 2 {
+			xs = append(xs, verifier.Commit(V[i]))
+			ys = append(ys, verifier.Commit(V[i+1]))
+		}
+		require.NoError(t, Shuffle(verifier, xs, ys))
+		return verifier.Verify(proof, gens, transcript.New("test.committed"))
+	}
+
+	V, proof := prove([]int64{1, 2, 3, 4}, []int64{3, 1, 4, 2})
+	require.True(t, verify(V, proof))
+	// swapping the commitments of the two vectors is still a valid shuffle
+	// relation but not the proven one
+	swapped := append([]Point{}, V...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	require.False(t, verify(swapped, proof))
+	// wrong commitment
+	tampered := append([]Point{}, V...)
+	tampered[2] = NewPoint().Add(tampered[2], gens.Pedersen.B)
+	require.False(t, verify(tampered, proof))
+	// missing commitment
+	require.False(t, verify(V[:len(V)-2], proof))
+
+	V, proof = prove([]int64{1, 2, 3, 4}, []int64{3, 1, 4, 4})
+	require.False(t, verify(V, proof))
+
+	// a single committed value with a public constraint: v == 7
+	prover := NewProverCircuit()
+	Vv, v := prover.Commit(NewScalar().SetInt64(7), NewScalar().Pick(random.New()))
+	prover.Constraint(prover.AddCst(v, NewScalar().SetInt64(-7)))
+	require.True(t, prover.isSatisfied())
+	proof, err := prover.Prove(gens, transcript.New("test.committed"))
+	require.NoError(t, err)
+	verifier := NewVerifierCircuit()
+	vv := verifier.Commit(Vv)
+	verifier.Constraint(verifier.AddCst(vv, NewScalar().SetInt64(-7)))
+	require.True(t, verifier.Verify(proof, gens, transcript.New("test.committed")))
+
+	// the commitments use the generators of the prover, which must be the
+	// ones of the proof
+	custom := NewGenerators(gens.Capacity())
+	custom.Pedersen = PedersenGens{B: hashToPoint("test.B"), BBlinding: hashToPoint("test.BBlinding")}
+	blinding := NewScalar().Pick(random.New())
+	prover = NewProverCircuitWith(custom.Pedersen)
+	Vv, v = prover.Commit(NewScalar().SetInt64(7), blinding)
+	require.True(t, Vv.Equal(custom.Pedersen.Commit(NewScalar().SetInt64(7), blinding)))
+	prover.Constraint(prover.AddCst(v, NewScalar().SetInt64(-7)))
+	_, err = prover.Prove(gens, transcript.New("test.committed"))
+	require.Error(t, err)
+	proof, err = prover.Prove(custom, transcript.New("test.committed"))
+	require.NoError(t, err)
+	verifier = NewVerifierCircuit()
+	vv = verifier.Commit(Vv)
+	verifier.Constraint(verifier.AddCst(vv, NewScalar().SetInt64(-7)))
+	require.True(t, verifier.Verify(proof, custom, transcript.New("test.committed")))
+}
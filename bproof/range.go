@@ -0,0 +1,417 @@
+package bproof
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// This file implements the (aggregated) range proof from the Bulletproofs
+// paper https://eprint.iacr.org/2017/1066.pdf sections 4.1 and 4.3.
+// The prover shows that m Pedersen commitments V_j = v_j * B + gamma_j * B~
+// commit to values in the range [0, 2^n) without revealing them and without
+// any trusted setup. The proof size is 2 * log(n * m) + 9 elements.
+//
+// Notations follow the paper, with j being the index of the value in [0,m) and
+// i the index of the bit in [0,n). The index in the aggregated vectors of size
+// n*m is k = j*n + i.
+// The main idea is to decompose each value in bits a_L such that
+//		<a_L_j, 2^n> = v_j		(the bits represent the value)
+//		a_L o a_R = 0			(a_R = a_L - 1 so a_L is only 0 or 1)
+// and then to combine all these equations into a single inner product using
+// random challenges y and z from the verifier, which can be proven with the
+// inner product argument.
+
+// RangeProof contains all the elements that a prover sends to show that a
+// list of commitments are committing to values in the range [0,2^n).
+type RangeProof struct {
+	// A = alpha * B~ + <a_L, G> + <a_R, H>: commitment to the bits
+	A Point
+	// S = rho * B~ + <s_L, G> + <s_R, H>: commitment to the blinding vectors
+	S Point
+	// T1 and T2 commit to the coefficients of the polynomial t(X)
+	T1 Point
+	T2 Point
+	// TauX is the blinding factor of t(x)
+	TauX Scalar
+	// Mu is the blinding factor of A + x*S
+	Mu Scalar
+	// THat = t(x) = <l(x),r(x)>
+	THat Scalar
+	// IPP proves that THat is really the inner product of l(x) and r(x)
+	IPP *InnerProductProof
+}
+
+// ProveSingleRange returns a range proof showing that value lies in [0,2^n)
+// as well as the Pedersen commitment to value with the given blinding factor.
+func ProveSingleRange(gens *Generators, value uint64, blinding Scalar, n int) (*RangeProof, Point, error) {
+	proof, commits, err := ProveRange(gens, []uint64{value}, []Scalar{blinding}, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, commits[0], nil
+}
+
+// VerifySingle returns true if the proof shows that the commitment V commits to
+// a value in [0,2^n).
+func (rp *RangeProof) VerifySingle(gens *Generators, V Point, n int) bool {
+	return rp.Verify(gens, []Point{V}, n)
+}
+
+// ProveRange returns an aggregated range proof showing that all values lie in
+// [0,2^n) as well as their Pedersen commitments with the respective blinding
+// factors. The number of values m must be a power of two and n must be one of
+// 8, 16, 32 or 64. The generators must have a capacity of at least n*m.
+func ProveRange(gens *Generators, values []uint64, blindings []Scalar, n int) (*RangeProof, []Point, error) {
+	if err := checkRangeParams(gens, len(values), n); err != nil {
+		return nil, nil, err
+	}
+	if len(values) != len(blindings) {
+		return nil, nil, errors.New("bproof: different number of values and blindings")
+	}
+	for _, v := range values {
+		if n < 64 && v>>uint(n) != 0 {
+			return nil, nil, fmt.Errorf("bproof: value %d out of range [0,2^%d)", v, n)
+		}
+	}
+	proof, commits := proveRange(gens, values, blindings, n)
+	return proof, commits, nil
+}
+
+// proveRange runs the prover without checking if the values are in range, a
+// proof for an out of range value is never valid.
+func proveRange(gens *Generators, values []uint64, blindings []Scalar, n int) (*RangeProof, []Point) {
+	m := len(values)
+	nm := n * m
+	pc := gens.Pedersen
+	G := gens.G[:nm]
+	H := gens.H[:nm]
+
+	commits := make([]Point, m)
+	for j := range values {
+		commits[j] = pc.Commit(scalarFromUint64(values[j]), blindings[j])
+	}
+	tr := newRangeTranscript(n, m, commits)
+
+	// a_L contains the bits of each value, a_R = a_L - 1
+	aL := make([]Scalar, nm)
+	aR := make([]Scalar, nm)
+	for j, v := range values {
+		for i := 0; i < n; i++ {
+			bit := int64((v >> uint(i)) & 1)
+			aL[j*n+i] = NewScalar().SetInt64(bit)
+			aR[j*n+i] = NewScalar().SetInt64(bit - 1)
+		}
+	}
+	// A = alpha * B~ + <a_L, G> + <a_R, H>
+	alpha := NewScalar().Pick(random.New())
+	A := NewPoint().Mul(alpha, pc.BBlinding)
+	A = A.Add(A, multiExp(aL, G))
+	A = A.Add(A, multiExp(aR, H))
+
+	// s_L and s_R are the blinding vectors that hide a_L and a_R when
+	// revealing l(x) and r(x) at the end
+	sL := make([]Scalar, nm)
+	sR := make([]Scalar, nm)
+	for k := 0; k < nm; k++ {
+		sL[k] = NewScalar().Pick(random.New())
+		sR[k] = NewScalar().Pick(random.New())
+	}
+	// S = rho * B~ + <s_L, G> + <s_R, H>
+	rho := NewScalar().Pick(random.New())
+	S := NewPoint().Mul(rho, pc.BBlinding)
+	S = S.Add(S, multiExp(sL, G))
+	S = S.Add(S, multiExp(sR, H))
+
+	tr.AppendPoint("A", A)
+	tr.AppendPoint("S", S)
+	y := tr.ChallengeScalar("y", Curve)
+	z := tr.ChallengeScalar("z", Curve)
+
+	// l(X) = (a_L - z) + s_L * X
+	// r(X) = y^nm o (a_R + z + s_R * X) + z^(2+j) * 2^n (on the j-th block)
+	yn := powers(y, nm)
+	zs := constVec(z, nm)
+	l0 := addVec(aL, scaleVec(constVec(one(), nm), NewScalar().Neg(z)))
+	l1 := sL
+	r0 := addVec(hadamard(yn, addVec(aR, zs)), zTwoVec(z, n, m))
+	r1 := hadamard(yn, sR)
+
+	// t(X) = <l(X), r(X)> = t0 + t1 * X + t2 * X^2, the prover commits to t1
+	// and t2, t0 is implicitly defined by the values and the challenges
+	t1 := NewScalar().Add(innerProduct(l0, r1), innerProduct(l1, r0))
+	t2 := innerProduct(l1, r1)
+	tau1 := NewScalar().Pick(random.New())
+	tau2 := NewScalar().Pick(random.New())
+	T1 := pc.Commit(t1, tau1)
+	T2 := pc.Commit(t2, tau2)
+
+	tr.AppendPoint("T1", T1)
+	tr.AppendPoint("T2", T2)
+	x := tr.ChallengeScalar("x", Curve)
+
+	// evaluate everything at x
+	l := addVec(l0, scaleVec(l1, x))
+	r := addVec(r0, scaleVec(r1, x))
+	tHat := innerProduct(l, r)
+	// tau_x = tau2 * x^2 + tau1 * x + SUM z^(2+j) * gamma_j
+	x2 := NewScalar().Mul(x, x)
+	tauX := NewScalar().Add(NewScalar().Mul(tau2, x2), NewScalar().Mul(tau1, x))
+	zj := NewScalar().Mul(z, z)
+	for j := 0; j < m; j++ {
+		tauX = tauX.Add(tauX, NewScalar().Mul(zj, blindings[j]))
+		zj = zj.Mul(zj, z)
+	}
+	// mu = alpha + rho * x
+	mu := NewScalar().Add(alpha, NewScalar().Mul(rho, x))
+
+	tr.AppendScalar("taux", tauX)
+	tr.AppendScalar("mu", mu)
+	tr.AppendScalar("that", tHat)
+	// The inner product argument uses Q = w * B with w a fresh challenge and
+	// H' = y^-k * H_k such that <r, H'> cancels out the y^nm factor of r.
+	w := tr.ChallengeScalar("w", Curve)
+	Q := NewPoint().Mul(w, pc.B)
+	hPrime := scaledH(H, y)
+	ipp := proveInnerProduct(tr, Q, G, hPrime, l, r)
+
+	return &RangeProof{
+		A:    A,
+		S:    S,
+		T1:   T1,
+		T2:   T2,
+		TauX: tauX,
+		Mu:   mu,
+		THat: tHat,
+		IPP:  ipp,
+	}, commits
+}
+
+// Verify returns true if the proof shows that every commitment commits to a
+// value in [0,2^n). The verifier checks two equations:
+//
+//  1. t(x) is correctly computed from the committed values:
+//     THat * B + TauX * B~ == SUM z^(2+j) * V_j + delta(y,z) * B + x * T1 + x^2 * T2
+//
+// 2. THat is the inner product of l(x) and r(x) committed in A and S, via the
+// inner product argument on the commitment
+//
+//	P = A + x*S - z * SUM G_k + SUM (z * y^k + z^(2+j) * 2^i) * H'_k - Mu * B~
+func (rp *RangeProof) Verify(gens *Generators, commits []Point, n int) bool {
+	m := len(commits)
+	if err := checkRangeParams(gens, m, n); err != nil {
+		return false
+	}
+	if rp.A == nil || rp.S == nil || rp.T1 == nil || rp.T2 == nil ||
+		rp.TauX == nil || rp.Mu == nil || rp.THat == nil ||
+		rp.IPP == nil || rp.IPP.A == nil || rp.IPP.B == nil {
+		return false
+	}
+	nm := n * m
+	pc := gens.Pedersen
+	G := gens.G[:nm]
+	H := gens.H[:nm]
+
+	tr := newRangeTranscript(n, m, commits)
+	tr.AppendPoint("A", rp.A)
+	tr.AppendPoint("S", rp.S)
+	y := tr.ChallengeScalar("y", Curve)
+	z := tr.ChallengeScalar("z", Curve)
+	tr.AppendPoint("T1", rp.T1)
+	tr.AppendPoint("T2", rp.T2)
+	x := tr.ChallengeScalar("x", Curve)
+	tr.AppendScalar("taux", rp.TauX)
+	tr.AppendScalar("mu", rp.Mu)
+	tr.AppendScalar("that", rp.THat)
+	w := tr.ChallengeScalar("w", Curve)
+
+	// Check 1
+	x2 := NewScalar().Mul(x, x)
+	left := pc.Commit(rp.THat, rp.TauX)
+	right := NewPoint().Mul(delta(y, z, n, m), pc.B)
+	zj := NewScalar().Mul(z, z)
+	for j := 0; j < m; j++ {
+		right = right.Add(right, NewPoint().Mul(zj, commits[j]))
+		zj = zj.Mul(zj, z)
+	}
+	right = right.Add(right, NewPoint().Mul(x, rp.T1))
+	right = right.Add(right, NewPoint().Mul(x2, rp.T2))
+	if !left.Equal(right) {
+		return false
+	}
+
+	// Check 2
+	hPrime := scaledH(H, y)
+	P := NewPoint().Add(rp.A, NewPoint().Mul(x, rp.S))
+	P = P.Add(P, multiExp(constVec(NewScalar().Neg(z), nm), G))
+	hExp := addVec(scaleVec(powers(y, nm), z), zTwoVec(z, n, m))
+	P = P.Add(P, multiExp(hExp, hPrime))
+	P = P.Sub(P, NewPoint().Mul(rp.Mu, pc.BBlinding))
+	// we add THat * Q to P such that the inner product argument proves
+	// P = <l,G> + <r,H'> + THat * Q
+	Q := NewPoint().Mul(w, pc.B)
+	P = P.Add(P, NewPoint().Mul(rp.THat, Q))
+	return rp.IPP.verify(tr, Q, P, G, hPrime)
+}
+
+// newRangeTranscript returns the transcript of a range proof bound to the
+// parameters and the commitments being proven.
+func newRangeTranscript(n, m int, commits []Point) *transcript.Transcript {
+	tr := transcript.New("bproof.rangeproof")
+	tr.AppendUint64("n", uint64(n))
+	tr.AppendUint64("m", uint64(m))
+	for _, V := range commits {
+		tr.AppendPoint("V", V)
+	}
+	return tr
+}
+
+func checkRangeParams(gens *Generators, m, n int) error {
+	switch n {
+	case 8, 16, 32, 64:
+	default:
+		return fmt.Errorf("bproof: invalid bitsize %d, must be 8, 16, 32 or 64", n)
+	}
+	if !isPowerOfTwo(m) {
+		return fmt.Errorf("bproof: number of values %d is not a power of two", m)
+	}
+	if gens.Capacity() < n*m {
+		return fmt.Errorf("bproof: generators capacity %d too small for %d values of %d bits", gens.Capacity(), m, n)
+	}
+	return nil
+}
+
+// zTwoVec returns the vector of size n*m whose j-th block of size n is
+// z^(2+j) * 2^n = [z^(2+j), z^(2+j) * 2, z^(2+j) * 4, ...]
+func zTwoVec(z Scalar, n, m int) []Scalar {
+	twoN := powers(NewScalar().SetInt64(2), n)
+	out := make([]Scalar, 0, n*m)
+	zj := NewScalar().Mul(z, z)
+	for j := 0; j < m; j++ {
+		out = append(out, scaleVec(twoN, zj)...)
+		zj = zj.Mul(zj, z)
+	}
+	return out
+}
+
+// delta returns the part of t0 that the verifier can compute by itself:
+//
+//	delta(y,z) = (z - z^2) * <1, y^nm> - SUM z^(3+j) * <1, 2^n>
+func delta(y, z Scalar, n, m int) Scalar {
+	z2 := NewScalar().Mul(z, z)
+	d := NewScalar().Mul(NewScalar().Sub(z, z2), sumVec(powers(y, n*m)))
+	sumTwo := sumVec(powers(NewScalar().SetInt64(2), n))
+	zj := NewScalar().Mul(z2, z)
+	for j := 0; j < m; j++ {
+		d = d.Sub(d, NewScalar().Mul(zj, sumTwo))
+		zj = zj.Mul(zj, z)
+	}
+	return d
+}
+
+// scaledH returns H'_k = y^-k * H_k
+func scaledH(H []Point, y Scalar) []Point {
+	yInv := powers(NewScalar().Inv(y), len(H))
+	out := make([]Point, len(H))
+	for k := range H {
+		out[k] = NewPoint().Mul(yInv[k], H[k])
+	}
+	return out
+}
+
+// scalarFromUint64 returns the scalar representing v. SetInt64 is not enough
+// since 64 bits range proofs handle values up to 2^64 - 1.
+func scalarFromUint64(v uint64) Scalar {
+	var buff [8]byte
+	binary.LittleEndian.PutUint64(buff[:], v)
+	return NewScalar().SetBytes(buff[:])
+}
+
+// MarshalBinary returns the canonical encoding of the proof:
+//
+//	A || S || T1 || T2 || TauX || Mu || THat || a || b || L_0 || R_0 || ...
+//
+// where points and scalars are encoded with their fixed length encoding.
+func (rp *RangeProof) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	points := []Point{rp.A, rp.S, rp.T1, rp.T2}
+	scalars := []Scalar{rp.TauX, rp.Mu, rp.THat, rp.IPP.A, rp.IPP.B}
+	for j := range rp.IPP.L {
+		points = append(points, rp.IPP.L[j], rp.IPP.R[j])
+	}
+	for _, p := range points[:4] {
+		if err := marshalTo(&b, p); err != nil {
+			return nil, err
+		}
+	}
+	for _, s := range scalars {
+		if err := marshalTo(&b, s); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range points[4:] {
+		if err := marshalTo(&b, p); err != nil {
+			return nil, err
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a proof encoded with MarshalBinary
+func (rp *RangeProof) UnmarshalBinary(buff []byte) error {
+	pl := Curve.PointLen()
+	sl := Curve.ScalarLen()
+	fixed := 4*pl + 5*sl
+	if len(buff) < fixed || (len(buff)-fixed)%(2*pl) != 0 {
+		return errors.New("bproof: invalid range proof length")
+	}
+	rounds := (len(buff) - fixed) / (2 * pl)
+	points := make([]Point, 4+2*rounds)
+	scalars := make([]Scalar, 5)
+	for i := 0; i < 4; i++ {
+		points[i] = NewPoint()
+		if err := points[i].UnmarshalBinary(buff[:pl]); err != nil {
+			return err
+		}
+		buff = buff[pl:]
+	}
+	for i := range scalars {
+		scalars[i] = NewScalar()
+		if err := scalars[i].UnmarshalBinary(buff[:sl]); err != nil {
+			return err
+		}
+		buff = buff[sl:]
+	}
+	for i := 4; i < len(points); i++ {
+		points[i] = NewPoint()
+		if err := points[i].UnmarshalBinary(buff[:pl]); err != nil {
+			return err
+		}
+		buff = buff[pl:]
+	}
+	rp.A, rp.S, rp.T1, rp.T2 = points[0], points[1], points[2], points[3]
+	rp.TauX, rp.Mu, rp.THat = scalars[0], scalars[1], scalars[2]
+	rp.IPP = &InnerProductProof{A: scalars[3], B: scalars[4]}
+	for j := 0; j < rounds; j++ {
+		rp.IPP.L = append(rp.IPP.L, points[4+2*j])
+		rp.IPP.R = append(rp.IPP.R, points[5+2*j])
+	}
+	return nil
+}
+
+type binaryMarshaler interface {
+	MarshalBinary() ([]byte, error)
+}
+
+func marshalTo(b *bytes.Buffer, m binaryMarshaler) error {
+	buff, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	b.Write(buff)
+	return nil
+}
@@ -0,0 +1,117 @@
+package bproof
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeProofSingle(t *testing.T) {
+	gens := NewGenerators(64)
+	for _, tv := range []struct {
+		n int
+		v uint64
+	}{
+		{8, 0},
+		{8, 255},
+		{16, 1 << 15},
+		{32, 1<<32 - 1},
+		{64, 0},
+		{64, 1<<64 - 1},
+	} {
+		blinding := NewScalar().Pick(random.New())
+		proof, V, err := ProveSingleRange(gens, tv.v, blinding, tv.n)
+		require.NoError(t, err)
+		require.True(t, V.Equal(gens.Pedersen.Commit(scalarFromUint64(tv.v), blinding)))
+		require.True(t, proof.VerifySingle(gens, V, tv.n), "value %d on %d bits", tv.v, tv.n)
+		// the proof is bound to the commitment
+		V2 := gens.Pedersen.Commit(scalarFromUint64(tv.v), NewScalar().Pick(random.New()))
+		require.False(t, proof.VerifySingle(gens, V2, tv.n))
+		// the proof is bound to the bitsize
+		if tv.n < 64 {
+			require.False(t, proof.VerifySingle(gens, V, tv.n*2))
+		}
+	}
+}
+
+func TestRangeProofAggregated(t *testing.T) {
+	n := 16
+	for _, m := range []int{1, 2, 4, 8} {
+		gens := NewGenerators(n * m)
+		values := make([]uint64, m)
+		blindings := make([]Scalar, m)
+		for j := range values {
+			values[j] = uint64(j * 1000)
+			blindings[j] = NewScalar().Pick(random.New())
+		}
+		values[m-1] = 1<<16 - 1
+		proof, commits, err := ProveRange(gens, values, blindings, n)
+		require.NoError(t, err)
+		require.Len(t, proof.IPP.L, log2(n*m))
+		require.True(t, proof.Verify(gens, commits, n), "m = %d", m)
+		if m > 1 {
+			// swapping the commitments changes the transcript
+			commits[0], commits[1] = commits[1], commits[0]
+			require.False(t, proof.Verify(gens, commits, n))
+		}
+	}
+}
+
+func TestRangeProofOutOfRange(t *testing.T) {
+	gens := NewGenerators(32)
+	blinding := NewScalar().Pick(random.New())
+	_, _, err := ProveSingleRange(gens, 256, blinding, 8)
+	require.Error(t, err)
+	_, _, err = ProveRange(gens, []uint64{3, 1 << 16}, []Scalar{blinding, blinding}, 16)
+	require.Error(t, err)
+
+	// a prover ignoring the range only commits to the lowest n bits of the
+	// value so the proof can not be valid for the commitment of the full value
+	proof, commits := proveRange(gens, []uint64{256 + 3}, []Scalar{blinding}, 8)
+	require.False(t, proof.Verify(gens, commits, 8))
+
+	// an incomplete proof is rejected without panicking
+	proof, commits = proveRange(gens, []uint64{3}, []Scalar{blinding}, 8)
+	proof.IPP = nil
+	require.False(t, proof.Verify(gens, commits, 8))
+	require.False(t, (&RangeProof{}).Verify(gens, commits, 8))
+
+	// invalid parameters
+	_, _, err = ProveSingleRange(gens, 3, blinding, 12)
+	require.Error(t, err)
+	_, _, err = ProveRange(gens, []uint64{1, 2, 3}, []Scalar{blinding, blinding, blinding}, 8)
+	require.Error(t, err)
+	_, _, err = ProveSingleRange(gens, 3, blinding, 64)
+	require.Error(t, err)
+}
+
+func TestRangeProofMarshalling(t *testing.T) {
+	gens := NewGenerators(64)
+	blindings := []Scalar{NewScalar().Pick(random.New()), NewScalar().Pick(random.New())}
+	proof, commits, err := ProveRange(gens, []uint64{42, 1 << 31}, blindings, 32)
+	require.NoError(t, err)
+	buff, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	// 4 points + 5 scalars + 2 * log(64) points
+	require.Len(t, buff, (4+5+2*6)*32)
+
+	var proof2 RangeProof
+	require.NoError(t, proof2.UnmarshalBinary(buff))
+	require.True(t, proof2.Verify(gens, commits, 32))
+	buff2, err := proof2.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, buff, buff2)
+
+	require.Error(t, proof2.UnmarshalBinary(buff[:len(buff)-1]))
+	require.Error(t, proof2.UnmarshalBinary(buff[:100]))
+}
+
+func log2(n int) int {
+	var l int
+	for n > 1 {
+		n = n / 2
+		l++
+	}
+	return l
+}
@@ -0,0 +1,79 @@
+package bproof
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Shuffle constrains y to be a permutation of x, without revealing the
+// permutation. This is the k-shuffle gadget of the dalek bulletproofs
+// implementation: x is a permutation of y if and only if the two polynomials
+//
+//	(x_0 - Z) * (x_1 - Z) * ... * (x_{k-1} - Z)
+//	(y_0 - Z) * (y_1 - Z) * ... * (y_{k-1} - Z)
+//
+// are equal since they have the same roots. By the Schwartz-Zippel lemma, it
+// is enough to check the equality on a random point z chosen by the verifier
+// once x and y are committed, which is why the gadget uses randomized
+// constraints. It costs 2 * (k - 1) multipliers.
+func Shuffle(cs ConstraintSystem, x, y []LinearCombination) error {
+	if len(x) != len(y) {
+		return errors.New("bproof: shuffle of vectors of different length")
+	}
+	k := len(x)
+	if k == 0 {
+		return nil
+	}
+	if k == 1 {
+		cs.Constraint(cs.Sub(x[0], y[0]))
+		return nil
+	}
+	return cs.SpecifyRandomizedConstraints(func(rcs RandomizedConstraintSystem) error {
+		z := rcs.ChallengeScalar("k-shuffle challenge")
+		minusZ := NewScalar().Neg(z)
+		px := shuffleProduct(rcs, x, minusZ)
+		py := shuffleProduct(rcs, y, minusZ)
+		rcs.Constraint(rcs.Sub(px, py))
+		return nil
+	})
+}
+
+// shuffleProduct returns the output of the multiplications of all (v_i - z)
+func shuffleProduct(cs ConstraintSystem, v []LinearCombination, minusZ Scalar) LinearCombination {
+	acc := cs.AddCst(v[0], minusZ)
+	for i := 1; i < len(v); i++ {
+		_, _, o := cs.Mul(acc, cs.AddCst(v[i], minusZ))
+		acc = o
+	}
+	return acc
+}
+
+// KShuffle is a gadget allocating two vectors of k values, X and Y, and
+// proving that Y is a permutation of X. The values are only set on the prover
+// side, the verifier only sets K.
+type KShuffle struct {
+	K int
+	X []Scalar
+	Y []Scalar
+}
+
+// Define allocates one multiplier per pair (x_i, y_i) and constrains the left
+// wires to be a permutation of the right wires. The prover must set K values
+// in both X and Y.
+func (s *KShuffle) Define(cs ConstraintSystem) error {
+	assigned := s.X != nil || s.Y != nil
+	if assigned && (len(s.X) != s.K || len(s.Y) != s.K) {
+		return fmt.Errorf("bproof: shuffle of %d values with %d and %d values", s.K, len(s.X), len(s.Y))
+	}
+	var xs, ys []LinearCombination
+	for i := 0; i < s.K; i++ {
+		var xi, yi Scalar
+		if assigned {
+			xi, yi = s.X[i], s.Y[i]
+		}
+		l, r, _ := cs.Allocate(xi, yi)
+		xs = append(xs, l)
+		ys = append(ys, r)
+	}
+	return Shuffle(cs, xs, ys)
+}
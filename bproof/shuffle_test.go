@@ -0,0 +1,100 @@
+package bproof
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func toScalars(vs []int64) []Scalar {
+	out := make([]Scalar, len(vs))
+	for i, v := range vs {
+		out[i] = NewScalar().SetInt64(v)
+	}
+	return out
+}
+
+// shuffleCircuit runs the shuffle gadget and the randomized phase on a prover
+func shuffleCircuit(t *testing.T, x, y []int64) *ProverCircuit {
+	c := NewProverCircuit()
+	gadget := &KShuffle{K: len(x), X: toScalars(x), Y: toScalars(y)}
+	require.NoError(t, gadget.Define(c))
+	rc := &randomizedProver{ProverCircuit: c, tr: transcript.New("test.shuffle")}
+	require.NoError(t, c.randomize(rc))
+	return c
+}
+
+var shuffleVectors = []struct {
+	x, y  []int64
+	valid bool
+}{
+	{[]int64{3}, []int64{3}, true},
+	{[]int64{3}, []int64{4}, false},
+	{[]int64{3, 4}, []int64{4, 3}, true},
+	{[]int64{3, 4}, []int64{3, 4}, true},
+	{[]int64{3, 4}, []int64{3, 3}, false},
+	{[]int64{1, 2, 3, 4, 5}, []int64{5, 3, 1, 4, 2}, true},
+	{[]int64{1, 2, 3, 4, 5}, []int64{5, 3, 1, 4, 4}, false},
+	{[]int64{1, 1, 2, 2}, []int64{2, 1, 2, 1}, true},
+	{[]int64{1, 1, 2, 2}, []int64{2, 1, 1, 1}, false},
+}
+
+func TestShuffleGadget(t *testing.T) {
+	for _, tv := range shuffleVectors {
+		c := shuffleCircuit(t, tv.x, tv.y)
+		require.Equal(t, tv.valid, c.isSatisfied(), "x = %v, y = %v", tv.x, tv.y)
+		k := len(tv.x)
+		if k > 1 {
+			// the first phase only contains the allocated values
+			require.Equal(t, k, c.nbPhaseOne)
+			// each product of k values costs k - 1 multipliers
+			require.Equal(t, k+2*(k-1), c.nbVars)
+		}
+	}
+}
+
+func TestShuffleProof(t *testing.T) {
+	gens := NewGenerators(16)
+	for _, tv := range shuffleVectors {
+		prover := NewProverCircuit()
+		gadget := &KShuffle{K: len(tv.x), X: toScalars(tv.x), Y: toScalars(tv.y)}
+		require.NoError(t, gadget.Define(prover))
+		proof, err := prover.Prove(gens, transcript.New("test.shuffle"))
+		require.NoError(t, err)
+
+		// the verifier only knows the size of the shuffle
+		verifier := NewVerifierCircuit()
+		require.NoError(t, (&KShuffle{K: len(tv.x)}).Define(verifier))
+		valid := verifier.Verify(proof, gens, transcript.New("test.shuffle"))
+		require.Equal(t, tv.valid, valid, "x = %v, y = %v", tv.x, tv.y)
+	}
+}
+
+func TestShuffleLengths(t *testing.T) {
+	x := toScalars([]int64{1, 2, 3})
+	for _, gadget := range []*KShuffle{
+		{K: 3, X: x, Y: x[:2]},
+		{K: 3, X: x[:2], Y: x},
+		{K: 2, X: x, Y: x},
+		{K: 3, X: x},
+	} {
+		require.Error(t, gadget.Define(NewProverCircuit()))
+	}
+}
+
+func TestShuffleRandomizedPhase(t *testing.T) {
+	c := NewProverCircuit()
+	var called bool
+	require.NoError(t, c.SpecifyRandomizedConstraints(func(rcs RandomizedConstraintSystem) error {
+		called = true
+		// no randomized constraints inside the second phase
+		require.Error(t, rcs.SpecifyRandomizedConstraints(nil))
+		return nil
+	}))
+	require.False(t, called)
+	rc := &randomizedProver{ProverCircuit: c, tr: transcript.New("test")}
+	require.NoError(t, c.randomize(rc))
+	require.True(t, called)
+	require.Error(t, c.randomize(rc))
+}
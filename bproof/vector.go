@@ -0,0 +1,97 @@
+package bproof
+
+// This file contains the small vector helpers used throughout the
+// Bulletproofs arguments: inner products, hadamard products, powers of a
+// scalar and multi exponentiations. None of them are optimized, they are
+// written to be read.
+
+// innerProduct returns <a,b> = SUM a_i * b_i
+func innerProduct(a, b []Scalar) Scalar {
+	if len(a) != len(b) {
+		panic("inner product of vectors of different length")
+	}
+	acc := zero()
+	for i := range a {
+		acc = acc.Add(acc, NewScalar().Mul(a[i], b[i]))
+	}
+	return acc
+}
+
+// hadamard returns the vector [a_0 * b_0, a_1 * b_1, ...]
+func hadamard(a, b []Scalar) []Scalar {
+	if len(a) != len(b) {
+		panic("hadamard product of vectors of different length")
+	}
+	out := make([]Scalar, len(a))
+	for i := range a {
+		out[i] = NewScalar().Mul(a[i], b[i])
+	}
+	return out
+}
+
+// addVec returns the vector [a_0 + b_0, a_1 + b_1, ...]
+func addVec(a, b []Scalar) []Scalar {
+	if len(a) != len(b) {
+		panic("addition of vectors of different length")
+	}
+	out := make([]Scalar, len(a))
+	for i := range a {
+		out[i] = NewScalar().Add(a[i], b[i])
+	}
+	return out
+}
+
+// scaleVec returns the vector [a_0 * s, a_1 * s, ...]
+func scaleVec(a []Scalar, s Scalar) []Scalar {
+	out := make([]Scalar, len(a))
+	for i := range a {
+		out[i] = NewScalar().Mul(a[i], s)
+	}
+	return out
+}
+
+// constVec returns a vector of length n where each entry is equal to s
+func constVec(s Scalar, n int) []Scalar {
+	out := make([]Scalar, n)
+	for i := range out {
+		out[i] = s.Clone()
+	}
+	return out
+}
+
+// powers returns [1, x, x^2, ..., x^(n-1)]
+func powers(x Scalar, n int) []Scalar {
+	out := make([]Scalar, n)
+	acc := one()
+	for i := 0; i < n; i++ {
+		out[i] = acc.Clone()
+		acc = acc.Mul(acc, x)
+	}
+	return out
+}
+
+// sumVec returns SUM a_i
+func sumVec(a []Scalar) Scalar {
+	acc := zero()
+	for _, s := range a {
+		acc = acc.Add(acc, s)
+	}
+	return acc
+}
+
+// multiExp returns SUM s_i * P_i
+func multiExp(scalars []Scalar, points []Point) Point {
+	if len(scalars) != len(points) {
+		panic("multi exponentiation with different number of scalars and points")
+	}
+	acc := NewPoint()
+	for i := range scalars {
+		acc = acc.Add(acc, NewPoint().Mul(scalars[i], points[i]))
+	}
+	return acc
+}
+
+// isPowerOfTwo returns true if n is a non zero power of two
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
@@ -0,0 +1,28 @@
+package bproof
+
+// VerifierCircuit builds the same constraints as the prover but without
+// knowing any value. It only needs the structure of the circuit to verify a
+// proof.
+type VerifierCircuit struct {
+	Circuit
+	// commitments to the high level variables
+	V []Point
+}
+
+func NewVerifierCircuit() *VerifierCircuit {
+	return &VerifierCircuit{Circuit: *NewCircuit()}
+}
+
+// Allocate creates a new multiplier. The verifier does not know the
+// assignments so a and b are ignored and can be nil.
+func (c *VerifierCircuit) Allocate(a, b Scalar) (left Variable, right Variable, out Variable) {
+	return c.newMultiplier()
+}
+
+// Commit returns the variable referencing the value committed in V by the
+// prover. The verifier must commit to the same commitments in the same order
+// as the prover.
+func (c *VerifierCircuit) Commit(V Point) Variable {
+	c.V = append(c.V, V)
+	return c.newCommitted()
+}
@@ -0,0 +1,409 @@
+// Package circuit compiles circuits written in a small language to R1CS and
+// computes their witness. A circuit declares its inputs and outputs and
+// constrains them with arithmetic expressions:
+//
+//	input x
+//	output out
+//	out == x*x*x + x + 5
+//
+// A statement is either a declaration, an assignment "y = expr" which
+// defines the intermediate variable y, or an assertion "expr == expr".
+// Statements end with a newline or a semicolon and comments start with "#".
+// An output is assigned by the first assertion where it appears alone on one
+// side. The expressions contain numbers, variables, "+", "-", "*", "^" with a
+// constant exponent and parentheses. The constants and the values of the
+// witness are integers, an overflow is an error.
+//
+// The compiler flattens the expressions: additions and multiplications by
+// constants are free in a R1CS, so it allocates an intermediate variable for
+// each multiplication of two variables only.
+package circuit
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/nikkolasg/playsnark"
+)
+
+// maxExponent bounds the exponents since x^n takes n-1 constraints
+const maxExponent = 1024
+
+type lc = playsnark.LinearCombination
+
+// step computes the value of a variable during the witness generation, as
+// the product of two linear combinations or as a single one if b is nil
+type step struct {
+	pos  Pos
+	name string
+	a, b lc
+}
+
+// constraint is a constraint of the R1CS along with the statement it comes
+// from
+type constraint struct {
+	pos     Pos
+	a, b, c lc
+}
+
+// Circuit is a compiled circuit
+type Circuit struct {
+	r1cs          playsnark.R1CS
+	inputs        []string
+	outputs       []string
+	intermediates []string
+	steps         []step
+	constraints   []constraint
+}
+
+// R1CS returns the R1CS of the circuit. Its intermediate variables are the
+// assigned ones and the ones allocated by the compiler, named "_t1", "_t2",
+// ...
+func (c *Circuit) R1CS() playsnark.R1CS {
+	return c.r1cs
+}
+
+// Compile parses the source and compiles it. The errors are of type *Error
+// and point to the source.
+func Compile(src string) (*Circuit, error) {
+	stmts, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+	cp := &compiler{
+		declared: map[string]Pos{},
+		assigned: map[string]bool{},
+		outputs:  map[string]bool{},
+	}
+	for _, stmt := range stmts {
+		if err := cp.statement(stmt); err != nil {
+			return nil, err
+		}
+	}
+	for _, out := range cp.c.outputs {
+		if !cp.assigned[out] {
+			return nil, errorf(cp.declared[out], "output %q is never assigned", out)
+		}
+	}
+	if len(cp.c.constraints) == 0 {
+		return nil, errorf(Pos{Line: 1, Col: 1}, "circuit without constraints")
+	}
+	c := &cp.c
+	c.r1cs = playsnark.NewR1CS()
+	for _, name := range c.inputs {
+		c.r1cs.NewInput(name)
+	}
+	for _, name := range c.outputs {
+		c.r1cs.NewOutput(name)
+	}
+	for _, name := range c.intermediates {
+		c.r1cs.NewVar(name)
+	}
+	for _, cs := range c.constraints {
+		c.r1cs.AddConstraint(cs.a, cs.b, cs.c)
+	}
+	return c, nil
+}
+
+// Solve returns the solution of the R1CS for the values of the inputs, ordered
+// as its variables. It returns an error pointing to the source if an
+// assertion doesn't hold or if the value of a variable overflows a
+// playsnark.Value.
+func (c *Circuit) Solve(inputs map[string]playsnark.Value) (playsnark.Vector, error) {
+	values := map[string]playsnark.Value{"const": 1}
+	for _, name := range c.inputs {
+		v, ok := inputs[name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for input %q", name)
+		}
+		values[name] = v
+	}
+	if len(inputs) != len(c.inputs) {
+		for name := range inputs {
+			if _, ok := values[name]; !ok {
+				return nil, fmt.Errorf("%q is not an input", name)
+			}
+		}
+	}
+	// the values are computed on big integers, such that an overflow is
+	// detected instead of wrapping around
+	for _, s := range c.steps {
+		v := eval(s.a, values)
+		if s.b != nil {
+			v.Mul(v, eval(s.b, values))
+		}
+		value, ok := toValue(v)
+		if !ok {
+			return nil, errorf(s.pos, "value of %q overflows", s.name)
+		}
+		values[s.name] = value
+	}
+	for _, cs := range c.constraints {
+		left := eval(cs.a, values)
+		if left.Mul(left, eval(cs.b, values)).Cmp(eval(cs.c, values)) != 0 {
+			return nil, errorf(cs.pos, "assertion does not hold")
+		}
+	}
+	return c.r1cs.Solution(values)
+}
+
+func eval(l lc, values map[string]playsnark.Value) *big.Int {
+	v := new(big.Int)
+	for name, coeff := range l {
+		t := big.NewInt(int64(coeff))
+		v.Add(v, t.Mul(t, big.NewInt(int64(values[name]))))
+	}
+	return v
+}
+
+// toValue returns the integer as a playsnark.Value and false if it doesn't fit
+func toValue(v *big.Int) (playsnark.Value, bool) {
+	if !v.IsInt64() || int64(playsnark.Value(v.Int64())) != v.Int64() {
+		return 0, false
+	}
+	return playsnark.Value(v.Int64()), true
+}
+
+type compiler struct {
+	c        Circuit
+	declared map[string]Pos
+	// assigned contains the variables whose value is known at this point of
+	// the witness generation
+	assigned map[string]bool
+	outputs  map[string]bool
+	nbTemps  int
+}
+
+func (cp *compiler) declare(name token) error {
+	if name.text == "const" {
+		return errorf(name.pos, "%q is reserved", name.text)
+	}
+	if pos, ok := cp.declared[name.text]; ok {
+		return errorf(name.pos, "%q already declared at %s", name.text, pos)
+	}
+	cp.declared[name.text] = name.pos
+	return nil
+}
+
+func (cp *compiler) statement(stmt statement) error {
+	switch stmt.kind {
+	case stmtInput, stmtOutput:
+		for _, name := range stmt.names {
+			if err := cp.declare(name); err != nil {
+				return err
+			}
+			if stmt.kind == stmtInput {
+				cp.c.inputs = append(cp.c.inputs, name.text)
+				cp.assigned[name.text] = true
+			} else {
+				cp.c.outputs = append(cp.c.outputs, name.text)
+				cp.outputs[name.text] = true
+			}
+		}
+		return nil
+	case stmtAssign:
+		name := stmt.names[0]
+		a, b, err := cp.flattenTop(stmt.right)
+		if err != nil {
+			return err
+		}
+		if err := cp.declare(name); err != nil {
+			return err
+		}
+		cp.c.intermediates = append(cp.c.intermediates, name.text)
+		cp.assign(stmt.pos, name.text, a, b)
+		return nil
+	}
+	// an output alone on one side of an assertion is assigned by the other
+	for _, sides := range [][2]expr{{stmt.left, stmt.right}, {stmt.right, stmt.left}} {
+		if id, ok := sides[0].(*identExpr); ok && cp.outputs[id.name] && !cp.assigned[id.name] {
+			a, b, err := cp.flattenTop(sides[1])
+			if err != nil {
+				return err
+			}
+			cp.assign(stmt.pos, id.name, a, b)
+			return nil
+		}
+	}
+	la, lb, err := cp.flattenTop(stmt.left)
+	if err != nil {
+		return err
+	}
+	ra, rb, err := cp.flattenTop(stmt.right)
+	if err != nil {
+		return err
+	}
+	// a single constraint suffices unless both sides are products
+	if rb == nil {
+		cp.constrain(stmt.pos, la, lb, ra)
+	} else {
+		cp.constrain(stmt.pos, ra, rb, cp.temp(stmt.left.position(), la, lb))
+	}
+	return nil
+}
+
+// assign adds the step computing the variable and its constraint
+func (cp *compiler) assign(pos Pos, name string, a, b lc) {
+	cp.c.steps = append(cp.c.steps, step{pos: pos, name: name, a: a, b: b})
+	cp.assigned[name] = true
+	cp.constrain(pos, a, b, lc{name: 1})
+}
+
+// constrain adds the constraint a * b = c, or a = c if b is nil
+func (cp *compiler) constrain(pos Pos, a, b, c lc) {
+	if b == nil {
+		b = lc{"const": 1}
+	}
+	cp.c.constraints = append(cp.c.constraints, constraint{pos: pos, a: a, b: b, c: c})
+}
+
+// flatten returns the expression as a linear combination
+func (cp *compiler) flatten(e expr) (lc, error) {
+	a, b, err := cp.flattenTop(e)
+	if err != nil {
+		return nil, err
+	}
+	return cp.temp(e.position(), a, b), nil
+}
+
+// temp returns a * b as a linear combination, or a if b is nil, allocating an
+// intermediate variable for the product
+func (cp *compiler) temp(pos Pos, a, b lc) lc {
+	if b == nil {
+		return a
+	}
+	cp.nbTemps++
+	name := fmt.Sprintf("_t%d", cp.nbTemps)
+	cp.c.intermediates = append(cp.c.intermediates, name)
+	cp.assign(pos, name, a, b)
+	return lc{name: 1}
+}
+
+// flattenTop returns the expression as the product of two linear
+// combinations, or as a single one with b nil. The sub expressions are
+// flattened.
+func (cp *compiler) flattenTop(e expr) (a, b lc, err error) {
+	switch e := e.(type) {
+	case *numberExpr:
+		return lc{"const": e.value}, nil, nil
+	case *identExpr:
+		if _, ok := cp.declared[e.name]; !ok {
+			return nil, nil, errorf(e.pos, "unknown variable %q", e.name)
+		}
+		if !cp.assigned[e.name] {
+			return nil, nil, errorf(e.pos, "output %q used before being assigned", e.name)
+		}
+		return lc{e.name: 1}, nil, nil
+	case *negExpr:
+		a, err := cp.flatten(e.e)
+		if err != nil {
+			return nil, nil, err
+		}
+		a, err = scale(e.pos, a, -1)
+		return a, nil, err
+	case *powExpr:
+		if e.n > maxExponent {
+			return nil, nil, errorf(e.pos, "exponent %d larger than %d", e.n, maxExponent)
+		}
+		if e.n == 0 {
+			return lc{"const": 1}, nil, nil
+		}
+		base, err := cp.flatten(e.e)
+		if err != nil || e.n == 1 {
+			return base, nil, err
+		}
+		acc := base
+		for i := int64(2); i < e.n; i++ {
+			a, b, err := cp.productTop(e.pos, acc, base)
+			if err != nil {
+				return nil, nil, err
+			}
+			acc = cp.temp(e.pos, a, b)
+		}
+		return cp.productTop(e.pos, acc, base)
+	case *binaryExpr:
+		left, err := cp.flatten(e.left)
+		if err != nil {
+			return nil, nil, err
+		}
+		right, err := cp.flatten(e.right)
+		if err != nil {
+			return nil, nil, err
+		}
+		switch e.op {
+		case tokPlus:
+			a, err := add(e.pos, left, right, 1)
+			return a, nil, err
+		case tokMinus:
+			a, err := add(e.pos, left, right, -1)
+			return a, nil, err
+		}
+		return cp.productTop(e.pos, left, right)
+	}
+	panic("unknown expression")
+}
+
+// productTop returns a * b, as a single linear combination if one of them is
+// a constant
+func (cp *compiler) productTop(pos Pos, a, b lc) (lc, lc, error) {
+	if k, ok := constant(a); ok {
+		res, err := scale(pos, b, k)
+		return res, nil, err
+	}
+	if k, ok := constant(b); ok {
+		res, err := scale(pos, a, k)
+		return res, nil, err
+	}
+	return a, b, nil
+}
+
+// add returns a + sign * b and an error if a coefficient overflows
+func add(pos Pos, a, b lc, sign playsnark.Value) (lc, error) {
+	res := lc{}
+	for name, coeff := range a {
+		res[name] = coeff
+	}
+	for name, coeff := range b {
+		v := big.NewInt(int64(sign))
+		v.Mul(v, big.NewInt(int64(coeff)))
+		v.Add(v, big.NewInt(int64(res[name])))
+		value, ok := toValue(v)
+		if !ok {
+			return nil, errorf(pos, "constant overflows")
+		}
+		res[name] = value
+	}
+	for name, coeff := range res {
+		if coeff == 0 {
+			delete(res, name)
+		}
+	}
+	return res, nil
+}
+
+// scale returns k * a and an error if a coefficient overflows
+func scale(pos Pos, a lc, k playsnark.Value) (lc, error) {
+	res := lc{}
+	if k == 0 {
+		return res, nil
+	}
+	for name, coeff := range a {
+		v := big.NewInt(int64(k))
+		value, ok := toValue(v.Mul(v, big.NewInt(int64(coeff))))
+		if !ok {
+			return nil, errorf(pos, "constant overflows")
+		}
+		res[name] = value
+	}
+	return res, nil
+}
+
+// constant returns the value of the linear combination if it is a constant
+func constant(a lc) (playsnark.Value, bool) {
+	for name := range a {
+		if name != "const" {
+			return 0, false
+		}
+	}
+	return a["const"], true
+}
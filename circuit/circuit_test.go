@@ -0,0 +1,145 @@
+package circuit
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/stretchr/testify/require"
+)
+
+const cubic = `
+# x^3 + x + 5 = out
+input x
+output out
+out == x*x*x + x + 5
+`
+
+func TestCompileCubic(t *testing.T) {
+	c, err := Compile(cubic)
+	require.NoError(t, err)
+	r1cs := c.R1CS()
+	// x*x and (x*x)*x are the only multiplications, the sum is free but needs
+	// one constraint to assign the output
+	require.Equal(t, 3, r1cs.NbConstraints())
+	require.Equal(t, []string{"x"}, r1cs.Inputs())
+	require.Equal(t, []string{"out"}, r1cs.Outputs())
+	require.Equal(t, []string{"_t1", "_t2"}, r1cs.Intermediates())
+
+	sol, err := c.Solve(map[string]playsnark.Value{"x": 3})
+	require.NoError(t, err)
+	require.Equal(t, playsnark.Vector{1, 3, 35, 9, 27}, sol)
+	qap := playsnark.ToQAP(r1cs)
+	require.True(t, qap.IsValid(sol))
+
+	_, err = c.Solve(map[string]playsnark.Value{})
+	require.EqualError(t, err, `missing value for input "x"`)
+	_, err = c.Solve(map[string]playsnark.Value{"x": 3, "y": 4})
+	require.EqualError(t, err, `"y" is not an input`)
+}
+
+func TestCompileProof(t *testing.T) {
+	c, err := Compile(cubic)
+	require.NoError(t, err)
+	sol, err := c.Solve(map[string]playsnark.Value{"x": 3})
+	require.NoError(t, err)
+	r1cs := c.R1CS()
+	io, err := r1cs.PublicValues(map[string]playsnark.Value{"x": 3, "out": 35})
+	require.NoError(t, err)
+	for _, b := range playsnark.Backends {
+		pk, vk := b.Setup(playsnark.ToQAP(r1cs))
+		proof, err := pk.Prove(sol)
+		require.NoError(t, err)
+		require.True(t, vk.Verify(proof, io))
+	}
+}
+
+func TestCompileExpressions(t *testing.T) {
+	var tests = []struct {
+		name        string
+		src         string
+		inputs      map[string]playsnark.Value
+		outputs     map[string]playsnark.Value
+		constraints int
+	}{
+		{"Power", "input x; output y; y == x^3", map[string]playsnark.Value{"x": 2}, map[string]playsnark.Value{"y": 8}, 2},
+		{"PowerZero", "input x; output y; y == x^0 + x^1", map[string]playsnark.Value{"x": 5}, map[string]playsnark.Value{"y": 6}, 1},
+		{"Constants", "input x; output y; y == 3 * (x - 1) * 2 - -x", map[string]playsnark.Value{"x": 4}, map[string]playsnark.Value{"y": 22}, 1},
+		{"Assignment", "input x, y\noutput out\nz = x * y\nout == z * z + z", map[string]playsnark.Value{"x": 2, "y": 3}, map[string]playsnark.Value{"out": 42}, 3},
+		{"OutputOnTheRight", "input x; output y; x * x == y", map[string]playsnark.Value{"x": 7}, map[string]playsnark.Value{"y": 49}, 1},
+		{"Assertion", "input x, y; output out; x * y == 12; out == x + y", map[string]playsnark.Value{"x": 3, "y": 4}, map[string]playsnark.Value{"out": 7}, 2},
+		{"Outputs", "input x; output a, b; a == x * x; b == a * x", map[string]playsnark.Value{"x": 3}, map[string]playsnark.Value{"a": 9, "b": 27}, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := Compile(test.src)
+			require.NoError(t, err)
+			r1cs := c.R1CS()
+			require.Equal(t, test.constraints, r1cs.NbConstraints())
+			sol, err := c.Solve(test.inputs)
+			require.NoError(t, err)
+			qap := playsnark.ToQAP(r1cs)
+			require.True(t, qap.IsValid(sol))
+			values := map[string]playsnark.Value{}
+			for k, v := range test.inputs {
+				values[k] = v
+			}
+			for k, v := range test.outputs {
+				values[k] = v
+			}
+			io, err := r1cs.PublicValues(values)
+			require.NoError(t, err)
+			require.Equal(t, io, sol[:len(io)])
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	var tests = []struct {
+		src string
+		err string
+	}{
+		{"input x\noutput out\nout == x * y", `line 3:12: unknown variable "y"`},
+		{"input x\ninput x", `line 2:7: "x" already declared at line 1:7`},
+		{"input const", `line 1:7: "const" is reserved`},
+		{"input x\nx = x * x", `line 2:1: "x" already declared at line 1:7`},
+		{"input x\noutput out\ny = out * x\nout == x", `line 3:5: output "out" used before being assigned`},
+		{"input x\noutput out, y\ny == x", `line 2:8: output "out" is never assigned`},
+		{"input x\noutput y\ny == x^2000", `line 3:7: exponent 2000 larger than 1024`},
+		{"input x", "line 1:1: circuit without constraints"},
+		{"input x\noutput y\ny == x * 2^70", "line 3:11: constant overflows"},
+		{"input x\noutput y\ny == x * 2^62 + x * 2^62", "line 3:15: constant overflows"},
+	}
+	for _, test := range tests {
+		_, err := Compile(test.src)
+		require.EqualError(t, err, test.err, test.src)
+		require.IsType(t, &Error{}, err)
+	}
+}
+
+func TestSolveAssertion(t *testing.T) {
+	c, err := Compile("input x, y\noutput out\nout == x + y\n\nx * y == 12")
+	require.NoError(t, err)
+	_, err = c.Solve(map[string]playsnark.Value{"x": 3, "y": 4})
+	require.NoError(t, err)
+	_, err = c.Solve(map[string]playsnark.Value{"x": 2, "y": 4})
+	require.EqualError(t, err, "line 5:1: assertion does not hold")
+}
+
+func TestSolveOverflow(t *testing.T) {
+	c, err := Compile("input x\noutput y\ny == x^50")
+	require.NoError(t, err)
+	sol, err := c.Solve(map[string]playsnark.Value{"x": 1})
+	require.NoError(t, err)
+	qap := playsnark.ToQAP(c.R1CS())
+	require.True(t, qap.IsValid(sol))
+	// 3^40 doesn't fit in a playsnark.Value
+	_, err = c.Solve(map[string]playsnark.Value{"x": 3})
+	require.EqualError(t, err, `line 3:7: value of "_t39" overflows`)
+	require.IsType(t, &Error{}, err)
+
+	// an assertion can't hold because its product wraps around
+	c, err = Compile("input x\noutput y\ny == x + 1\nx * x == 0")
+	require.NoError(t, err)
+	_, err = c.Solve(map[string]playsnark.Value{"x": 1 << 32})
+	require.EqualError(t, err, "line 4:1: assertion does not hold")
+}
@@ -0,0 +1,170 @@
+package circuit
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	// tokEnd ends a statement: a newline or a semicolon
+	tokEnd
+	tokIdent
+	tokNumber
+	tokInput
+	tokOutput
+	tokPlus
+	tokMinus
+	tokStar
+	tokCaret
+	tokLParen
+	tokRParen
+	tokComma
+	tokAssign
+	tokEqual
+)
+
+var tokenNames = map[tokenKind]string{
+	tokEOF:    "end of file",
+	tokEnd:    "end of statement",
+	tokIdent:  "identifier",
+	tokNumber: "number",
+	tokInput:  `"input"`,
+	tokOutput: `"output"`,
+	tokPlus:   `"+"`,
+	tokMinus:  `"-"`,
+	tokStar:   `"*"`,
+	tokCaret:  `"^"`,
+	tokLParen: `"("`,
+	tokRParen: `")"`,
+	tokComma:  `","`,
+	tokAssign: `"="`,
+	tokEqual:  `"=="`,
+}
+
+func (k tokenKind) String() string {
+	return tokenNames[k]
+}
+
+var keywords = map[string]tokenKind{
+	"input":  tokInput,
+	"output": tokOutput,
+}
+
+// Pos is a position in the source, starting at line 1 column 1
+type Pos struct {
+	Line int
+	Col  int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("line %d:%d", p.Line, p.Col)
+}
+
+// Error is an error in the source of a circuit
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return e.Pos.String() + ": " + e.Msg
+}
+
+func errorf(pos Pos, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value int64
+	pos   Pos
+}
+
+// lex splits the source into tokens. Comments start with "#" and end with the
+// line.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+	pos := Pos{Line: 1, Col: 1}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := pos
+		advance := func(n int) {
+			i += n
+			pos.Col += n
+		}
+		switch {
+		case r == '\n':
+			tokens = append(tokens, token{kind: tokEnd, text: "\n", pos: start})
+			i++
+			pos = Pos{Line: pos.Line + 1, Col: 1}
+		case r == '#':
+			for i < len(runes) && runes[i] != '\n' {
+				advance(1)
+			}
+		case unicode.IsSpace(r):
+			advance(1)
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			text := string(runes[i:j])
+			kind, ok := keywords[text]
+			if !ok {
+				kind = tokIdent
+			}
+			tokens = append(tokens, token{kind: kind, text: text, pos: start})
+			advance(j - i)
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			text := string(runes[i:j])
+			v, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return nil, errorf(start, "invalid number %s", text)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, value: v, pos: start})
+			advance(j - i)
+		default:
+			var kind tokenKind
+			n := 1
+			switch r {
+			case ';':
+				kind = tokEnd
+			case '+':
+				kind = tokPlus
+			case '-':
+				kind = tokMinus
+			case '*':
+				kind = tokStar
+			case '^':
+				kind = tokCaret
+			case '(':
+				kind = tokLParen
+			case ')':
+				kind = tokRParen
+			case ',':
+				kind = tokComma
+			case '=':
+				kind = tokAssign
+				if i+1 < len(runes) && runes[i+1] == '=' {
+					kind = tokEqual
+					n = 2
+				}
+			default:
+				return nil, errorf(start, "unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[i : i+n]), pos: start})
+			advance(n)
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: pos}), nil
+}
@@ -0,0 +1,247 @@
+package circuit
+
+import "github.com/nikkolasg/playsnark"
+
+// expr is a node of the syntax tree of an expression
+type expr interface {
+	position() Pos
+}
+
+type numberExpr struct {
+	pos   Pos
+	value playsnark.Value
+}
+
+type identExpr struct {
+	pos  Pos
+	name string
+}
+
+// binaryExpr is an addition, a subtraction or a multiplication
+type binaryExpr struct {
+	pos         Pos
+	op          tokenKind
+	left, right expr
+}
+
+type negExpr struct {
+	pos Pos
+	e   expr
+}
+
+// powExpr raises the expression to a constant power
+type powExpr struct {
+	pos Pos
+	e   expr
+	n   int64
+}
+
+func (e *numberExpr) position() Pos { return e.pos }
+func (e *identExpr) position() Pos  { return e.pos }
+func (e *binaryExpr) position() Pos { return e.pos }
+func (e *negExpr) position() Pos    { return e.pos }
+func (e *powExpr) position() Pos    { return e.pos }
+
+type stmtKind int
+
+const (
+	// input x, y
+	stmtInput stmtKind = iota
+	// output out
+	stmtOutput
+	// y = expr
+	stmtAssign
+	// expr == expr
+	stmtAssert
+)
+
+type statement struct {
+	kind stmtKind
+	pos  Pos
+	// names are the declared variables, or the assigned one
+	names []token
+	left  expr
+	right expr
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	program   = { statement end }
+//	statement = ("input" | "output") ident { "," ident }
+//	          | ident "=" expr
+//	          | expr "==" expr
+//	expr      = term { ("+" | "-") term }
+//	term      = unary { "*" unary }
+//	unary     = "-" unary | factor
+//	factor    = primary [ "^" number ]
+//	primary   = number | ident | "(" expr ")"
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func parse(src string) ([]statement, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	var stmts []statement
+	for {
+		for p.peek().kind == tokEnd {
+			p.next()
+		}
+		if p.peek().kind == tokEOF {
+			return stmts, nil
+		}
+		stmt, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+		if t := p.peek(); t.kind != tokEnd && t.kind != tokEOF {
+			return nil, errorf(t.pos, "unexpected %s %q after statement", t.kind, t.text)
+		}
+	}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	t := p.next()
+	if t.kind != kind {
+		return t, unexpected(t, kind.String())
+	}
+	return t, nil
+}
+
+func unexpected(t token, expected string) error {
+	if t.text == "" || t.kind == tokEnd {
+		return errorf(t.pos, "expected %s, got %s", expected, t.kind)
+	}
+	return errorf(t.pos, "expected %s, got %q", expected, t.text)
+}
+
+func (p *parser) statement() (statement, error) {
+	start := p.peek()
+	switch start.kind {
+	case tokInput, tokOutput:
+		p.next()
+		stmt := statement{kind: stmtInput, pos: start.pos}
+		if start.kind == tokOutput {
+			stmt.kind = stmtOutput
+		}
+		for {
+			name, err := p.expect(tokIdent)
+			if err != nil {
+				return stmt, err
+			}
+			stmt.names = append(stmt.names, name)
+			if p.peek().kind != tokComma {
+				return stmt, nil
+			}
+			p.next()
+		}
+	case tokIdent:
+		if p.tokens[p.i+1].kind == tokAssign {
+			p.next()
+			p.next()
+			right, err := p.expr()
+			return statement{kind: stmtAssign, pos: start.pos, names: []token{start}, right: right}, err
+		}
+	}
+	left, err := p.expr()
+	if err != nil {
+		return statement{}, err
+	}
+	if _, err := p.expect(tokEqual); err != nil {
+		return statement{}, err
+	}
+	right, err := p.expr()
+	return statement{kind: stmtAssert, pos: start.pos, left: left, right: right}, err
+}
+
+func (p *parser) expr() (expr, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokPlus || p.peek().kind == tokMinus {
+		op := p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{pos: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (expr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokStar {
+		op := p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{pos: op.pos, op: op.kind, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (expr, error) {
+	if t := p.peek(); t.kind == tokMinus {
+		p.next()
+		e, err := p.unary()
+		return &negExpr{pos: t.pos, e: e}, err
+	}
+	return p.factor()
+}
+
+func (p *parser) factor() (expr, error) {
+	e, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind == tokCaret {
+		p.next()
+		n, err := p.expect(tokNumber)
+		if err != nil {
+			return nil, err
+		}
+		return &powExpr{pos: t.pos, e: e, n: n.value}, nil
+	}
+	return e, nil
+}
+
+func (p *parser) primary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &numberExpr{pos: t.pos, value: playsnark.Value(t.value)}, nil
+	case tokIdent:
+		return &identExpr{pos: t.pos, name: t.text}, nil
+	case tokLParen:
+		e, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		_, err = p.expect(tokRParen)
+		return e, err
+	}
+	return nil, unexpected(t, "expression")
+}
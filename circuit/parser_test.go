@@ -0,0 +1,56 @@
+package circuit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := lex("input x # the input\nout == x^2;")
+	require.NoError(t, err)
+	var kinds []tokenKind
+	for _, tok := range tokens {
+		kinds = append(kinds, tok.kind)
+	}
+	require.Equal(t, []tokenKind{tokInput, tokIdent, tokEnd, tokIdent, tokEqual, tokIdent, tokCaret, tokNumber, tokEnd, tokEOF}, kinds)
+	require.Equal(t, Pos{Line: 2, Col: 5}, tokens[4].pos)
+	require.Equal(t, int64(2), tokens[7].value)
+
+	_, err = lex("input x\nout == x / 2")
+	require.EqualError(t, err, `line 2:10: unexpected character '/'`)
+}
+
+func TestParse(t *testing.T) {
+	stmts, err := parse("input x, y\n\noutput out\nz = -(x + 1) * y\nout == z^3 - 2")
+	require.NoError(t, err)
+	require.Len(t, stmts, 4)
+	require.Equal(t, stmtInput, stmts[0].kind)
+	require.Len(t, stmts[0].names, 2)
+	require.Equal(t, stmtAssign, stmts[2].kind)
+	require.Equal(t, "z", stmts[2].names[0].text)
+	// the multiplication binds tighter than the negation of the parentheses
+	mul := stmts[2].right.(*binaryExpr)
+	require.Equal(t, tokStar, mul.op)
+	require.IsType(t, &negExpr{}, mul.left)
+	require.Equal(t, Pos{Line: 5, Col: 1}, stmts[3].pos)
+	sub := stmts[3].right.(*binaryExpr)
+	require.Equal(t, tokMinus, sub.op)
+	require.Equal(t, int64(3), sub.left.(*powExpr).n)
+
+	var errors = []struct {
+		src string
+		err string
+	}{
+		{"input", "line 1:6: expected identifier, got end of file"},
+		{"input x\nx + 1", `line 2:6: expected "==", got end of file`},
+		{"input x\nout == (x + 1", `line 2:14: expected ")", got end of file`},
+		{"out == x * * 2", `line 1:12: expected expression, got "*"`},
+		{"out == x^y", `line 1:10: expected number, got "y"`},
+		{"out == x x", `line 1:10: unexpected identifier "x" after statement`},
+	}
+	for _, e := range errors {
+		_, err := parse(e.src)
+		require.EqualError(t, err, e.err, e.src)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nikkolasg/playsnark"
+	lang "github.com/nikkolasg/playsnark/circuit"
+)
+
+// compileSource compiles the JSON description of a circuit, or its source in
+// the language of the circuit package
+func compileSource(buff []byte) (playsnark.R1CS, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(buff), []byte("{")) {
+		c, err := lang.Compile(string(buff))
+		if err != nil {
+			return playsnark.R1CS{}, err
+		}
+		return c.R1CS(), nil
+	}
+	desc, err := parseCircuit(buff)
+	if err != nil {
+		return playsnark.R1CS{}, err
+	}
+	return desc.compile()
+}
+
+// circuit is the description of a circuit compiled by the compile command.
+// Each constraint is one of the gates of the R1CS:
+//
+//	{"op": "mul", "left": "x", "right": "x", "out": "u"}       u = x * x
+//	{"op": "add", "left": "v", "right": "x", "out": "w"}       w = v + x
+//	{"op": "addconst", "left": "w", "const": 5, "out": "out"}  out = w + 5
+type circuit struct {
+	Inputs      []string     `json:"inputs"`
+	Outputs     []string     `json:"outputs"`
+	Variables   []string     `json:"variables"`
+	Constraints []constraint `json:"constraints"`
+}
+
+type constraint struct {
+	Op    string `json:"op"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+	Const int    `json:"const"`
+	Out   string `json:"out"`
+}
+
+// parseCircuit decodes the circuit description
+func parseCircuit(buff []byte) (circuit, error) {
+	var c circuit
+	if err := json.Unmarshal(buff, &c); err != nil {
+		return c, fmt.Errorf("invalid circuit: %w", err)
+	}
+	return c, nil
+}
+
+// compile returns the R1CS of the circuit, checking that all the variables are
+// declared once
+func (c circuit) compile() (playsnark.R1CS, error) {
+	r := playsnark.NewR1CS()
+	declared := map[string]bool{"const": true}
+	for _, names := range []struct {
+		add  func(string)
+		list []string
+	}{{r.NewInput, c.Inputs}, {r.NewOutput, c.Outputs}, {r.NewVar, c.Variables}} {
+		for _, name := range names.list {
+			if declared[name] {
+				return r, fmt.Errorf("variable %q declared twice", name)
+			}
+			declared[name] = true
+			names.add(name)
+		}
+	}
+	if len(c.Constraints) == 0 {
+		return r, fmt.Errorf("circuit without constraints")
+	}
+	for i, cs := range c.Constraints {
+		names := []string{cs.Left, cs.Out}
+		if cs.Op != "addconst" {
+			names = append(names, cs.Right)
+		}
+		for _, name := range names {
+			if !declared[name] {
+				return r, fmt.Errorf("constraint %d: unknown variable %q", i, name)
+			}
+		}
+		switch cs.Op {
+		case "mul":
+			r.Mul(cs.Left, cs.Right, cs.Out)
+		case "add":
+			r.Add(cs.Left, cs.Right, cs.Out)
+		case "addconst":
+			r.AddConst(cs.Left, cs.Const, cs.Out)
+		default:
+			return r, fmt.Errorf("constraint %d: unknown operation %q", i, cs.Op)
+		}
+	}
+	return r, nil
+}
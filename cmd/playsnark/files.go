@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/nikkolasg/playsnark"
+)
+
+// kinds of the files written in an envelope
+const (
+	provingKeyKind   = "proving key"
+	verifyingKeyKind = "verifying key"
+	proofKind        = "proof"
+)
+
+// envelope is the JSON file containing a marshalled key or proof, along with
+// the backend and the curve needed to read it
+type envelope struct {
+	Kind    string `json:"kind"`
+	Backend string `json:"backend"`
+	Curve   string `json:"curve"`
+	Data    []byte `json:"data"`
+}
+
+func writeJSON(path string, v interface{}) error {
+	buff, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(buff, '\n'), 0644)
+}
+
+func writeEnvelope(path, kind, backend, curve string, m encoding.BinaryMarshaler) error {
+	buff, err := m.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeJSON(path, envelope{Kind: kind, Backend: backend, Curve: curve, Data: buff})
+}
+
+// readEnvelope reads the envelope of the given kind and returns its backend
+// and the QAP of the R1CS on its curve
+func readEnvelope(path, kind string, r1cs playsnark.R1CS) (envelope, playsnark.Backend, playsnark.QAP, error) {
+	var env envelope
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return env, nil, playsnark.QAP{}, err
+	}
+	if err := json.Unmarshal(buff, &env); err != nil {
+		return env, nil, playsnark.QAP{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if env.Kind != kind {
+		return env, nil, playsnark.QAP{}, fmt.Errorf("%s: %q file instead of %q", path, env.Kind, kind)
+	}
+	b, err := playsnark.BackendByName(env.Backend)
+	if err != nil {
+		return env, nil, playsnark.QAP{}, fmt.Errorf("%s: %w", path, err)
+	}
+	c, err := playsnark.CurveByName(env.Curve)
+	if err != nil {
+		return env, nil, playsnark.QAP{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return env, b, playsnark.ToQAPWith(c, r1cs), nil
+}
+
+func readR1CS(path string) (playsnark.R1CS, error) {
+	var r1cs playsnark.R1CS
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return r1cs, err
+	}
+	if err := json.Unmarshal(buff, &r1cs); err != nil {
+		return r1cs, fmt.Errorf("%s: %w", path, err)
+	}
+	return r1cs, nil
+}
+
+// readValues reads the values of the variables by name
+func readValues(path string) (map[string]playsnark.Value, error) {
+	var values map[string]playsnark.Value
+	buff, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(buff, &values); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
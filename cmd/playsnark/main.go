@@ -0,0 +1,264 @@
+// Command playsnark compiles circuits, runs the trusted setup and proves and
+// verifies statements with the proof systems of the playsnark package.
+//
+//	playsnark compile -o circuit.r1cs cubic.circuit
+//	playsnark setup -backend groth16 -curve BN254 circuit.r1cs
+//	playsnark prove -witness witness.json circuit.r1cs
+//	playsnark verify -public public.json circuit.r1cs
+//	playsnark inspect circuit.r1cs proving.key verifying.key proof.json
+//
+// The circuits are written in the language of the circuit package, or
+// described gate by gate in JSON. The witness contains the values of all the
+// variables by name and the public file the values of the inputs and outputs.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/nikkolasg/playsnark"
+)
+
+const usage = `usage: playsnark <command> [flags] [files]
+
+commands:
+  compile   compile a circuit source or JSON description to a R1CS
+  setup     run the trusted setup of a R1CS and write the keys
+  prove     prove a witness of a R1CS with the proving key
+  verify    verify a proof with the verifying key and the public values
+  inspect   describe R1CS, key and proof files
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// errInvalidProof is returned by the verify command when the proof doesn't
+// verify
+var errInvalidProof = errors.New("proof is invalid")
+
+// run executes the command and returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	c := &cli{stdout: stdout, stderr: stderr}
+	commands := map[string]func([]string) error{
+		"compile": c.compile,
+		"setup":   c.setup,
+		"prove":   c.prove,
+		"verify":  c.verify,
+		"inspect": c.inspect,
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "playsnark: unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+	if err := cmd(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "playsnark %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// cli runs the commands, writing to its outputs
+type cli struct {
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	return fs
+}
+
+// parseFlags parses the flags of the command, which expects nbArgs files
+func parseFlags(fs *flag.FlagSet, args []string, nbArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if nbArgs >= 0 && fs.NArg() != nbArgs {
+		return fmt.Errorf("expected %d file(s), got %d", nbArgs, fs.NArg())
+	}
+	return nil
+}
+
+func (c *cli) compile(args []string) error {
+	fs := c.flags("compile")
+	out := fs.String("o", "circuit.r1cs", "output R1CS file")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	buff, err := ioutil.ReadFile(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	r1cs, err := compileSource(buff)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(*out, r1cs); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "wrote %s: %d constraints\n", *out, r1cs.NbConstraints())
+	return nil
+}
+
+func (c *cli) setup(args []string) error {
+	fs := c.flags("setup")
+	backendName := fs.String("backend", playsnark.Groth16.Name(), "proof system: groth16 or phgr13")
+	curveName := fs.String("curve", playsnark.BLS12381.Name, "curve: BLS12-381 or BN254")
+	pkPath := fs.String("pk", "proving.key", "output proving key file")
+	vkPath := fs.String("vk", "verifying.key", "output verifying key file")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	backend, err := playsnark.BackendByName(*backendName)
+	if err != nil {
+		return err
+	}
+	curve, err := playsnark.CurveByName(*curveName)
+	if err != nil {
+		return err
+	}
+	r1cs, err := readR1CS(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	pk, vk := backend.Setup(playsnark.ToQAPWith(curve, r1cs))
+	if err := writeEnvelope(*pkPath, provingKeyKind, backend.Name(), curve.Name, pk); err != nil {
+		return err
+	}
+	if err := writeEnvelope(*vkPath, verifyingKeyKind, backend.Name(), curve.Name, vk); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "wrote %s and %s for %s on %s\n", *pkPath, *vkPath, backend.Name(), curve.Name)
+	return nil
+}
+
+func (c *cli) prove(args []string) error {
+	fs := c.flags("prove")
+	pkPath := fs.String("pk", "proving.key", "proving key file")
+	witnessPath := fs.String("witness", "witness.json", "values of all the variables")
+	out := fs.String("o", "proof.json", "output proof file")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	r1cs, err := readR1CS(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	env, backend, qap, err := readEnvelope(*pkPath, provingKeyKind, r1cs)
+	if err != nil {
+		return err
+	}
+	pk, err := backend.ProvingKey(qap, env.Data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *pkPath, err)
+	}
+	values, err := readValues(*witnessPath)
+	if err != nil {
+		return err
+	}
+	sol, err := r1cs.Solution(values)
+	if err != nil {
+		return err
+	}
+	proof, err := pk.Prove(sol)
+	if err != nil {
+		return err
+	}
+	if err := writeEnvelope(*out, proofKind, env.Backend, env.Curve, proof); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stdout, "wrote %s\n", *out)
+	return nil
+}
+
+func (c *cli) verify(args []string) error {
+	fs := c.flags("verify")
+	vkPath := fs.String("vk", "verifying.key", "verifying key file")
+	proofPath := fs.String("proof", "proof.json", "proof file")
+	publicPath := fs.String("public", "public.json", "values of the inputs and outputs")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	r1cs, err := readR1CS(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	env, backend, qap, err := readEnvelope(*vkPath, verifyingKeyKind, r1cs)
+	if err != nil {
+		return err
+	}
+	vk, err := backend.VerifyingKey(qap, env.Data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *vkPath, err)
+	}
+	proofEnv, _, _, err := readEnvelope(*proofPath, proofKind, r1cs)
+	if err != nil {
+		return err
+	}
+	if proofEnv.Backend != env.Backend || proofEnv.Curve != env.Curve {
+		return fmt.Errorf("proof for %s on %s but key for %s on %s",
+			proofEnv.Backend, proofEnv.Curve, env.Backend, env.Curve)
+	}
+	proof, err := backend.Proof(qap, proofEnv.Data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", *proofPath, err)
+	}
+	values, err := readValues(*publicPath)
+	if err != nil {
+		return err
+	}
+	public, err := r1cs.PublicValues(values)
+	if err != nil {
+		return err
+	}
+	if !vk.Verify(proof, public) {
+		return errInvalidProof
+	}
+	fmt.Fprintln(c.stdout, "proof is valid")
+	return nil
+}
+
+func (c *cli) inspect(args []string) error {
+	fs := c.flags("inspect")
+	if err := parseFlags(fs, args, -1); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no file to inspect")
+	}
+	for _, path := range fs.Args() {
+		buff, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var env envelope
+		if err := json.Unmarshal(buff, &env); err == nil && env.Kind != "" {
+			fmt.Fprintf(c.stdout, "%s: %s for %s on %s, %d bytes\n", path, env.Kind, env.Backend, env.Curve, len(env.Data))
+			continue
+		}
+		var r1cs playsnark.R1CS
+		if err := json.Unmarshal(buff, &r1cs); err != nil {
+			return fmt.Errorf("%s: not a R1CS, key or proof file: %w", path, err)
+		}
+		fmt.Fprintf(c.stdout, "%s: R1CS with %d constraints\n", path, r1cs.NbConstraints())
+		fmt.Fprintf(c.stdout, "  inputs:        %v\n", r1cs.Inputs())
+		fmt.Fprintf(c.stdout, "  outputs:       %v\n", r1cs.Outputs())
+		fmt.Fprintf(c.stdout, "  intermediates: %v\n", r1cs.Intermediates())
+	}
+	return nil
+}
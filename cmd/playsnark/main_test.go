@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/stretchr/testify/require"
+)
+
+// cubicCircuit is the circuit x^3 + x + 5 = out
+const cubicCircuit = `{
+	"inputs": ["x"],
+	"outputs": ["out"],
+	"variables": ["u", "v", "w"],
+	"constraints": [
+		{"op": "mul", "left": "x", "right": "x", "out": "u"},
+		{"op": "mul", "left": "u", "right": "x", "out": "v"},
+		{"op": "add", "left": "v", "right": "x", "out": "w"},
+		{"op": "addconst", "left": "w", "const": 5, "out": "out"}
+	]
+}`
+
+// runCmd runs the command in process and returns its exit code and outputs
+func runCmd(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFiles writes the files in a new temporary directory and returns the
+// function giving the path of a file in it
+func writeFiles(t *testing.T, files map[string]string) func(string) string {
+	dir := t.TempDir()
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(path(name), []byte(content), 0644))
+	}
+	return path
+}
+
+func TestEndToEnd(t *testing.T) {
+	for _, b := range playsnark.Backends {
+		for _, c := range playsnark.Curves {
+			t.Run(b.Name()+"/"+c.Name, func(t *testing.T) {
+				path := writeFiles(t, map[string]string{
+					"circuit.json": cubicCircuit,
+					"witness.json": `{"x": 3, "out": 35, "u": 9, "v": 27, "w": 30}`,
+					"public.json":  `{"x": 3, "out": 35}`,
+					"wrong.json":   `{"x": 3, "out": 36}`,
+				})
+				r1cs, pk, vk, proof := path("circuit.r1cs"), path("proving.key"), path("verifying.key"), path("proof.json")
+
+				code, out, _ := runCmd("compile", "-o", r1cs, path("circuit.json"))
+				require.Equal(t, 0, code)
+				require.Contains(t, out, "4 constraints")
+
+				code, _, stderr := runCmd("setup", "-backend", b.Name(), "-curve", c.Name, "-pk", pk, "-vk", vk, r1cs)
+				require.Equal(t, 0, code, stderr)
+
+				code, _, stderr = runCmd("prove", "-pk", pk, "-witness", path("witness.json"), "-o", proof, r1cs)
+				require.Equal(t, 0, code, stderr)
+
+				code, out, stderr = runCmd("verify", "-vk", vk, "-proof", proof, "-public", path("public.json"), r1cs)
+				require.Equal(t, 0, code, stderr)
+				require.Equal(t, "proof is valid\n", out)
+				// the witness contains the public values
+				code, _, _ = runCmd("verify", "-vk", vk, "-proof", proof, "-public", path("witness.json"), r1cs)
+				require.Equal(t, 0, code)
+
+				code, _, stderr = runCmd("verify", "-vk", vk, "-proof", proof, "-public", path("wrong.json"), r1cs)
+				require.Equal(t, 1, code)
+				require.Contains(t, stderr, "proof is invalid")
+
+				code, out, _ = runCmd("inspect", r1cs, pk, vk, proof)
+				require.Equal(t, 0, code)
+				require.Contains(t, out, "R1CS with 4 constraints")
+				require.Contains(t, out, "inputs:        [x]")
+				require.Contains(t, out, "proving key for "+b.Name()+" on "+c.Name)
+				require.Contains(t, out, "verifying key for "+b.Name()+" on "+c.Name)
+				require.Contains(t, out, "proof for "+b.Name()+" on "+c.Name)
+			})
+		}
+	}
+}
+
+func TestCompileSource(t *testing.T) {
+	path := writeFiles(t, map[string]string{
+		"cubic.circuit": "input x\noutput out\nout == x*x*x + x + 5\n",
+	})
+	code, out, stderr := runCmd("compile", "-o", path("cubic.r1cs"), path("cubic.circuit"))
+	require.Equal(t, 0, code, stderr)
+	require.Contains(t, out, "3 constraints")
+	code, out, _ = runCmd("inspect", path("cubic.r1cs"))
+	require.Equal(t, 0, code)
+	require.Contains(t, out, "intermediates: [_t1 _t2]")
+}
+
+func TestErrors(t *testing.T) {
+	path := writeFiles(t, map[string]string{
+		"circuit.json":  cubicCircuit,
+		"unknown.json":  `{"inputs": ["x"], "outputs": ["out"], "constraints": [{"op": "mul", "left": "x", "right": "y", "out": "out"}]}`,
+		"twice.json":    `{"inputs": ["x"], "outputs": ["x"], "constraints": [{"op": "mul", "left": "x", "right": "x", "out": "x"}]}`,
+		"op.json":       `{"inputs": ["x"], "outputs": ["out"], "constraints": [{"op": "div", "left": "x", "right": "x", "out": "out"}]}`,
+		"product.json":  `{"inputs": ["x", "y"], "outputs": ["out"], "constraints": [{"op": "mul", "left": "x", "right": "y", "out": "out"}]}`,
+		"error.circuit": "input x\noutput out\nout == x * y\n",
+		"witness.json":  `{"x": 3, "out": 35, "u": 9, "v": 27, "w": 30}`,
+		"invalid.json":  `{"x": 3, "out": 35, "u": 9, "v": 27, "w": 31}`,
+		"missing.json":  `{"x": 3, "out": 35}`,
+	})
+	r1cs, pk, vk, proof := path("circuit.r1cs"), path("proving.key"), path("verifying.key"), path("proof.json")
+	code, _, stderr := runCmd()
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr, "usage")
+	code, _, stderr = runCmd("plonk")
+	require.Equal(t, 2, code)
+	require.Contains(t, stderr, "unknown command")
+
+	code, _, stderr = runCmd("compile", "-o", r1cs, path("unknown.json"))
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `unknown variable "y"`)
+	code, _, stderr = runCmd("compile", "-o", r1cs, path("twice.json"))
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `"x" declared twice`)
+	code, _, stderr = runCmd("compile", "-o", r1cs, path("op.json"))
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `unknown operation "div"`)
+
+	code, _, _ = runCmd("compile", "-o", r1cs, path("circuit.json"))
+	require.Equal(t, 0, code)
+	code, _, stderr = runCmd("setup", "-backend", "plonk", r1cs)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `unknown backend "plonk"`)
+	code, _, stderr = runCmd("setup", "-curve", "secp256k1", r1cs)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `unknown curve "secp256k1"`)
+	code, _, _ = runCmd("setup", "-pk", pk, "-vk", vk, r1cs)
+	require.Equal(t, 0, code)
+
+	code, _, stderr = runCmd("prove", "-pk", pk, "-witness", path("invalid.json"), "-o", proof, r1cs)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "does not satisfy")
+	code, _, stderr = runCmd("prove", "-pk", pk, "-witness", path("missing.json"), "-o", proof, r1cs)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `missing value for variable "u"`)
+	// the verifying key is not a proving key
+	code, _, stderr = runCmd("prove", "-pk", vk, "-witness", path("witness.json"), "-o", proof, r1cs)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `"verifying key" file`)
+
+	// the keys don't match another circuit
+	product := path("product.r1cs")
+	code, _, _ = runCmd("compile", "-o", product, path("product.json"))
+	require.Equal(t, 0, code)
+	code, _, stderr = runCmd("prove", "-pk", pk, "-witness", path("witness.json"), "-o", proof, product)
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "does not match")
+
+	code, _, stderr = runCmd("compile", "-o", r1cs, path("error.circuit"))
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, `line 3:12: unknown variable "y"`)
+
+	code, _, stderr = runCmd("inspect", path("witness.json"))
+	require.Equal(t, 1, code)
+	require.Contains(t, stderr, "not a R1CS")
+}
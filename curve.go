@@ -1,8 +1,12 @@
 package playsnark
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/drand/kyber"
 	bls "github.com/drand/kyber-bls12381"
+	"github.com/nikkolasg/playsnark/bn254"
 )
 
 type Element = kyber.Scalar
@@ -10,7 +14,129 @@ type Commit = kyber.Point
 type G1 = kyber.Point
 type G2 = kyber.Point
 
-var Suite = bls.NewBLS12381Suite()
+// PairingSuite is a pairing friendly curve: the groups G1 and G2, the target
+// group GT and the pairing e: G1 x G2 -> GT. The scalars of the three groups
+// are the same, and form the field of the circuits.
+type PairingSuite interface {
+	G1() kyber.Group
+	G2() kyber.Group
+	GT() kyber.Group
+	Pair(p1, p2 kyber.Point) kyber.Point
+}
+
+// Curve is the pairing suite a proof system runs on. Groth16 and PHGR13 take
+// it from the QAP, whose polynomials are defined over its scalar field, the
+// systems with a universal setup take it from the KZG setup and Spartan and
+// Nova from their own setup.
+type Curve struct {
+	Name  string
+	Suite PairingSuite
+	// Generator generates the multiplicative group of the scalar field, the
+	// evaluation domains are derived from it
+	Generator Value
+}
+
+// BLS12381 is the curve used by default
+var BLS12381 = Curve{Name: "BLS12-381", Suite: bls.NewBLS12381Suite(), Generator: 7}
+
+// BN254 is the curve supported by the Ethereum precompiles
+var BN254 = Curve{Name: "BN254", Suite: bn254.NewSuite(), Generator: 5}
+
+// Curves lists all the supported curves
+var Curves = []Curve{BLS12381, BN254}
+
+// CurveByName returns the supported curve with the given name
+func CurveByName(name string) (Curve, error) {
+	for _, c := range Curves {
+		if c.Name == name {
+			return c, nil
+		}
+	}
+	return Curve{}, fmt.Errorf("unknown curve %q", name)
+}
+
+// NewElement returns the zero of the scalar field
+func (c Curve) NewElement() Element {
+	return c.Suite.G1().Scalar().Zero()
+}
+
+// Element returns the value as an element of the scalar field
+func (c Curve) Element(v Value) Element {
+	return c.Suite.G1().Scalar().SetInt64(int64(v))
+}
+
+// Elements returns the values as elements of the scalar field
+func (c Curve) Elements(v Vector) []Element {
+	elements := make([]Element, len(v))
+	for i := range v {
+		elements[i] = c.Element(v[i])
+	}
+	return elements
+}
+
+// Modulus returns the order of the scalar field
+func (c Curve) Modulus() *big.Int {
+	m := c.BigInt(c.Element(-1))
+	return m.Add(m, big.NewInt(1))
+}
+
+// BigInt returns the element of the scalar field as an integer in [0, r)
+func (c Curve) BigInt(e Element) *big.Int {
+	buff, err := e.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	return new(big.Int).SetBytes(buff)
+}
+
+// ElementFromBig returns the integer reduced modulo the order of the scalar
+// field
+func (c Curve) ElementFromBig(v *big.Int) Element {
+	e := c.NewElement()
+	buff := make([]byte, e.MarshalSize())
+	new(big.Int).Mod(v, c.Modulus()).FillBytes(buff)
+	if err := e.UnmarshalBinary(buff); err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// twoAdicity returns the largest k such that 2^k divides r - 1, the order of
+// the multiplicative group of the scalar field
+func (c Curve) twoAdicity() int {
+	m := c.Modulus()
+	m.Sub(m, big.NewInt(1))
+	return int(m.TrailingZeroBits())
+}
+
+// NewG1 returns the generator of G1
+func (c Curve) NewG1() G1 {
+	return c.Suite.G1().Point().Base()
+}
+
+// hashToG1 returns a point of G1 whose discrete log is unknown
+func hashToG1(c Curve, label string) G1 {
+	return c.NewG1().(interface{ Hash([]byte) kyber.Point }).Hash([]byte(label))
+}
+
+// NewG2 returns the generator of G2
+func (c Curve) NewG2() G2 {
+	return c.Suite.G2().Point().Base()
+}
+
+// NewGT returns the neutral element of GT
+func (c Curve) NewGT() Target {
+	return c.Suite.GT().Point().Null()
+}
+
+// Pair returns e(a,b)
+func (c Curve) Pair(a G1, b G2) Target {
+	return c.Suite.Pair(a, b)
+}
+
+// The following globals work on the default curve, for the code which doesn't
+// take one: the functions without a curve argument use them.
+var Suite = BLS12381.Suite
 var Group = Suite.G1()
 var G2Group = Suite.G2()
 
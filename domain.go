@@ -0,0 +1,163 @@
+package playsnark
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Domain is a multiplicative subgroup H = {1, w, w^2, ..., w^(n-1)} of the
+// scalar field where w is a primitive n-th root of unity. Contrary to the QAP
+// which interpolates polynomials on the points 1, 2, ..., n, the proof systems
+// with a universal setup interpolate on such a subgroup since the polynomial
+// vanishing on all of its points is simply
+//
+//	Z_H(x) = x^n - 1
+//
+// and the Lagrange polynomials can be evaluated anywhere in constant time.
+type Domain struct {
+	// Curve is the curve whose scalar field contains the domain
+	Curve Curve
+	// Size is the number of elements n, a power of two
+	Size int
+	// Omega is the generator w of the subgroup
+	Omega Element
+	// Elements are {w^i} for i:0->n-1
+	Elements []Element
+}
+
+// NewDomain returns the subgroup of size n of the scalar field of the default
+// curve, n must be a power of two.
+func NewDomain(n int) Domain {
+	return NewDomainWith(BLS12381, n)
+}
+
+// NewDomainWith returns the subgroup of size n of the scalar field of the
+// given curve, n must be a power of two dividing r - 1.
+func NewDomainWith(c Curve, n int) Domain {
+	if n < 1 || n&(n-1) != 0 || n > 1<<uint(c.twoAdicity()) {
+		panic(fmt.Sprintf("domain size %d is not a power of two up to 2^%d on %s", n, c.twoAdicity(), c.Name))
+	}
+	// w = g^((r-1)/n) is then a n-th root of unity, and a primitive one since g
+	// generates the whole group
+	e := c.Modulus()
+	e.Sub(e, big.NewInt(1))
+	e.Div(e, big.NewInt(int64(n)))
+	omega := expElement(c.Element(c.Generator), e)
+	elements := make([]Element, n)
+	elements[0] = c.NewElement().One()
+	for i := 1; i < n; i++ {
+		elements[i] = c.NewElement().Mul(elements[i-1], omega)
+	}
+	return Domain{
+		Curve:    c,
+		Size:     n,
+		Omega:    omega,
+		Elements: elements,
+	}
+}
+
+// Vanishing returns Z_H(x) = x^n - 1
+func (d Domain) Vanishing() Poly {
+	z := newPoly(d.Curve, d.Size)
+	z[0] = d.Curve.Element(-1)
+	z[d.Size] = d.Curve.Element(1)
+	return z
+}
+
+// DivideByVanishing returns the quotient q and the remainder r such that
+// p(x) = q(x) * Z_H(x) + r(x). Since Z_H(x) = x^n - 1, each coefficient of
+// degree i >= n is simply moved down to the degree i - n.
+func (d Domain) DivideByVanishing(p Poly) (q Poly, r Poly) {
+	r = p.Clone()
+	if len(r) <= d.Size {
+		return Poly{}, r.Normalize()
+	}
+	q = newPoly(d.Curve, len(r)-d.Size-1)
+	for i := len(r) - 1; i >= d.Size; i-- {
+		q[i-d.Size] = q[i-d.Size].Add(q[i-d.Size], r[i])
+		r[i-d.Size] = r[i-d.Size].Add(r[i-d.Size], r[i])
+		r[i] = d.Curve.NewElement()
+	}
+	return q, r.Normalize()
+}
+
+// EvalVanishing returns Z_H(x) = x^n - 1 for the given x
+func (d Domain) EvalVanishing(x Element) Element {
+	xn := expElement(x, big.NewInt(int64(d.Size)))
+	return xn.Sub(xn, d.Curve.Element(1))
+}
+
+// Interpolate returns the polynomial p of degree < n such that p(w^i) = ys[i]
+// for i:0->n-1, missing evaluations are considered to be zero. It uses the
+// inverse discrete Fourier transform:
+//
+//	p_j = 1/n * SUM ys[i] * w^(-ij)
+func (d Domain) Interpolate(ys []Element) Poly {
+	if len(ys) > d.Size {
+		panic("too many evaluations for the domain")
+	}
+	nInv := d.Curve.NewElement().Inv(d.Curve.Element(Value(d.Size)))
+	p := newPoly(d.Curve, d.Size-1)
+	for j := 0; j < d.Size; j++ {
+		for i, y := range ys {
+			// w^(-ij) = w^(n - ij mod n)
+			wij := d.Elements[(d.Size-(i*j)%d.Size)%d.Size]
+			p[j] = p[j].Add(p[j], d.Curve.NewElement().Mul(y, wij))
+		}
+		p[j] = p[j].Mul(p[j], nInv)
+	}
+	return p
+}
+
+// Evaluate returns the evaluations of p on all the elements of the domain
+func (d Domain) Evaluate(p Poly) []Element {
+	out := make([]Element, d.Size)
+	for i, x := range d.Elements {
+		out[i] = p.Eval(x)
+	}
+	return out
+}
+
+// Lagrange returns the i-th Lagrange polynomial L_i which is one on w^i and
+// zero on all the other elements of the domain
+func (d Domain) Lagrange(i int) Poly {
+	ys := make([]Element, d.Size)
+	for j := range ys {
+		ys[j] = d.Curve.NewElement()
+	}
+	ys[i] = d.Curve.Element(1)
+	return d.Interpolate(ys)
+}
+
+// EvalLagrange returns L_i(x) without interpolating the polynomial:
+//
+//	L_i(x) = w^i * (x^n - 1) / (n * (x - w^i))
+//
+// x must not be in the domain.
+func (d Domain) EvalLagrange(i int, x Element) Element {
+	num := d.Curve.NewElement().Mul(d.Elements[i], d.EvalVanishing(x))
+	den := d.Curve.NewElement().Sub(x, d.Elements[i])
+	den = den.Mul(den, d.Curve.Element(Value(d.Size)))
+	return num.Div(num, den)
+}
+
+// expElement returns base^e using square and multiply
+func expElement(base Element, e *big.Int) Element {
+	res := base.Clone().One()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = res.Mul(res, res)
+		if e.Bit(i) == 1 {
+			res = res.Mul(res, base)
+		}
+	}
+	return res
+}
+
+// nextPowerOfTwo returns the smallest power of two greater or equal to n
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
@@ -0,0 +1,106 @@
+package playsnark
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainRootOfUnity(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			for _, n := range []int{1, 2, 8, 64} {
+				d := NewDomainWith(curve, n)
+				require.Len(t, d.Elements, n)
+				// w^n = 1
+				require.True(t, expElement(d.Omega, big.NewInt(int64(n))).Equal(curve.Element(1)))
+				// w is primitive: w^(n/2) = -1
+				if n > 1 {
+					require.True(t, expElement(d.Omega, big.NewInt(int64(n/2))).Equal(curve.NewElement().Neg(curve.Element(1))))
+				}
+				// the vanishing polynomial is zero on the whole domain
+				z := d.Vanishing()
+				for _, x := range d.Elements {
+					require.True(t, z.Eval(x).Equal(curve.NewElement()))
+				}
+				x := curve.NewElement().Pick(random.New())
+				require.True(t, z.Eval(x).Equal(d.EvalVanishing(x)))
+			}
+			require.Panics(t, func() { NewDomainWith(curve, 6) })
+		})
+	}
+}
+
+func TestDomainMaxSize(t *testing.T) {
+	// the 2-adicity of the scalar field bounds the size of the domains
+	require.Equal(t, 32, BLS12381.twoAdicity())
+	require.Equal(t, 28, BN254.twoAdicity())
+	require.Panics(t, func() { NewDomainWith(BN254, 1<<29) })
+	require.Panics(t, func() { NewDomain(1 << 33) })
+}
+
+func TestDomainInterpolate(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			d := NewDomainWith(curve, 8)
+			p := randomPolyWith(curve, 7)
+			evals := d.Evaluate(p)
+			require.True(t, p.Equal(d.Interpolate(evals)))
+
+			// partial evaluations are padded with zeros
+			q := d.Interpolate(evals[:3])
+			for i, x := range d.Elements {
+				if i < 3 {
+					require.True(t, q.Eval(x).Equal(evals[i]))
+				} else {
+					require.True(t, q.Eval(x).Equal(curve.NewElement()))
+				}
+			}
+		})
+	}
+}
+
+func TestDomainLagrange(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			d := NewDomainWith(curve, 4)
+			x := curve.NewElement().Pick(random.New())
+			for i := 0; i < d.Size; i++ {
+				l := d.Lagrange(i)
+				for j, w := range d.Elements {
+					if i == j {
+						require.True(t, l.Eval(w).Equal(curve.Element(1)))
+					} else {
+						require.True(t, l.Eval(w).Equal(curve.NewElement()))
+					}
+				}
+				require.True(t, l.Eval(x).Equal(d.EvalLagrange(i, x)))
+			}
+		})
+	}
+}
+
+func TestDomainDivideByVanishing(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			d := NewDomainWith(curve, 4)
+			q := randomPolyWith(curve, 6)
+			r := randomPolyWith(curve, 2)
+			p := q.Mul(d.Vanishing()).Add(r)
+			q2, r2 := d.DivideByVanishing(p)
+			require.True(t, q.Equal(q2))
+			require.True(t, r.Equal(r2))
+			// same as the long division
+			q3, r3 := p.Div2(d.Vanishing())
+			require.True(t, q2.Equal(q3))
+			require.True(t, r2.Equal(r3))
+
+			// small polynomial
+			q2, r2 = d.DivideByVanishing(r)
+			require.Len(t, q2, 0)
+			require.True(t, r.Equal(r2))
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package playsnark
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/drand/kyber"
+)
+
+// The keys and proofs of the backends are marshalled as a sequence of points,
+// each prefixed by its length, and of slices of points prefixed by their
+// number of elements. The decoder knows the group of each point, the curve
+// being the one of the QAP, and rejects any point of another length.
+
+var errKeyMismatch = errors.New("key does not match the circuit")
+
+type encoder struct {
+	buff bytes.Buffer
+	err  error
+}
+
+func (e *encoder) point(p Commit) {
+	if e.err != nil {
+		return
+	}
+	buff, err := p.MarshalBinary()
+	if err != nil {
+		e.err = err
+		return
+	}
+	var size [2]byte
+	binary.BigEndian.PutUint16(size[:], uint16(len(buff)))
+	e.buff.Write(size[:])
+	e.buff.Write(buff)
+}
+
+func (e *encoder) points(ps []Commit) {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(ps)))
+	e.buff.Write(size[:])
+	for _, p := range ps {
+		e.point(p)
+	}
+}
+
+func (e *encoder) bytes() ([]byte, error) {
+	return e.buff.Bytes(), e.err
+}
+
+type decoder struct {
+	c   Curve
+	r   *bytes.Reader
+	err error
+}
+
+func newDecoder(c Curve, buff []byte) *decoder {
+	return &decoder{c: c, r: bytes.NewReader(buff)}
+}
+
+func (d *decoder) read(buff []byte) bool {
+	if d.err != nil {
+		return false
+	}
+	if _, err := io.ReadFull(d.r, buff); err != nil {
+		d.err = err
+		return false
+	}
+	return true
+}
+
+func (d *decoder) g1() kyber.Group {
+	return d.c.Suite.G1()
+}
+
+func (d *decoder) g2() kyber.Group {
+	return d.c.Suite.G2()
+}
+
+// point reads a point of the group g
+func (d *decoder) point(g kyber.Group) Commit {
+	var size [2]byte
+	if !d.read(size[:]) {
+		return nil
+	}
+	if n := int(binary.BigEndian.Uint16(size[:])); n != g.PointLen() {
+		d.err = fmt.Errorf("invalid point length %d for %s on %s", n, g, d.c.Name)
+		return nil
+	}
+	p := g.Point()
+	buff := make([]byte, p.MarshalSize())
+	if !d.read(buff) {
+		return nil
+	}
+	if err := p.UnmarshalBinary(buff); err != nil {
+		d.err = err
+		return nil
+	}
+	return p
+}
+
+// points reads a slice of points of the group g
+func (d *decoder) points(g kyber.Group) []Commit {
+	var size [4]byte
+	if !d.read(size[:]) {
+		return nil
+	}
+	n := int(binary.BigEndian.Uint32(size[:]))
+	// each point takes at least its length prefix
+	if n > d.r.Len()/2 {
+		d.err = errors.New("invalid number of points")
+		return nil
+	}
+	ps := make([]Commit, 0, n)
+	for i := 0; i < n; i++ {
+		ps = append(ps, d.point(g))
+	}
+	return ps
+}
+
+// done returns the first error encountered, or an error if some bytes were
+// left
+func (d *decoder) done() error {
+	if d.err == nil && d.r.Len() != 0 {
+		d.err = errors.New("trailing bytes")
+	}
+	return d.err
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (p Groth16Proof) MarshalBinary() ([]byte, error) {
+	var e encoder
+	e.point(p.A)
+	e.point(p.B)
+	e.point(p.C)
+	return e.bytes()
+}
+
+func unmarshalGroth16Proof(c Curve, buff []byte) (Groth16Proof, error) {
+	d := newDecoder(c, buff)
+	p := Groth16Proof{A: d.point(d.g1()), B: d.point(d.g2()), C: d.point(d.g1())}
+	return p, d.done()
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface. The toxic
+// waste is not marshalled.
+func (tr Groth16Setup) MarshalBinary() ([]byte, error) {
+	var e encoder
+	e.point(tr.Alpha)
+	e.point(tr.Beta)
+	e.point(tr.Delta)
+	e.points(tr.Xi)
+	e.points(tr.IoLP)
+	e.points(tr.NioLP)
+	e.points(tr.XiT)
+	e.point(tr.Beta2)
+	e.point(tr.Delta2)
+	e.point(tr.Gamma)
+	e.points(tr.Xi2)
+	return e.bytes()
+}
+
+func unmarshalGroth16Setup(qap QAP, buff []byte) (Groth16Setup, error) {
+	d := newDecoder(qap.curve, buff)
+	tr := Groth16Setup{curve: qap.curve}
+	tr.Alpha = d.point(d.g1())
+	tr.Beta = d.point(d.g1())
+	tr.Delta = d.point(d.g1())
+	tr.Xi = d.points(d.g1())
+	tr.IoLP = d.points(d.g1())
+	tr.NioLP = d.points(d.g1())
+	tr.XiT = d.points(d.g1())
+	tr.Beta2 = d.point(d.g2())
+	tr.Delta2 = d.point(d.g2())
+	tr.Gamma = d.point(d.g2())
+	tr.Xi2 = d.points(d.g2())
+	if err := d.done(); err != nil {
+		return tr, err
+	}
+	if len(tr.IoLP) != qap.nbIO || len(tr.NioLP) != qap.nbVars-qap.nbIO || len(tr.Xi) != qap.nbGates {
+		return tr, errKeyMismatch
+	}
+	return tr, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (vk Groth16VerifyingKey) MarshalBinary() ([]byte, error) {
+	var e encoder
+	e.point(vk.Alpha)
+	e.point(vk.Beta2)
+	e.point(vk.Gamma)
+	e.point(vk.Delta2)
+	e.points(vk.IoLP)
+	return e.bytes()
+}
+
+func unmarshalGroth16VerifyingKey(qap QAP, buff []byte) (Groth16VerifyingKey, error) {
+	d := newDecoder(qap.curve, buff)
+	vk := Groth16VerifyingKey{Curve: qap.curve}
+	vk.Alpha = d.point(d.g1())
+	vk.Beta2 = d.point(d.g2())
+	vk.Gamma = d.point(d.g2())
+	vk.Delta2 = d.point(d.g2())
+	vk.IoLP = d.points(d.g1())
+	if err := d.done(); err != nil {
+		return vk, err
+	}
+	if len(vk.IoLP) != qap.nbIO {
+		return vk, errKeyMismatch
+	}
+	return vk, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (p PHGR13Proof) MarshalBinary() ([]byte, error) {
+	var e encoder
+	for _, pt := range []Commit{p.vss, p.vass, p.wss, p.wass, p.yss, p.yass, p.hs, p.gz} {
+		e.point(pt)
+	}
+	return e.bytes()
+}
+
+func unmarshalPHGR13Proof(c Curve, buff []byte) (PHGR13Proof, error) {
+	d := newDecoder(c, buff)
+	var p PHGR13Proof
+	// only wss is on G2
+	for _, pt := range []*Commit{&p.vss, &p.vass, &p.wss, &p.wass, &p.yss, &p.yass, &p.hs, &p.gz} {
+		g := d.g1()
+		if pt == &p.wss {
+			g = d.g2()
+		}
+		*pt = d.point(g)
+	}
+	return p, d.done()
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (ek PHGR13EvalKey) MarshalBinary() ([]byte, error) {
+	var e encoder
+	for _, ps := range [][]Commit{ek.vs, ek.ws, ek.ys, ek.vas, ek.was, ek.yas, ek.gsi, ek.vbs, ek.wbs, ek.ybs} {
+		e.points(ps)
+	}
+	return e.bytes()
+}
+
+func unmarshalPHGR13EvalKey(qap QAP, buff []byte) (PHGR13EvalKey, error) {
+	d := newDecoder(qap.curve, buff)
+	var ek PHGR13EvalKey
+	// only ws is on G2
+	intermediates := []*[]Commit{&ek.vs, &ek.ws, &ek.ys, &ek.vas, &ek.was, &ek.yas}
+	for _, ps := range append(intermediates, &ek.gsi, &ek.vbs, &ek.wbs, &ek.ybs) {
+		g := d.g1()
+		if ps == &ek.ws {
+			g = d.g2()
+		}
+		*ps = d.points(g)
+	}
+	if err := d.done(); err != nil {
+		return ek, err
+	}
+	for _, ps := range append(intermediates, &ek.vbs, &ek.wbs, &ek.ybs) {
+		if len(*ps) != qap.nbVars-qap.nbIO {
+			return ek, errKeyMismatch
+		}
+	}
+	return ek, nil
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface
+func (vk PHGR13VerifKey) MarshalBinary() ([]byte, error) {
+	var e encoder
+	for _, pt := range []Commit{vk.g1, vk.av, vk.aw, vk.ay, vk.gamma, vk.bgamma, vk.bgamma2, vk.yts} {
+		e.point(pt)
+	}
+	for _, ps := range [][]Commit{vk.vs, vk.ws, vk.ys} {
+		e.points(ps)
+	}
+	return e.bytes()
+}
+
+func unmarshalPHGR13VerifKey(qap QAP, buff []byte) (PHGR13VerifKey, error) {
+	d := newDecoder(qap.curve, buff)
+	var vk PHGR13VerifKey
+	// all the points are on G2 but g1, aw and bgamma
+	for _, pt := range []*Commit{&vk.g1, &vk.av, &vk.aw, &vk.ay, &vk.gamma, &vk.bgamma, &vk.bgamma2, &vk.yts} {
+		g := d.g2()
+		if pt == &vk.g1 || pt == &vk.aw || pt == &vk.bgamma {
+			g = d.g1()
+		}
+		*pt = d.point(g)
+	}
+	for _, ps := range []*[]Commit{&vk.vs, &vk.ws, &vk.ys} {
+		g := d.g1()
+		if ps == &vk.ws {
+			g = d.g2()
+		}
+		*ps = d.points(g)
+		if d.err == nil && len(*ps) != qap.nbVars {
+			return vk, errKeyMismatch
+		}
+	}
+	return vk, d.done()
+}
@@ -0,0 +1,316 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/nikkolasg/playsnark"
+)
+
+type Element = playsnark.Element
+
+// expression is a linear combination of the variables of the R1CS, mapping
+// their index to their coefficient. The index 0 is "const", so a constant is
+// an expression with this index only.
+type expression map[int]Element
+
+// step computes the values of variables allocated by the builder, in the
+// order of their allocation
+type step func(s *solver) error
+
+// builder implements the API by recording the constraints
+type builder struct {
+	curve       playsnark.Curve
+	nbVars      int
+	constraints []playsnark.Constraint
+	steps       []step
+	// err is the first error met while recording the constraints
+	err error
+}
+
+func newBuilder(curve playsnark.Curve) *builder {
+	// the variable 0 is "const"
+	return &builder{curve: curve, nbVars: 1}
+}
+
+func (b *builder) Curve() playsnark.Curve {
+	return b.curve
+}
+
+func (b *builder) errorf(format string, args ...interface{}) {
+	if b.err == nil {
+		b.err = fmt.Errorf(format, args...)
+	}
+}
+
+// newVariable allocates a new variable of the R1CS
+func (b *builder) newVariable() expression {
+	b.nbVars++
+	return expression{b.nbVars - 1: b.curve.NewElement().One()}
+}
+
+// constant returns the expression of the constant
+func (b *builder) constant(v Element) expression {
+	if v.Equal(b.curve.NewElement()) {
+		return expression{}
+	}
+	return expression{0: v.Clone()}
+}
+
+// toExpression returns the variable or the constant as an expression
+func (b *builder) toExpression(v Variable) expression {
+	if e, ok := v.(expression); ok {
+		return e
+	}
+	value, err := toElement(b.curve, v)
+	if err != nil {
+		b.errorf("%w", err)
+		return expression{}
+	}
+	return b.constant(value)
+}
+
+// constantValue returns the value of the expression if it is a constant
+func (b *builder) constantValue(e expression) (Element, bool) {
+	for i := range e {
+		if i != 0 {
+			return nil, false
+		}
+	}
+	if c, ok := e[0]; ok {
+		return c, true
+	}
+	return b.curve.NewElement(), true
+}
+
+// combine returns a + k * b
+func (b *builder) combine(x, y expression, k Element) expression {
+	res := make(expression, len(x)+len(y))
+	for i, c := range x {
+		res[i] = c.Clone()
+	}
+	for i, c := range y {
+		term := b.curve.NewElement().Mul(c, k)
+		if prev, ok := res[i]; ok {
+			term = term.Add(term, prev)
+		}
+		if term.Equal(b.curve.NewElement()) {
+			delete(res, i)
+		} else {
+			res[i] = term
+		}
+	}
+	return res
+}
+
+// scale returns k * x
+func (b *builder) scale(x expression, k Element) expression {
+	return b.combine(expression{}, x, k)
+}
+
+func (b *builder) Add(x, y Variable, others ...Variable) Variable {
+	one := b.curve.NewElement().One()
+	res := b.combine(b.toExpression(x), b.toExpression(y), one)
+	for _, o := range others {
+		res = b.combine(res, b.toExpression(o), one)
+	}
+	return res
+}
+
+func (b *builder) Sub(x, y Variable, others ...Variable) Variable {
+	minusOne := b.curve.NewElement().SetInt64(-1)
+	res := b.combine(b.toExpression(x), b.toExpression(y), minusOne)
+	for _, o := range others {
+		res = b.combine(res, b.toExpression(o), minusOne)
+	}
+	return res
+}
+
+func (b *builder) Mul(x, y Variable, others ...Variable) Variable {
+	res := b.mul(b.toExpression(x), b.toExpression(y))
+	for _, o := range others {
+		res = b.mul(res, b.toExpression(o))
+	}
+	return res
+}
+
+// mul returns x * y, allocating a variable unless one of them is a constant
+func (b *builder) mul(x, y expression) expression {
+	if k, ok := b.constantValue(x); ok {
+		return b.scale(y, k)
+	}
+	if k, ok := b.constantValue(y); ok {
+		return b.scale(x, k)
+	}
+	res := b.newVariable()
+	b.addStep(res, func(s *solver) (Element, error) {
+		return s.curve.NewElement().Mul(s.eval(x), s.eval(y)), nil
+	})
+	b.constrain(x, y, res)
+	return res
+}
+
+func (b *builder) Inverse(x Variable) Variable {
+	e := b.toExpression(x)
+	if k, ok := b.constantValue(e); ok {
+		if k.Equal(b.curve.NewElement()) {
+			b.errorf("inverse of zero")
+			return expression{}
+		}
+		return b.constant(b.curve.NewElement().Inv(k))
+	}
+	res := b.newVariable()
+	b.addStep(res, func(s *solver) (Element, error) {
+		v := s.eval(e)
+		if v.Equal(s.curve.NewElement()) {
+			return nil, errors.New("inverse of zero")
+		}
+		return s.curve.NewElement().Inv(v), nil
+	})
+	b.constrain(e, res, b.constant(b.curve.NewElement().One()))
+	return res
+}
+
+func (b *builder) Select(sel, x, y Variable) Variable {
+	s := b.toExpression(sel)
+	ex, ey := b.toExpression(x), b.toExpression(y)
+	if k, ok := b.constantValue(s); ok {
+		switch {
+		case k.Equal(b.curve.NewElement()):
+			return ey
+		case k.Equal(b.curve.NewElement().One()):
+			return ex
+		}
+		b.errorf("selector is not boolean")
+		return expression{}
+	}
+	b.assertIsBoolean(s)
+	// y + s * (x - y)
+	return b.combine(ey, b.mul(s, b.Sub(ex, ey).(expression)), b.curve.NewElement().One())
+}
+
+// assertIsBoolean constrains x * x = x
+func (b *builder) assertIsBoolean(x expression) {
+	b.constrain(x, x, x)
+}
+
+func (b *builder) AssertIsBoolean(x Variable) {
+	e := b.toExpression(x)
+	if k, ok := b.constantValue(e); ok {
+		if !k.Equal(b.curve.NewElement()) && !k.Equal(b.curve.NewElement().One()) {
+			b.errorf("constant is not boolean")
+		}
+		return
+	}
+	b.assertIsBoolean(e)
+}
+
+func (b *builder) AssertProduct(x, y, z Variable) {
+	b.constrain(b.toExpression(x), b.toExpression(y), b.toExpression(z))
+}
+
+func (b *builder) NewHint(hint Hint, nbOutputs int, inputs ...Variable) []Variable {
+	ins := make([]expression, len(inputs))
+	for i := range inputs {
+		ins[i] = b.toExpression(inputs[i])
+	}
+	outs := make([]Variable, nbOutputs)
+	indexes := make([]int, nbOutputs)
+	for i := range outs {
+		outs[i] = b.newVariable()
+		indexes[i] = b.nbVars - 1
+	}
+	b.steps = append(b.steps, func(s *solver) error {
+		field := s.curve.Modulus()
+		values := make([]*big.Int, len(ins))
+		for i := range ins {
+			values[i] = s.curve.BigInt(s.eval(ins[i]))
+		}
+		results := make([]*big.Int, len(indexes))
+		for i := range results {
+			results[i] = new(big.Int)
+		}
+		if err := hint(field, values, results); err != nil {
+			return err
+		}
+		for i, index := range indexes {
+			s.values[index] = s.curve.ElementFromBig(results[i])
+		}
+		return nil
+	})
+	return outs
+}
+
+func (b *builder) AssertIsEqual(x, y Variable) {
+	ex, ey := b.toExpression(x), b.toExpression(y)
+	kx, okx := b.constantValue(ex)
+	ky, oky := b.constantValue(ey)
+	if okx && oky {
+		if !kx.Equal(ky) {
+			b.errorf("assertion between different constants")
+		}
+		return
+	}
+	b.constrain(ex, b.constant(b.curve.NewElement().One()), ey)
+}
+
+// constrain adds the constraint left * right = out
+func (b *builder) constrain(left, right, out expression) {
+	b.constraints = append(b.constraints, playsnark.Constraint{
+		Left:  left,
+		Right: right,
+		Out:   out,
+	})
+}
+
+// addStep adds the step computing the value of the variable
+func (b *builder) addStep(v expression, compute func(s *solver) (Element, error)) {
+	var index int
+	for i := range v {
+		index = i
+	}
+	b.steps = append(b.steps, func(s *solver) error {
+		value, err := compute(s)
+		if err != nil {
+			return err
+		}
+		s.values[index] = value
+		return nil
+	})
+}
+
+// toElement returns the value as an element of the scalar field of the
+// curve
+func toElement(curve playsnark.Curve, v Variable) (Element, error) {
+	switch v := v.(type) {
+	case int:
+		return curve.NewElement().SetInt64(int64(v)), nil
+	case int64:
+		return curve.NewElement().SetInt64(v), nil
+	case uint64:
+		return curve.ElementFromBig(new(big.Int).SetUint64(v)), nil
+	case *big.Int:
+		if v == nil {
+			return nil, errors.New("nil big.Int")
+		}
+		return curve.ElementFromBig(v), nil
+	case big.Int:
+		return curve.ElementFromBig(&v), nil
+	case string:
+		n, ok := new(big.Int).SetString(v, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid number %q", v)
+		}
+		return curve.ElementFromBig(n), nil
+	case Element:
+		if reflect.TypeOf(v) != reflect.TypeOf(curve.NewElement()) {
+			return nil, errors.New("element of another field")
+		}
+		return v.Clone(), nil
+	case nil:
+		return nil, errors.New("missing value")
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", v)
+}
@@ -0,0 +1,119 @@
+// Package frontend compiles circuits written in Go to rank-1 constraint
+// systems, in the style of gnark. A circuit is a struct whose Variable fields
+// are its inputs, tagged as public or secret, and whose Define method
+// constrains them through the API:
+//
+//	type Cubic struct {
+//		X Variable `snark:"secret"`
+//		Y Variable `snark:"public"`
+//	}
+//
+//	func (c *Cubic) Define(api API) error {
+//		x3 := api.Mul(c.X, c.X, c.X)
+//		api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+//		return nil
+//	}
+//
+// Compile runs Define once to record the constraints. The same struct filled
+// with values, the assignment, is then given to Solve which computes the
+// values of all the variables of the R1CS.
+//
+// Contrary to the R1CS of the playsnark package, the coefficients and the
+// values are elements of the scalar field of a curve, so the circuits can
+// compute inverses or use constants of any size.
+package frontend
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/nikkolasg/playsnark"
+)
+
+// Variable is a wire of the circuit. The fields of the circuits are given
+// values of the following types in the assignment, and the API also accepts
+// them as constants: int, int64, uint64, *big.Int, big.Int, decimal strings
+// and playsnark.Element.
+type Variable interface{}
+
+// Circuit is a struct whose Variable fields, possibly nested in structs,
+// arrays and slices, are the public and secret inputs. They are tagged
+// `snark:"public"` or `snark:"secret"`, secret being the default, and
+// `snark:"-"` skips a field. The tag of a struct applies to its fields.
+type Circuit interface {
+	// Define declares the constraints of the circuit with the API
+	Define(api API) error
+}
+
+// API records the constraints of a circuit. All the methods accept
+// variables as well as constants and return a variable.
+type API interface {
+	// Add returns a + b + ...
+	Add(a, b Variable, others ...Variable) Variable
+	// Sub returns a - b - ...
+	Sub(a, b Variable, others ...Variable) Variable
+	// Mul returns a * b * ...
+	Mul(a, b Variable, others ...Variable) Variable
+	// Inverse returns 1 / a, solving fails if a is zero
+	Inverse(a Variable) Variable
+	// Select returns a if b is 1 and c if b is 0, b being constrained to be
+	// boolean
+	Select(b, a, c Variable) Variable
+	// AssertIsEqual constrains a and b to be equal
+	AssertIsEqual(a, b Variable)
+	// AssertIsBoolean constrains a to be 0 or 1
+	AssertIsBoolean(a Variable)
+	// AssertProduct constrains a * b = c with a single constraint
+	AssertProduct(a, b, c Variable)
+	// NewHint returns nbOutputs new variables computed by the hint from the
+	// values of the inputs while solving. The outputs are not constrained:
+	// the circuit must check them.
+	NewHint(hint Hint, nbOutputs int, inputs ...Variable) []Variable
+	// Curve returns the curve over whose scalar field the circuit is defined
+	Curve() playsnark.Curve
+}
+
+// Hint computes the outputs from the inputs, given as integers in [0, field),
+// field being the order of the scalar field. The outputs are allocated and are
+// reduced modulo the field afterwards.
+type Hint func(field *big.Int, inputs []*big.Int, outputs []*big.Int) error
+
+// Compile calls the Define method of the circuit, which must be a pointer to
+// a struct, and returns its constraints over the scalar field of the curve.
+// The inputs of the circuit are set to the variables of the R1CS.
+func Compile(curve playsnark.Curve, circuit Circuit) (*R1CS, error) {
+	inputs, err := parseInputs(circuit)
+	if err != nil {
+		return nil, err
+	}
+	b := newBuilder(curve)
+	r := &R1CS{curve: curve}
+	// the public inputs come first, right after "const"
+	for _, visibility := range []visibility{public, secret} {
+		for _, in := range inputs {
+			if in.visibility != visibility {
+				continue
+			}
+			in.value.Set(reflect.ValueOf(b.newVariable()))
+			if visibility == public {
+				r.public = append(r.public, in.name)
+			} else {
+				r.secret = append(r.secret, in.name)
+			}
+		}
+	}
+	if err := circuit.Define(b); err != nil {
+		return nil, fmt.Errorf("define: %w", err)
+	}
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.constraints) == 0 {
+		return nil, fmt.Errorf("circuit without constraints")
+	}
+	r.nbVars = b.nbVars
+	r.constraints = b.constraints
+	r.steps = b.steps
+	return r, nil
+}
@@ -0,0 +1,238 @@
+package frontend
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/stretchr/testify/require"
+)
+
+// cubic is the circuit x^3 + x + 5 = y
+type cubic struct {
+	X Variable `snark:"secret"`
+	Y Variable `snark:"public"`
+}
+
+func (c *cubic) Define(api API) error {
+	x3 := api.Mul(c.X, c.X, c.X)
+	api.AssertIsEqual(c.Y, api.Add(x3, c.X, 5))
+	return nil
+}
+
+func TestCompileCubic(t *testing.T) {
+	for _, curve := range playsnark.Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs, err := Compile(curve, &cubic{})
+			require.NoError(t, err)
+			// x*x, x^2*x and the assertion
+			require.Equal(t, 3, r1cs.NbConstraints())
+			require.Equal(t, 5, r1cs.NbVariables())
+			require.Equal(t, []string{"Y"}, r1cs.Public())
+			require.Equal(t, []string{"X"}, r1cs.Secret())
+
+			sol, err := r1cs.Solve(&cubic{X: 3, Y: 35})
+			require.NoError(t, err)
+			require.True(t, r1cs.IsSatisfied(sol))
+			qap := r1cs.QAP()
+			require.True(t, qap.IsValidElements(sol))
+
+			setup := playsnark.NewGroth16TrustedSetup(qap)
+			proof := playsnark.Groth16ProveElements(setup, qap, sol)
+			public, err := r1cs.PublicValues(&cubic{Y: 35})
+			require.NoError(t, err)
+			require.Len(t, public, 2)
+			require.True(t, setup.VerifyingKey().VerifyElements(proof, public))
+			public, err = r1cs.PublicValues(&cubic{Y: 36})
+			require.NoError(t, err)
+			require.False(t, setup.VerifyingKey().VerifyElements(proof, public))
+
+			_, err = r1cs.Solve(&cubic{X: 3, Y: 36})
+			require.EqualError(t, err, "constraint 2 is not satisfied")
+		})
+	}
+}
+
+// operations uses all the API with variables and constants
+type operations struct {
+	A, B Variable `snark:"public"`
+	Sel  Variable
+	Res  struct {
+		Sub, Inv, Select, Const Variable
+	}
+}
+
+func (c *operations) Define(api API) error {
+	api.AssertIsEqual(c.Res.Sub, api.Sub(c.A, c.B, 1))
+	api.AssertIsEqual(c.Res.Inv, api.Inverse(c.A))
+	api.AssertIsEqual(c.Res.Select, api.Select(c.Sel, c.A, c.B))
+	// constant folding does not add constraints
+	k := api.Mul(api.Inverse(4), api.Add(2, 6), api.Select(1, 3, 5))
+	api.AssertIsEqual(c.Res.Const, api.Mul(k, c.A))
+	return nil
+}
+
+func TestOperations(t *testing.T) {
+	curve := playsnark.BLS12381
+	r1cs, err := Compile(curve, &operations{})
+	require.NoError(t, err)
+	// sub: 1, inverse: 2, select: 3 with the booleanity, constant: 1
+	require.Equal(t, 7, r1cs.NbConstraints())
+	require.Equal(t, []string{"A", "B"}, r1cs.Public())
+	require.Equal(t, []string{"Sel", "Res.Sub", "Res.Inv", "Res.Select", "Res.Const"}, r1cs.Secret())
+
+	inv := curve.NewElement().Inv(curve.Element(5))
+	assignment := func(a, b, sel Variable) *operations {
+		c := &operations{A: a, B: b, Sel: sel}
+		c.Res.Sub = big.NewInt(1)
+		c.Res.Inv = inv
+		c.Res.Select = 3
+		c.Res.Const = "30"
+		return c
+	}
+	sol, err := r1cs.Solve(assignment(5, 3, 0))
+	require.NoError(t, err)
+	qap := r1cs.QAP()
+	require.True(t, qap.IsValidElements(sol))
+	_, err = r1cs.Solve(assignment(5, 3, 1))
+	require.Error(t, err)
+	_, err = r1cs.Solve(assignment(0, 3, 0))
+	require.EqualError(t, err, "inverse of zero")
+
+	// a malicious selector which is not a bit
+	_, err = r1cs.Solve(assignment(5, 3, 2))
+	require.Error(t, err)
+}
+
+// tags covers the parsing of the inputs
+type tags struct {
+	Path   [2]Variable `snark:"public"`
+	Leaves []Variable
+	Nested struct {
+		A Variable `snark:"public"`
+		B Variable
+	} `snark:"secret"`
+	Skip    Variable `snark:"-"`
+	Other   int
+	private Variable
+}
+
+func (c *tags) Define(api API) error {
+	api.AssertIsEqual(api.Add(c.Path[0], c.Path[1]), api.Mul(c.Leaves[0], c.Nested.A, c.Nested.B))
+	return nil
+}
+
+func TestInputs(t *testing.T) {
+	r1cs, err := Compile(playsnark.BLS12381, &tags{Leaves: make([]Variable, 1)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Path[0]", "Path[1]", "Nested.A"}, r1cs.Public())
+	require.Equal(t, []string{"Leaves[0]", "Nested.B"}, r1cs.Secret())
+
+	assignment := &tags{Path: [2]Variable{2, 4}, Leaves: []Variable{1}}
+	assignment.Nested.A = 2
+	assignment.Nested.B = 3
+	_, err = r1cs.Solve(assignment)
+	require.NoError(t, err)
+	// the verifier allocates the slices but doesn't fill the secret inputs
+	public, err := r1cs.PublicValues(&tags{Path: [2]Variable{2, 4}, Leaves: make([]Variable, 1), Nested: assignment.Nested})
+	require.NoError(t, err)
+	require.Len(t, public, 4)
+
+	assignment.Nested.B = nil
+	_, err = r1cs.Solve(assignment)
+	require.EqualError(t, err, `input "Nested.B": missing value`)
+	assignment.Nested.B = 3.0
+	_, err = r1cs.Solve(assignment)
+	require.EqualError(t, err, `input "Nested.B": unsupported value of type float64`)
+	_, err = r1cs.Solve(&tags{Path: [2]Variable{2, 4}})
+	require.EqualError(t, err, "assignment has 4 inputs instead of 5")
+	// an element of the BN254 field
+	assignment.Nested.B = playsnark.BN254.Element(3)
+	_, err = r1cs.Solve(assignment)
+	require.EqualError(t, err, `input "Nested.B": element of another field`)
+}
+
+type failing struct {
+	X Variable
+	f func(api API, x Variable) error
+}
+
+func (c *failing) Define(api API) error {
+	return c.f(api, c.X)
+}
+
+func TestCompileErrors(t *testing.T) {
+	var tests = []struct {
+		name string
+		f    func(api API, x Variable) error
+		err  string
+	}{
+		{"Define", func(api API, x Variable) error { return errors.New("oops") }, "define: oops"},
+		{"NoConstraints", func(api API, x Variable) error { api.Add(x, x); return nil }, "circuit without constraints"},
+		{"InverseOfZero", func(api API, x Variable) error { api.AssertIsEqual(x, api.Inverse(0)); return nil }, "inverse of zero"},
+		{"DifferentConstants", func(api API, x Variable) error { api.AssertIsEqual(1, 2); return nil }, "assertion between different constants"},
+		{"Selector", func(api API, x Variable) error { api.AssertIsEqual(x, api.Select(2, x, 1)); return nil }, "selector is not boolean"},
+		{"Constant", func(api API, x Variable) error { api.AssertIsEqual(x, 1.5); return nil }, "unsupported value of type float64"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Compile(playsnark.BLS12381, &failing{f: test.f})
+			require.EqualError(t, err, test.err)
+		})
+	}
+	_, err := Compile(playsnark.BLS12381, &tagError{})
+	require.EqualError(t, err, `field X: invalid tag "private"`)
+	_, err = Compile(playsnark.BLS12381, (*cubic)(nil))
+	require.EqualError(t, err, "circuit must be a pointer to a struct")
+}
+
+type tagError struct {
+	X Variable `snark:"private"`
+}
+
+func (c *tagError) Define(api API) error {
+	return nil
+}
+
+// division proves the knowledge of the quotient and remainder of x by 3 with
+// a hint
+type division struct {
+	X Variable `snark:"public"`
+	R Variable `snark:"public"`
+}
+
+func divide(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	outputs[0].DivMod(inputs[0], big.NewInt(3), outputs[1])
+	return nil
+}
+
+func (c *division) Define(api API) error {
+	res := api.NewHint(divide, 2, c.X)
+	api.AssertProduct(res[0], 3, api.Sub(c.X, res[1]))
+	api.AssertIsEqual(res[1], c.R)
+	api.AssertIsBoolean(api.Sub(c.R, 1))
+	api.AssertIsBoolean(1)
+	return nil
+}
+
+func TestHint(t *testing.T) {
+	r1cs, err := Compile(playsnark.BLS12381, &division{})
+	require.NoError(t, err)
+	require.Equal(t, 3, r1cs.NbConstraints())
+	sol, err := r1cs.Solve(&division{X: 14, R: 2})
+	require.NoError(t, err)
+	require.True(t, sol[3].Equal(playsnark.BLS12381.Element(4)))
+	_, err = r1cs.Solve(&division{X: 15, R: 0})
+	require.EqualError(t, err, "constraint 2 is not satisfied")
+
+	// a wrong quotient doesn't satisfy the constraints
+	sol[3] = playsnark.BLS12381.Element(5)
+	require.False(t, r1cs.IsSatisfied(sol))
+
+	_, err = Compile(playsnark.BLS12381, &failing{f: func(api API, x Variable) error {
+		api.AssertIsBoolean(2)
+		return nil
+	}})
+	require.EqualError(t, err, "constant is not boolean")
+}
@@ -0,0 +1,85 @@
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type visibility int
+
+const (
+	secret visibility = iota
+	public
+)
+
+// input is a Variable field of a circuit
+type input struct {
+	// name is the path of the field, as "Path[2].Left"
+	name       string
+	visibility visibility
+	value      reflect.Value
+}
+
+var variableType = reflect.TypeOf((*Variable)(nil)).Elem()
+
+// parseInputs returns the Variable fields of the circuit in the order of
+// their declaration
+func parseInputs(circuit Circuit) ([]input, error) {
+	v := reflect.ValueOf(circuit)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("circuit must be a pointer to a struct")
+	}
+	var inputs []input
+	if err := walk(v.Elem(), "", secret, &inputs); err != nil {
+		return nil, err
+	}
+	return inputs, nil
+}
+
+func walk(v reflect.Value, name string, vis visibility, inputs *[]input) error {
+	if v.Type() == variableType {
+		*inputs = append(*inputs, input{name: name, visibility: vis, value: v})
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if field.PkgPath != "" {
+				// unexported
+				continue
+			}
+			fieldVis := vis
+			switch tag := strings.TrimSpace(field.Tag.Get("snark")); tag {
+			case "-":
+				continue
+			case "public":
+				fieldVis = public
+			case "secret":
+				fieldVis = secret
+			case "":
+			default:
+				return fmt.Errorf("field %s: invalid tag %q", join(name, field.Name), tag)
+			}
+			if err := walk(v.Field(i), join(name, field.Name), fieldVis, inputs); err != nil {
+				return err
+			}
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			if err := walk(v.Index(i), fmt.Sprintf("%s[%d]", name, i), vis, inputs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
@@ -0,0 +1,151 @@
+package frontend
+
+import (
+	"fmt"
+
+	"github.com/nikkolasg/playsnark"
+)
+
+// R1CS is a compiled circuit: its constraints over the scalar field of a
+// curve, and the steps computing the values of the variables allocated by the
+// API from the inputs. The variables are "const", the public inputs, the
+// secret inputs and then the allocated ones.
+type R1CS struct {
+	curve       playsnark.Curve
+	public      []string
+	secret      []string
+	nbVars      int
+	constraints []playsnark.Constraint
+	steps       []step
+}
+
+// Curve returns the curve over whose scalar field the circuit is defined
+func (r *R1CS) Curve() playsnark.Curve {
+	return r.curve
+}
+
+// Public returns the names of the public inputs
+func (r *R1CS) Public() []string {
+	return r.public
+}
+
+// Secret returns the names of the secret inputs
+func (r *R1CS) Secret() []string {
+	return r.secret
+}
+
+// NbVariables returns the number of variables, "const" included
+func (r *R1CS) NbVariables() int {
+	return r.nbVars
+}
+
+// NbConstraints returns the number of constraints
+func (r *R1CS) NbConstraints() int {
+	return len(r.constraints)
+}
+
+// QAP returns the QAP of the constraints, to give to Groth16. The public
+// values are "const" and the public inputs.
+func (r *R1CS) QAP() playsnark.QAP {
+	return playsnark.NewQAP(r.curve, r.nbVars, 1+len(r.public), r.constraints)
+}
+
+// Solve returns the values of all the variables of the R1CS from the values
+// of the inputs in the assignment, a circuit of the same type as the compiled
+// one. It fails if the constraints are not satisfied.
+func (r *R1CS) Solve(assignment Circuit) ([]Element, error) {
+	values, err := r.inputValues(assignment, true)
+	if err != nil {
+		return nil, err
+	}
+	s := &solver{curve: r.curve, values: make([]Element, r.nbVars)}
+	copy(s.values, values)
+	for _, step := range r.steps {
+		if err := step(s); err != nil {
+			return nil, err
+		}
+	}
+	if i := r.unsatisfied(s.values); i >= 0 {
+		return nil, fmt.Errorf("constraint %d is not satisfied", i)
+	}
+	return s.values, nil
+}
+
+// PublicValues returns the values of "const" and of the public inputs in the
+// assignment, which the verifier gives to Groth16. The secret inputs of the
+// assignment are not read.
+func (r *R1CS) PublicValues(assignment Circuit) ([]Element, error) {
+	return r.inputValues(assignment, false)
+}
+
+// IsSatisfied returns true if the values of the variables satisfy all the
+// constraints
+func (r *R1CS) IsSatisfied(values []Element) bool {
+	return len(values) == r.nbVars && r.unsatisfied(values) < 0
+}
+
+// unsatisfied returns the index of the first constraint not satisfied by the
+// values, or -1
+func (r *R1CS) unsatisfied(values []Element) int {
+	s := &solver{curve: r.curve, values: values}
+	for i, cs := range r.constraints {
+		left := s.eval(cs.Left)
+		left = left.Mul(left, s.eval(cs.Right))
+		if !left.Equal(s.eval(cs.Out)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// inputValues returns "const" and the values of the public inputs of the
+// assignment, followed by the secret ones if withSecret is true
+func (r *R1CS) inputValues(assignment Circuit, withSecret bool) ([]Element, error) {
+	inputs, err := parseInputs(assignment)
+	if err != nil {
+		return nil, err
+	}
+	if len(inputs) != len(r.public)+len(r.secret) {
+		return nil, fmt.Errorf("assignment has %d inputs instead of %d", len(inputs), len(r.public)+len(r.secret))
+	}
+	values := []Element{r.curve.NewElement().One()}
+	for _, names := range []struct {
+		vis   visibility
+		names []string
+	}{{public, r.public}, {secret, r.secret}} {
+		if names.vis == secret && !withSecret {
+			break
+		}
+		var i int
+		for _, in := range inputs {
+			if in.visibility != names.vis {
+				continue
+			}
+			if i >= len(names.names) || in.name != names.names[i] {
+				return nil, fmt.Errorf("assignment input %q does not match the circuit", in.name)
+			}
+			v, err := toElement(r.curve, in.value.Interface())
+			if err != nil {
+				return nil, fmt.Errorf("input %q: %w", in.name, err)
+			}
+			values = append(values, v)
+			i++
+		}
+	}
+	return values, nil
+}
+
+// solver holds the values of the variables while solving the R1CS
+type solver struct {
+	curve  playsnark.Curve
+	values []Element
+}
+
+// eval returns the value of the linear combination
+func (s *solver) eval(e map[int]Element) Element {
+	res := s.curve.NewElement()
+	for i, c := range e {
+		res = res.Add(res, s.curve.NewElement().Mul(c, s.values[i]))
+	}
+	return res
+}
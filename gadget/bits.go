@@ -0,0 +1,119 @@
+// Package gadget contains reusable pieces of circuits written against the API
+// of the frontend package. The gadgets on bits expect their inputs to be
+// already constrained to be boolean, as the outputs of ToBinary or of the
+// other gadgets are: checking it again would cost one constraint per bit.
+package gadget
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/nikkolasg/playsnark/frontend"
+)
+
+type Variable = frontend.Variable
+
+// ToBinary returns the nbits bits of x, least significant first, and
+// constrains x to be smaller than 2^nbits. It costs nbits + 1 constraints.
+// The decomposition is unique since nbits must be smaller than the number of
+// bits of the field.
+func ToBinary(api frontend.API, x Variable, nbits int) []Variable {
+	if nbits <= 0 || nbits >= api.Curve().Modulus().BitLen() {
+		panic(fmt.Sprintf("gadget: cannot decompose on %d bits", nbits))
+	}
+	bits := api.NewHint(binaryHint, nbits, x)
+	for _, b := range bits {
+		api.AssertIsBoolean(b)
+	}
+	api.AssertIsEqual(FromBinary(api, bits...), x)
+	return bits
+}
+
+// binaryHint returns the bits of the input, least significant first
+func binaryHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	for i := range outputs {
+		outputs[i].SetUint64(uint64(inputs[0].Bit(i)))
+	}
+	return nil
+}
+
+// FromBinary returns the integer SUM(bits[i] * 2^i). It costs no
+// constraint and doesn't check the bits are boolean.
+func FromBinary(api frontend.API, bits ...Variable) Variable {
+	var res Variable = 0
+	coeff := big.NewInt(1)
+	for _, b := range bits {
+		res = api.Add(res, api.Mul(b, new(big.Int).Set(coeff)))
+		coeff.Lsh(coeff, 1)
+	}
+	return res
+}
+
+// And returns a AND b, with one constraint
+func And(api frontend.API, a, b Variable) Variable {
+	return api.Mul(a, b)
+}
+
+// Or returns a OR b = a + b - a*b, with one constraint
+func Or(api frontend.API, a, b Variable) Variable {
+	return api.Sub(api.Add(a, b), api.Mul(a, b))
+}
+
+// Xor returns a XOR b = a + b - 2*a*b, with one constraint
+func Xor(api frontend.API, a, b Variable) Variable {
+	return api.Sub(api.Add(a, b), api.Mul(2, a, b))
+}
+
+// Not returns 1 - a, without constraint
+func Not(api frontend.API, a Variable) Variable {
+	return api.Sub(1, a)
+}
+
+// Select returns the values of a if b is 1 and of c if b is 0. It constrains b
+// to be boolean once and costs one constraint per value.
+func Select(api frontend.API, b Variable, a, c []Variable) []Variable {
+	if len(a) != len(c) {
+		panic("gadget: selecting between vectors of different lengths")
+	}
+	api.AssertIsBoolean(b)
+	res := make([]Variable, len(a))
+	for i := range a {
+		// c + b * (a - c)
+		res[i] = api.Add(c[i], api.Mul(b, api.Sub(a[i], c[i])))
+	}
+	return res
+}
+
+// Swap returns (b, a) if s is 1 and (a, b) if s is 0, with two constraints
+// including the booleanity of s
+func Swap(api frontend.API, s, a, b Variable) (Variable, Variable) {
+	api.AssertIsBoolean(s)
+	// t = s * (b - a) is moved from one side to the other
+	t := api.Mul(s, api.Sub(b, a))
+	return api.Add(a, t), api.Sub(b, t)
+}
+
+// IsZero returns 1 if x is zero and 0 otherwise, with two constraints:
+// given inv = 1/x, or 0 if x is zero,
+//
+//	x * inv = 1 - res
+//	x * res = 0
+func IsZero(api frontend.API, x Variable) Variable {
+	inv := api.NewHint(inverseHint, 1, x)[0]
+	res := api.Sub(1, api.Mul(x, inv))
+	api.AssertProduct(x, res, 0)
+	return res
+}
+
+// inverseHint returns the inverse of the input, or 0 if it is zero
+func inverseHint(field *big.Int, inputs []*big.Int, outputs []*big.Int) error {
+	if inputs[0].Sign() != 0 {
+		outputs[0].ModInverse(inputs[0], field)
+	}
+	return nil
+}
+
+// IsEqual returns 1 if a equals b and 0 otherwise, with two constraints
+func IsEqual(api frontend.API, a, b Variable) Variable {
+	return IsZero(api, api.Sub(a, b))
+}
@@ -0,0 +1,171 @@
+package gadget
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+// gadgetCircuit asserts the outputs of a gadget are equal to the public
+// outputs, such that its cost is the number of constraints minus the number of
+// outputs
+type gadgetCircuit struct {
+	In  []Variable `snark:"secret"`
+	Out []Variable `snark:"public"`
+	f   func(api frontend.API, in []Variable) []Variable
+}
+
+func (c *gadgetCircuit) Define(api frontend.API) error {
+	for i, out := range c.f(api, c.In) {
+		api.AssertIsEqual(out, c.Out[i])
+	}
+	return nil
+}
+
+// compile returns the R1CS of the gadget and its cost
+func compile(t *testing.T, nbIn, nbOut int, f func(api frontend.API, in []Variable) []Variable) (*frontend.R1CS, int) {
+	c := &gadgetCircuit{In: make([]Variable, nbIn), Out: make([]Variable, nbOut), f: f}
+	r1cs, err := frontend.Compile(playsnark.BLS12381, c)
+	require.NoError(t, err)
+	return r1cs, r1cs.NbConstraints() - nbOut
+}
+
+// solve solves the R1CS for the inputs and expected outputs
+func solve(r1cs *frontend.R1CS, in, out []Variable) ([]playsnark.Element, error) {
+	return r1cs.Solve(&gadgetCircuit{In: in, Out: out})
+}
+
+func values(vs ...int) []Variable {
+	res := make([]Variable, len(vs))
+	for i, v := range vs {
+		res[i] = v
+	}
+	return res
+}
+
+func TestToBinary(t *testing.T) {
+	r1cs, cost := compile(t, 1, 8, func(api frontend.API, in []Variable) []Variable {
+		return ToBinary(api, in[0], 8)
+	})
+	// one booleanity constraint per bit and the recomposition
+	require.Equal(t, 9, cost)
+	_, err := solve(r1cs, values(200), values(0, 0, 0, 1, 0, 0, 1, 1))
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(255), values(1, 1, 1, 1, 1, 1, 1, 1))
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(200), values(1, 0, 0, 1, 0, 0, 1, 1))
+	require.Error(t, err)
+	// too large values don't have a decomposition on 8 bits, in particular
+	// -1 = r - 1 doesn't wrap around
+	_, err = solve(r1cs, values(256), values(0, 0, 0, 0, 0, 0, 0, 0))
+	require.Error(t, err)
+	r := playsnark.BLS12381.Modulus()
+	_, err = solve(r1cs, []Variable{new(big.Int).Sub(r, big.NewInt(1))}, values(1, 1, 1, 1, 1, 1, 1, 1))
+	require.Error(t, err)
+
+	require.Panics(t, func() {
+		compile(t, 1, 1, func(api frontend.API, in []Variable) []Variable {
+			return ToBinary(api, in[0], r.BitLen())
+		})
+	})
+}
+
+func TestFromBinary(t *testing.T) {
+	r1cs, cost := compile(t, 4, 1, func(api frontend.API, in []Variable) []Variable {
+		return []Variable{FromBinary(api, in...)}
+	})
+	require.Equal(t, 0, cost)
+	_, err := solve(r1cs, values(1, 0, 1, 1), values(13))
+	require.NoError(t, err)
+
+	// ToBinary and FromBinary are inverses
+	r1cs, cost = compile(t, 1, 1, func(api frontend.API, in []Variable) []Variable {
+		return []Variable{FromBinary(api, ToBinary(api, in[0], 16)...)}
+	})
+	require.Equal(t, 17, cost)
+	_, err = solve(r1cs, values(40000), values(40000))
+	require.NoError(t, err)
+}
+
+func TestLogic(t *testing.T) {
+	var tests = []struct {
+		name  string
+		f     func(api frontend.API, a, b Variable) Variable
+		cost  int
+		table [4]int
+	}{
+		{"And", And, 1, [4]int{0, 0, 0, 1}},
+		{"Or", Or, 1, [4]int{0, 1, 1, 1}},
+		{"Xor", Xor, 1, [4]int{0, 1, 1, 0}},
+		{"NotA", func(api frontend.API, a, b Variable) Variable { return Not(api, a) }, 0, [4]int{1, 1, 0, 0}},
+		{"IsEqual", IsEqual, 2, [4]int{1, 0, 0, 1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r1cs, cost := compile(t, 2, 1, func(api frontend.API, in []Variable) []Variable {
+				return []Variable{test.f(api, in[0], in[1])}
+			})
+			require.Equal(t, test.cost, cost)
+			for i, expected := range test.table {
+				a, b := i>>1, i&1
+				_, err := solve(r1cs, values(a, b), values(expected))
+				require.NoError(t, err, "%d %d", a, b)
+				_, err = solve(r1cs, values(a, b), values(1-expected))
+				require.Error(t, err, "%d %d", a, b)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	r1cs, cost := compile(t, 5, 2, func(api frontend.API, in []Variable) []Variable {
+		return Select(api, in[0], in[1:3], in[3:5])
+	})
+	// the booleanity of the selector and one per value
+	require.Equal(t, 3, cost)
+	_, err := solve(r1cs, values(1, 10, 11, 20, 21), values(10, 11))
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(0, 10, 11, 20, 21), values(20, 21))
+	require.NoError(t, err)
+	// 2 * (a - c) + c
+	_, err = solve(r1cs, values(2, 10, 11, 20, 21), values(0, 1))
+	require.Error(t, err)
+
+	r1cs, cost = compile(t, 3, 2, func(api frontend.API, in []Variable) []Variable {
+		a, b := Swap(api, in[0], in[1], in[2])
+		return []Variable{a, b}
+	})
+	require.Equal(t, 2, cost)
+	_, err = solve(r1cs, values(0, 3, 4), values(3, 4))
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(1, 3, 4), values(4, 3))
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(1, 3, 4), values(3, 4))
+	require.Error(t, err)
+}
+
+func TestIsZero(t *testing.T) {
+	r1cs, cost := compile(t, 1, 1, func(api frontend.API, in []Variable) []Variable {
+		return []Variable{IsZero(api, in[0])}
+	})
+	require.Equal(t, 2, cost)
+	_, err := solve(r1cs, values(0), values(1))
+	require.NoError(t, err)
+	sol, err := solve(r1cs, values(5), values(0))
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(5), values(1))
+	require.Error(t, err)
+
+	// a malicious prover can't claim 5 is zero with any inverse: the
+	// variables are "const", the output, the input, the inverse and x * inv
+	c := playsnark.BLS12381
+	sol[1] = c.Element(1)
+	for _, inv := range []playsnark.Element{c.Element(0), c.NewElement().Inv(c.Element(5))} {
+		sol[3] = inv
+		sol[4] = c.NewElement().Mul(c.Element(5), inv)
+		require.False(t, r1cs.IsSatisfied(sol))
+	}
+}
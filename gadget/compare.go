@@ -0,0 +1,70 @@
+package gadget
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/nikkolasg/playsnark/frontend"
+)
+
+// The comparisons are on integers of nbits bits. Since the field wraps
+// around, a "negative" value -x is the large integer r - x: the gadgets first
+// constrain their inputs to be smaller than 2^nbits, such that the
+// differences of the inputs are in (-2^nbits, 2^nbits) and never wrap around
+// as long as 2^(nbits+1) < r.
+
+// checkComparable panics if the field is too small for comparing integers of
+// nbits bits
+func checkComparable(api frontend.API, nbits int) {
+	if nbits <= 0 || nbits+1 >= api.Curve().Modulus().BitLen() {
+		panic(fmt.Sprintf("gadget: cannot compare integers of %d bits", nbits))
+	}
+}
+
+// IsLessThan returns 1 if a < b and 0 otherwise, a and b being constrained to
+// be smaller than 2^nbits. It costs 3*nbits + 4 constraints: a - b + 2^nbits
+// is in [0, 2^(nbits+1)) and its bit nbits is set iff a >= b.
+func IsLessThan(api frontend.API, a, b Variable, nbits int) Variable {
+	checkComparable(api, nbits)
+	ToBinary(api, a, nbits)
+	ToBinary(api, b, nbits)
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(nbits))
+	bits := ToBinary(api, api.Add(api.Sub(a, b), shift), nbits+1)
+	return Not(api, bits[nbits])
+}
+
+// AssertLessThan constrains a < b, a and b being constrained to be smaller
+// than 2^nbits. It costs 3*nbits + 3 constraints: b - a - 1 must be smaller
+// than 2^nbits, which it isn't when a >= b since it wraps around to a value
+// larger than r - 2^nbits.
+func AssertLessThan(api frontend.API, a, b Variable, nbits int) {
+	checkComparable(api, nbits)
+	ToBinary(api, a, nbits)
+	ToBinary(api, b, nbits)
+	ToBinary(api, api.Sub(b, a, 1), nbits)
+}
+
+// AssertInRange constrains lo <= x <= hi. With n the number of bits of
+// hi - lo, it costs 2*n + 2 constraints: x - lo and hi - x must both be
+// smaller than 2^n, which they can't be if x is out of the range since their
+// sum is hi - lo.
+func AssertInRange(api frontend.API, x Variable, lo, hi *big.Int) {
+	width := new(big.Int).Sub(hi, lo)
+	if width.Sign() < 0 {
+		panic("gadget: empty range")
+	}
+	if width.Sign() == 0 {
+		api.AssertIsEqual(x, lo)
+		return
+	}
+	nbits := width.BitLen()
+	checkComparable(api, nbits)
+	ToBinary(api, api.Sub(x, lo), nbits)
+	ToBinary(api, api.Sub(hi, x), nbits)
+}
+
+// AssertNotEqual constrains a != b with one constraint, since a - b must have
+// an inverse
+func AssertNotEqual(api frontend.API, a, b Variable) {
+	api.Inverse(api.Sub(a, b))
+}
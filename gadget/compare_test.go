@@ -0,0 +1,123 @@
+package gadget
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+// minus returns r - x, which is -x in the field
+func minus(x int64) *big.Int {
+	r := playsnark.BLS12381.Modulus()
+	return r.Sub(r, big.NewInt(x))
+}
+
+func TestIsLessThan(t *testing.T) {
+	r1cs, cost := compile(t, 2, 1, func(api frontend.API, in []Variable) []Variable {
+		return []Variable{IsLessThan(api, in[0], in[1], 8)}
+	})
+	require.Equal(t, 3*8+4, cost)
+	var tests = []struct {
+		a, b Variable
+		less int
+	}{
+		{0, 0, 0},
+		{0, 1, 1},
+		{1, 0, 0},
+		{17, 18, 1},
+		{18, 18, 0},
+		{19, 18, 0},
+		{254, 255, 1},
+		{255, 255, 0},
+		{255, 0, 0},
+		{0, 255, 1},
+	}
+	for _, test := range tests {
+		_, err := solve(r1cs, []Variable{test.a, test.b}, values(test.less))
+		require.NoError(t, err, "%v < %v", test.a, test.b)
+		_, err = solve(r1cs, []Variable{test.a, test.b}, values(1-test.less))
+		require.Error(t, err, "%v < %v", test.a, test.b)
+	}
+	// the values out of range are rejected whatever the output: -1 would be
+	// less than 0 without the range checks
+	for _, in := range [][]Variable{{256, 0}, {0, 256}, {minus(1), 0}, {0, minus(1)}, {minus(1), minus(2)}} {
+		for _, out := range []int{0, 1} {
+			_, err := solve(r1cs, in, values(out))
+			require.Error(t, err, "%v < %v", in[0], in[1])
+		}
+	}
+}
+
+func TestAssertLessThan(t *testing.T) {
+	r1cs, cost := compile(t, 2, 0, func(api frontend.API, in []Variable) []Variable {
+		AssertLessThan(api, in[0], in[1], 8)
+		return nil
+	})
+	require.Equal(t, 3*8+3, cost)
+	for _, in := range [][]Variable{{0, 1}, {17, 18}, {0, 255}, {254, 255}} {
+		_, err := solve(r1cs, in, nil)
+		require.NoError(t, err, "%v < %v", in[0], in[1])
+	}
+	for _, in := range [][]Variable{
+		{0, 0}, {18, 18}, {19, 18}, {255, 255}, {255, 0},
+		// out of range or wrapping around
+		{0, 256}, {255, 256}, {minus(1), 0}, {minus(1), 5}, {5, minus(1)},
+	} {
+		_, err := solve(r1cs, in, nil)
+		require.Error(t, err, "%v < %v", in[0], in[1])
+	}
+
+	require.Panics(t, func() {
+		compile(t, 2, 0, func(api frontend.API, in []Variable) []Variable {
+			AssertLessThan(api, in[0], in[1], playsnark.BLS12381.Modulus().BitLen()-1)
+			return nil
+		})
+	})
+}
+
+func TestAssertInRange(t *testing.T) {
+	// 18 <= age <= 150, 150 - 18 = 132 has 8 bits
+	r1cs, cost := compile(t, 1, 0, func(api frontend.API, in []Variable) []Variable {
+		AssertInRange(api, in[0], big.NewInt(18), big.NewInt(150))
+		return nil
+	})
+	require.Equal(t, 2*8+2, cost)
+	for _, age := range []int{18, 19, 100, 149, 150} {
+		_, err := solve(r1cs, values(age), nil)
+		require.NoError(t, err, age)
+	}
+	for _, age := range []Variable{0, 17, 151, 255, 256, 18 + 256, minus(1), minus(18)} {
+		_, err := solve(r1cs, []Variable{age}, nil)
+		require.Error(t, err, age)
+	}
+
+	r1cs, cost = compile(t, 1, 0, func(api frontend.API, in []Variable) []Variable {
+		AssertInRange(api, in[0], big.NewInt(7), big.NewInt(7))
+		return nil
+	})
+	require.Equal(t, 1, cost)
+	_, err := solve(r1cs, values(7), nil)
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(8), nil)
+	require.Error(t, err)
+}
+
+func TestAssertNotEqual(t *testing.T) {
+	r1cs, cost := compile(t, 2, 0, func(api frontend.API, in []Variable) []Variable {
+		AssertNotEqual(api, in[0], in[1])
+		return nil
+	})
+	require.Equal(t, 1, cost)
+	_, err := solve(r1cs, values(3, 4), nil)
+	require.NoError(t, err)
+	_, err = solve(r1cs, []Variable{minus(1), 0}, nil)
+	require.NoError(t, err)
+	_, err = solve(r1cs, values(4, 4), nil)
+	require.Error(t, err)
+	// r is 0 in the field
+	_, err = solve(r1cs, []Variable{playsnark.BLS12381.Modulus(), 0}, nil)
+	require.Error(t, err)
+}
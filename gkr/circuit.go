@@ -0,0 +1,186 @@
+package gkr
+
+import (
+	"fmt"
+
+	"github.com/nikkolasg/playsnark/sumcheck"
+)
+
+type Element = sumcheck.Element
+
+// GateType is the operation of a gate
+type GateType int
+
+const (
+	Add GateType = iota
+	Mul
+)
+
+// Gate computes Left + Right or Left * Right where Left and Right are the
+// indices of its inputs in the next layer
+type Gate struct {
+	Type  GateType
+	Left  int
+	Right int
+}
+
+// Layer is a list of gates whose inputs are the values of the next layer
+type Layer []Gate
+
+// Circuit is a layered arithmetic circuit: Layers[0] is the output layer and
+// the gates of the last layer read the inputs of the circuit.
+type Circuit struct {
+	Layers   []Layer
+	NbInputs int
+}
+
+// size returns the number of values of the i-th layer, where the layer after
+// the last one is the input layer
+func (c Circuit) size(i int) int {
+	if i == len(c.Layers) {
+		return c.NbInputs
+	}
+	return len(c.Layers[i])
+}
+
+// Evaluate returns the values of all the layers, starting with the outputs
+// and ending with the inputs. It panics if the circuit is malformed.
+func (c Circuit) Evaluate(inputs []Element) [][]Element {
+	if len(inputs) != c.NbInputs {
+		panic("gkr: wrong number of inputs")
+	}
+	values := make([][]Element, len(c.Layers)+1)
+	values[len(c.Layers)] = inputs
+	for i := len(c.Layers) - 1; i >= 0; i-- {
+		next := values[i+1]
+		for _, g := range c.Layers[i] {
+			if g.Left < 0 || g.Left >= len(next) || g.Right < 0 || g.Right >= len(next) {
+				panic(fmt.Sprintf("gkr: gate wired outside of layer %d", i+1))
+			}
+			v := sumcheck.NewElement()
+			switch g.Type {
+			case Add:
+				v = v.Add(next[g.Left], next[g.Right])
+			case Mul:
+				v = v.Mul(next[g.Left], next[g.Right])
+			default:
+				panic("gkr: unknown gate type")
+			}
+			values[i] = append(values[i], v)
+		}
+	}
+	return values
+}
+
+// Builder converts any circuit of addition and multiplication gates into a
+// layered circuit. Each gate is placed in the layer of its depth, and the
+// values used by a gate more than one layer above are relayed through the
+// intermediate layers by multiplying them by the constant 1. This constant is
+// always the first input of the built circuits.
+type Builder struct {
+	nbInputs int
+	wires    []wire
+}
+
+type wire struct {
+	gate  Gate
+	input int
+	depth int
+}
+
+// NewBuilder returns a builder with no wires, except the constant 1
+func NewBuilder() *Builder {
+	b := &Builder{}
+	b.Input()
+	return b
+}
+
+// One returns the wire of the constant 1
+func (b *Builder) One() int {
+	return 0
+}
+
+// Input returns a new input wire
+func (b *Builder) Input() int {
+	b.wires = append(b.wires, wire{input: b.nbInputs})
+	b.nbInputs++
+	return len(b.wires) - 1
+}
+
+// Add returns the wire of left + right
+func (b *Builder) Add(left, right int) int {
+	return b.gate(Add, left, right)
+}
+
+// Mul returns the wire of left * right
+func (b *Builder) Mul(left, right int) int {
+	return b.gate(Mul, left, right)
+}
+
+func (b *Builder) gate(t GateType, left, right int) int {
+	depth := b.wires[left].depth
+	if d := b.wires[right].depth; d > depth {
+		depth = d
+	}
+	b.wires = append(b.wires, wire{gate: Gate{Type: t, Left: left, Right: right}, depth: depth + 1})
+	return len(b.wires) - 1
+}
+
+// Inputs returns the inputs of the built circuit for the given values of the
+// input wires, in order: the constant 1 followed by the values.
+func (b *Builder) Inputs(values ...Element) []Element {
+	return append([]Element{sumcheck.NewElement().One()}, values...)
+}
+
+// Build returns the layered circuit computing the given wires, in order, in
+// its output layer
+func (b *Builder) Build(outputs ...int) Circuit {
+	depth := 1
+	for _, o := range outputs {
+		if d := b.wires[o].depth; d > depth {
+			depth = d
+		}
+	}
+	l := &layering{b: b, layers: make([]Layer, depth+1), pos: make(map[[2]int]int)}
+	for _, o := range outputs {
+		l.at(o, depth)
+	}
+	c := Circuit{NbInputs: b.nbInputs}
+	for k := depth; k >= 1; k-- {
+		c.Layers = append(c.Layers, l.layers[k])
+	}
+	return c
+}
+
+// layering places the wires in the layers, layers[k] containing the gates of
+// depth k
+type layering struct {
+	b      *Builder
+	layers []Layer
+	// pos maps [wire, depth] to the index of the value of the wire in the
+	// layer of the given depth
+	pos map[[2]int]int
+}
+
+// at returns the index of the value of the wire in the layer k, adding the
+// gate or the relay gates if needed
+func (l *layering) at(w, k int) int {
+	key := [2]int{w, k}
+	if i, ok := l.pos[key]; ok {
+		return i
+	}
+	wi := l.b.wires[w]
+	var g Gate
+	switch {
+	case wi.depth == 0 && k == 0:
+		return wi.input
+	case wi.depth == k:
+		g = Gate{Type: wi.gate.Type, Left: l.at(wi.gate.Left, k-1), Right: l.at(wi.gate.Right, k-1)}
+	default:
+		// relay w * 1
+		g = Gate{Type: Mul, Left: l.at(w, k-1), Right: l.at(l.b.One(), k-1)}
+	}
+	l.layers[k] = append(l.layers[k], g)
+	l.pos[key] = len(l.layers[k]) - 1
+	return l.pos[key]
+}
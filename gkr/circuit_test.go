@@ -0,0 +1,59 @@
+package gkr
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark/sumcheck"
+	"github.com/stretchr/testify/require"
+)
+
+func elements(vs ...int64) []Element {
+	out := make([]Element, len(vs))
+	for i, v := range vs {
+		out[i] = sumcheck.NewElement().SetInt64(v)
+	}
+	return out
+}
+
+// createCircuit returns the layered circuit computing
+// (a * b + c * d, (a + b) * (c + d))
+func createCircuit() Circuit {
+	return Circuit{
+		Layers: []Layer{
+			{{Type: Add, Left: 0, Right: 1}, {Type: Mul, Left: 2, Right: 3}},
+			{{Type: Mul, Left: 0, Right: 1}, {Type: Mul, Left: 2, Right: 3}, {Type: Add, Left: 0, Right: 1}, {Type: Add, Left: 2, Right: 3}},
+		},
+		NbInputs: 4,
+	}
+}
+
+func TestCircuitEvaluate(t *testing.T) {
+	c := createCircuit()
+	values := c.Evaluate(elements(2, 3, 4, 5))
+	require.Len(t, values, 3)
+	require.Equal(t, elements(26, 45), values[0])
+	require.Equal(t, elements(6, 20, 5, 9), values[1])
+
+	require.Panics(t, func() { c.Evaluate(elements(2, 3)) })
+	c.Layers[1][0].Right = 4
+	require.Panics(t, func() { c.Evaluate(elements(2, 3, 4, 5)) })
+}
+
+func TestBuilder(t *testing.T) {
+	// x^3 + x + 5 with the constant 5 as an input
+	b := NewBuilder()
+	x := b.Input()
+	five := b.Input()
+	x2 := b.Mul(x, x)
+	x3 := b.Mul(x2, x)
+	out := b.Add(b.Add(x3, x), five)
+	c := b.Build(out, x2)
+	// x3 at depth 2, then two additions
+	require.Len(t, c.Layers, 4)
+	require.Equal(t, 3, c.NbInputs)
+	values := c.Evaluate(b.Inputs(elements(3, 5)...))
+	require.Equal(t, elements(35, 9), values[0])
+
+	// all the gates read the previous layer: x is relayed as x * 1
+	require.Equal(t, Gate{Type: Mul, Left: 1, Right: 0}, c.Layers[3][1])
+}
@@ -0,0 +1,237 @@
+// Package gkr implements the GKR protocol of Goldwasser, Kalai and Rothblum
+// for layered arithmetic circuits, made non interactive with the Fiat-Shamir
+// transform. The prover shows that the outputs of the circuit are correct for
+// public inputs, and the verifier works in time roughly linear in the size of
+// the circuit description without evaluating the circuit.
+//
+// Let V_i be the multilinear extension of the values of the i-th layer, the
+// values of the layer i are defined from the layer i+1 by
+//
+//	V_i(z) = SUM_(x,y) add_i(z,x,y) * (V_(i+1)(x) + V_(i+1)(y))
+//					 + mul_i(z,x,y) * V_(i+1)(x) * V_(i+1)(y)
+//
+// where add_i(z,x,y) (resp. mul_i) is the extension of the function equal to
+// one if the gate z adds (resp. multiplies) the values x and y. Starting from
+// a random evaluation of V_0 computed from the outputs, each layer reduces a
+// claim on V_i to two claims on V_(i+1), at the points x and y chosen by the
+// sumcheck. The two claims are combined with random coefficients into the next
+// sumcheck, until the verifier checks the claims on the inputs itself.
+package gkr
+
+import (
+	"github.com/nikkolasg/playsnark/sumcheck"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// LayerProof is the sumcheck of a layer and the claimed evaluations of the
+// next layer at the two points x and y chosen by the sumcheck
+type LayerProof struct {
+	Sumcheck sumcheck.Proof
+	VX       Element
+	VY       Element
+}
+
+// Proof contains one sumcheck per layer
+type Proof struct {
+	Layers []LayerProof
+}
+
+// claim is the combination alpha * V(z1) + beta * V(z2) of two evaluations of
+// the extension of a layer
+type claim struct {
+	z1    []Element
+	z2    []Element
+	alpha Element
+	beta  Element
+	value Element
+}
+
+// Prove evaluates the circuit on the inputs and returns the outputs with the
+// proof that they are correct.
+func Prove(c Circuit, inputs []Element) ([]Element, Proof) {
+	var proof Proof
+	values := c.Evaluate(inputs)
+	tr := newTranscript(c, inputs, values[0])
+	cl := outputClaim(tr, values[0])
+	for i := range c.Layers {
+		next := sumcheck.NewMLE(values[i+1])
+		add, mul := c.wiring(i, cl)
+		vx, vy := expand(next)
+		p := sumcheck.Polynomial{{add, vx}, {add, vy}, {mul, vx, vy}}
+		sc, point, _ := sumcheck.Prove(tr, p)
+		s := next.NumVars()
+		lp := LayerProof{
+			Sumcheck: sc,
+			VX:       next.Evaluate(point[:s]),
+			VY:       next.Evaluate(point[s:]),
+		}
+		proof.Layers = append(proof.Layers, lp)
+		cl = nextClaim(tr, point, s, lp)
+	}
+	return values[0], proof
+}
+
+// Verify returns true if the proof shows that the circuit outputs the given
+// values on the given inputs.
+func Verify(c Circuit, inputs, outputs []Element, proof Proof) bool {
+	if len(proof.Layers) != len(c.Layers) || len(inputs) != c.NbInputs || len(outputs) != c.size(0) {
+		return false
+	}
+	tr := newTranscript(c, inputs, outputs)
+	cl := outputClaim(tr, outputs)
+	for i, lp := range proof.Layers {
+		s := numVars(c.size(i + 1))
+		point, final, err := sumcheck.Verify(tr, cl.value, 2*s, 3, lp.Sumcheck)
+		if err != nil {
+			return false
+		}
+		// the verifier evaluates the wiring predicates by itself:
+		// final = add(x,y) * (V(x) + V(y)) + mul(x,y) * V(x) * V(y)
+		add, mul := c.evalWiring(i, cl, point[:s], point[s:])
+		expected := sumcheck.NewElement().Add(lp.VX, lp.VY)
+		expected = expected.Mul(expected, add)
+		m := sumcheck.NewElement().Mul(lp.VX, lp.VY)
+		expected = expected.Add(expected, m.Mul(m, mul))
+		if !final.Equal(expected) {
+			return false
+		}
+		cl = nextClaim(tr, point, s, lp)
+	}
+	// the last claims are on the inputs
+	in := sumcheck.NewMLE(inputs)
+	return in.Evaluate(cl.z1).Equal(proof.Layers[len(proof.Layers)-1].VX) &&
+		in.Evaluate(cl.z2).Equal(proof.Layers[len(proof.Layers)-1].VY)
+}
+
+func newTranscript(c Circuit, inputs, outputs []Element) *transcript.Transcript {
+	tr := transcript.New("gkr")
+	tr.AppendUint64("layers", uint64(len(c.Layers)))
+	for _, l := range c.Layers {
+		tr.AppendUint64("gates", uint64(len(l)))
+		for _, g := range l {
+			tr.AppendUint64("type", uint64(g.Type))
+			tr.AppendUint64("left", uint64(g.Left))
+			tr.AppendUint64("right", uint64(g.Right))
+		}
+	}
+	for _, in := range inputs {
+		tr.AppendScalar("input", in)
+	}
+	for _, out := range outputs {
+		tr.AppendScalar("output", out)
+	}
+	return tr
+}
+
+// outputClaim returns the claim V_0(r) for a random r
+func outputClaim(tr *transcript.Transcript, outputs []Element) claim {
+	out := sumcheck.NewMLE(outputs)
+	var r []Element
+	for i := 0; i < out.NumVars(); i++ {
+		r = append(r, tr.ChallengeScalar("r", sumcheck.Group))
+	}
+	return claim{
+		z1:    r,
+		z2:    r,
+		alpha: sumcheck.NewElement().One(),
+		beta:  sumcheck.NewElement(),
+		value: out.Evaluate(r),
+	}
+}
+
+// nextClaim combines the two evaluations of the next layer with random
+// coefficients
+func nextClaim(tr *transcript.Transcript, point []Element, s int, lp LayerProof) claim {
+	tr.AppendScalar("vx", lp.VX)
+	tr.AppendScalar("vy", lp.VY)
+	cl := claim{
+		z1:    point[:s],
+		z2:    point[s:],
+		alpha: tr.ChallengeScalar("alpha", sumcheck.Group),
+		beta:  tr.ChallengeScalar("beta", sumcheck.Group),
+	}
+	cl.value = sumcheck.NewElement().Mul(cl.alpha, lp.VX)
+	cl.value = cl.value.Add(cl.value, sumcheck.NewElement().Mul(cl.beta, lp.VY))
+	return cl
+}
+
+// gateWeights returns alpha * eq(z1, g) + beta * eq(z2, g) for all the gates
+// g of the layer
+func (cl claim) gateWeights() sumcheck.MLE {
+	eq1 := sumcheck.EqMLE(cl.z1)
+	eq2 := sumcheck.EqMLE(cl.z2)
+	out := make(sumcheck.MLE, len(eq1))
+	for g := range out {
+		out[g] = sumcheck.NewElement().Mul(cl.alpha, eq1[g])
+		out[g] = out[g].Add(out[g], sumcheck.NewElement().Mul(cl.beta, eq2[g]))
+	}
+	return out
+}
+
+// wiring returns the extensions of (x,y) -> SUM_z w(z) * add_i(z,x,y) and the
+// same for mul_i, with w the gate weights of the claim. The index of (x,y) is
+// x + y * 2^s where s is the number of variables of the next layer.
+func (c Circuit) wiring(i int, cl claim) (sumcheck.MLE, sumcheck.MLE) {
+	n := 1 << uint(numVars(c.size(i+1)))
+	add := zeros(n * n)
+	mul := zeros(n * n)
+	w := cl.gateWeights()
+	for z, g := range c.Layers[i] {
+		m := add
+		if g.Type == Mul {
+			m = mul
+		}
+		idx := g.Left + g.Right*n
+		m[idx] = m[idx].Add(m[idx], w[z])
+	}
+	return add, mul
+}
+
+// evalWiring returns the evaluations of the extensions of wiring at (x,y)
+func (c Circuit) evalWiring(i int, cl claim, x, y []Element) (Element, Element) {
+	w := cl.gateWeights()
+	eqx := sumcheck.EqMLE(x)
+	eqy := sumcheck.EqMLE(y)
+	add, mul := sumcheck.NewElement(), sumcheck.NewElement()
+	for z, g := range c.Layers[i] {
+		t := sumcheck.NewElement().Mul(w[z], eqx[g.Left])
+		t = t.Mul(t, eqy[g.Right])
+		if g.Type == Mul {
+			mul = mul.Add(mul, t)
+		} else {
+			add = add.Add(add, t)
+		}
+	}
+	return add, mul
+}
+
+// expand returns the extensions of (x,y) -> V(x) and (x,y) -> V(y)
+func expand(v sumcheck.MLE) (sumcheck.MLE, sumcheck.MLE) {
+	n := len(v)
+	vx := make(sumcheck.MLE, n*n)
+	vy := make(sumcheck.MLE, n*n)
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			vx[x+y*n] = v[x].Clone()
+			vy[x+y*n] = v[y].Clone()
+		}
+	}
+	return vx, vy
+}
+
+func zeros(n int) sumcheck.MLE {
+	out := make(sumcheck.MLE, n)
+	for i := range out {
+		out[i] = sumcheck.NewElement()
+	}
+	return out
+}
+
+// numVars returns the number of variables of the extension of n values
+func numVars(n int) int {
+	v := 0
+	for 1<<uint(v) < n {
+		v++
+	}
+	return v
+}
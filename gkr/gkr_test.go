@@ -0,0 +1,62 @@
+package gkr
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark/sumcheck"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGKR(t *testing.T) {
+	c := createCircuit()
+	inputs := elements(2, 3, 4, 5)
+	outputs, proof := Prove(c, inputs)
+	require.Equal(t, elements(26, 45), outputs)
+	require.True(t, Verify(c, inputs, outputs, proof))
+
+	// wrong outputs
+	require.False(t, Verify(c, inputs, elements(26, 46), proof))
+	// wrong inputs
+	require.False(t, Verify(c, elements(2, 3, 4, 6), outputs, proof))
+	// other circuit
+	c2 := createCircuit()
+	c2.Layers[0][1].Type = Add
+	require.False(t, Verify(c2, inputs, outputs, proof))
+	// tampered claim on a layer
+	tampered := Proof{Layers: append([]LayerProof{}, proof.Layers...)}
+	tampered.Layers[0].VX = sumcheck.NewElement().Add(proof.Layers[0].VX, sumcheck.NewElement().One())
+	require.False(t, Verify(c, inputs, outputs, tampered))
+}
+
+func TestGKRWrongEvaluation(t *testing.T) {
+	// a prover computing a wrong gate value can't convince the verifier of
+	// the resulting outputs
+	c := createCircuit()
+	inputs := elements(2, 3, 4, 5)
+	cheat := createCircuit()
+	cheat.Layers[1][0].Type = Add
+	outputs, proof := Prove(cheat, inputs)
+	require.Equal(t, elements(25, 45), outputs)
+	require.False(t, Verify(c, inputs, outputs, proof))
+}
+
+func TestGKRBuilder(t *testing.T) {
+	// the inner product of two vectors of 4 elements and the product of the
+	// first vector
+	b := NewBuilder()
+	var xs, ys []int
+	for i := 0; i < 4; i++ {
+		xs = append(xs, b.Input())
+	}
+	for i := 0; i < 4; i++ {
+		ys = append(ys, b.Input())
+	}
+	ip := b.Add(b.Add(b.Mul(xs[0], ys[0]), b.Mul(xs[1], ys[1])), b.Add(b.Mul(xs[2], ys[2]), b.Mul(xs[3], ys[3])))
+	prod := b.Mul(b.Mul(xs[0], xs[1]), b.Mul(xs[2], xs[3]))
+	c := b.Build(ip, prod, xs[0])
+	inputs := b.Inputs(elements(1, 2, 3, 4, 5, 6, 7, 8)...)
+	outputs, proof := Prove(c, inputs)
+	require.Equal(t, elements(70, 24, 1), outputs)
+	require.True(t, Verify(c, inputs, outputs, proof))
+	require.False(t, Verify(c, inputs, elements(70, 24, 2), proof))
+}
@@ -3,11 +3,10 @@ module github.com/nikkolasg/playsnark
 go 1.15
 
 require (
+	github.com/consensys/gnark-crypto v0.4.0
 	github.com/drand/kyber v1.1.3
 	github.com/drand/kyber-bls12381 v0.2.1-0.20200920171356-02a6d1c7cc77
 	github.com/kilic/bls12-381 v0.0.0-20200820230200-6b2c19996391 // indirect
 	github.com/stretchr/testify v1.4.0
-	go.dedis.ch/kyber/v3 v3.0.9
-	golang.org/x/crypto v0.0.0-20200820211705-5c72a883971a // indirect
-	golang.org/x/sys v0.0.0-20200918174421-af09f7315aff // indirect
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
 )
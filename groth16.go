@@ -28,7 +28,8 @@ type groth16ToxicWaste struct {
 // Groth16Setup contains all the information created during a trusted setup
 // required by the prover and the verifier.
 type Groth16Setup struct {
-	tw groth16ToxicWaste
+	tw    groth16ToxicWaste
+	curve Curve
 	// Alpha and beta are required to make sure the computation of the proof
 	// elements A B and C are consistent with each other w.r.t. the intermediate
 	// variables used, i.e. they used the same a_i inside their computation.
@@ -62,28 +63,29 @@ type Groth16Setup struct {
 
 // NewGroth16TrustedSetup returns a setup for the given circuit
 func NewGroth16TrustedSetup(qap QAP) Groth16Setup {
+	c := qap.curve
 	var tw groth16ToxicWaste
 	var tr Groth16Setup
-	tw.Alpha = NewElement().Pick(random.New())
-	tr.Alpha = NewG1().Mul(tw.Alpha, nil)
+	tw.Alpha = c.NewElement().Pick(random.New())
+	tr.Alpha = c.NewG1().Mul(tw.Alpha, nil)
 
-	tw.Beta = NewElement().Pick(random.New())
-	tr.Beta = NewG1().Mul(tw.Beta, nil)
-	tr.Beta2 = NewG2().Mul(tw.Beta, nil)
+	tw.Beta = c.NewElement().Pick(random.New())
+	tr.Beta = c.NewG1().Mul(tw.Beta, nil)
+	tr.Beta2 = c.NewG2().Mul(tw.Beta, nil)
 
-	tw.Delta = NewElement().Pick(random.New())
-	tr.Delta = NewG1().Mul(tw.Delta, nil)
-	tr.Delta2 = NewG2().Mul(tw.Delta, nil)
+	tw.Delta = c.NewElement().Pick(random.New())
+	tr.Delta = c.NewG1().Mul(tw.Delta, nil)
+	tr.Delta2 = c.NewG2().Mul(tw.Delta, nil)
 
-	tw.X = NewElement().Pick(random.New())
-	tr.Xi = GeneratePowersCommit(zeroG1, tw.X, one.Clone(), qap.nbGates-1)
-	tr.Xi2 = GeneratePowersCommit(zeroG2, tw.X, one.Clone(), qap.nbGates-1)
+	tw.X = c.NewElement().Pick(random.New())
+	tr.Xi = GeneratePowersCommit(c.NewG1().Null(), tw.X, c.NewElement().One(), qap.nbGates-1)
+	tr.Xi2 = GeneratePowersCommit(c.NewG2().Null(), tw.X, c.NewElement().One(), qap.nbGates-1)
 
-	tw.Gamma = NewElement().Pick(random.New())
-	tr.Gamma = NewG2().Mul(tw.Gamma, nil)
+	tw.Gamma = c.NewElement().Pick(random.New())
+	tr.Gamma = c.NewG2().Mul(tw.Gamma, nil)
 	// diff marks the separation between IO poly variables and intermediates
 	// ones
-	diff := qap.nbVars - qap.nbIO
+	diff := qap.nbIO
 	// (beta*u_i(x) + alpha*v_i(x) + w_i(x)) / gamma for io related variable
 	// poly
 	tw.IoLP, tr.IoLP = fullLinearPoly(qap, 0, diff, tw.X, tw.Alpha, tw.Beta, tw.Gamma)
@@ -92,14 +94,39 @@ func NewGroth16TrustedSetup(qap QAP) Groth16Setup {
 
 	// XiT are { x^i * t(x) / delta } for i:0 -> nbGates-2 where t(x) is the
 	tx := qap.z.Eval(tw.X)
-	txd := NewElement().Div(tx, tw.Delta)
+	txd := c.NewElement().Div(tx, tw.Delta)
 	power := qap.nbGates - 2
-	tr.XiT = GeneratePowersCommit(zeroG1, tw.X, txd, power)
+	tr.XiT = GeneratePowersCommit(c.NewG1().Null(), tw.X, txd, power)
 
 	tr.tw = tw
+	tr.curve = c
 	return tr
 }
 
+// Groth16VerifyingKey contains the part of the trusted setup required by the
+// verifier only
+type Groth16VerifyingKey struct {
+	Curve  Curve
+	Alpha  G1
+	Beta2  G2
+	Gamma  G2
+	Delta2 G2
+	// commitments of the linear polys of the public values, "const" first
+	IoLP []G1
+}
+
+// VerifyingKey returns the verifying key of the setup
+func (tr Groth16Setup) VerifyingKey() Groth16VerifyingKey {
+	return Groth16VerifyingKey{
+		Curve:  tr.curve,
+		Alpha:  tr.Alpha,
+		Beta2:  tr.Beta2,
+		Gamma:  tr.Gamma,
+		Delta2: tr.Delta2,
+		IoLP:   tr.IoLP,
+	}
+}
+
 // Groth16Proof contains the three elements required by the verifier as well as
 // the private values used by the prover (which must not be given to verifier as
 // this would break zero knowledge)
@@ -120,6 +147,13 @@ type groth16ToxicProof struct {
 // Groth16Prove proofs it knows a solution sol for the given circuit and returns
 // the proof
 func Groth16Prove(tr Groth16Setup, q QAP, sol Vector) Groth16Proof {
+	return Groth16ProveElements(tr, q, q.curve.Elements(sol))
+}
+
+// Groth16ProveElements is Groth16Prove for a solution whose values are
+// elements of the scalar field of the QAP
+func Groth16ProveElements(tr Groth16Setup, q QAP, sol []Element) Groth16Proof {
+	c := q.curve
 	// The proof code is structured in three pieces, for generating the three
 	// elements of the proofs A B and C.
 	//
@@ -135,7 +169,7 @@ func Groth16Prove(tr Groth16Setup, q QAP, sol Vector) Groth16Proof {
 		var sum = basis.Clone().Null()
 		for i := 0; i < q.nbVars; i++ {
 			uix := polys[i].BlindEval(basis.Clone().Null(), xi)
-			sum = sum.Add(sum, uix.Mul(sol[i].ToFieldElement(), uix))
+			sum = sum.Add(sum, uix.Mul(sol[i], uix))
 		}
 		return sum
 	}
@@ -143,10 +177,10 @@ func Groth16Prove(tr Groth16Setup, q QAP, sol Vector) Groth16Proof {
 	// Compute A = G1^(alpha + SUM(a_i * u_i(x)) + r*delta)
 	// we compute each part directly in the exponent thx to the trusted setup
 	//
-	var A = sumBlind(zeroG1, q.left, tr.Xi)
+	var A = sumBlind(c.NewG1().Null(), q.left, tr.Xi)
 	//  Pick r and then compute g^(r * delta)
-	r := NewElement().Pick(random.New())
-	rd := NewG1().Mul(r, tr.Delta)
+	r := c.NewElement().Pick(random.New())
+	rd := c.NewG1().Mul(r, tr.Delta)
 	// A = G1^(alpha + SUM(a_i * u_i(x)) + r*delta)
 	A = A.Add(A, rd)
 	A = A.Add(tr.Alpha, A)
@@ -154,9 +188,9 @@ func Groth16Prove(tr Groth16Setup, q QAP, sol Vector) Groth16Proof {
 	// ----------------------------------------------
 	// We do something similar for B expcet in it's G2
 	// B = G2^(beta + SUM(a_i * v_i(x)) + s*delta
-	var B = sumBlind(zeroG2, q.right, tr.Xi2)
-	s := NewElement().Pick(random.New())
-	sd := NewG2().Mul(s, tr.Delta2)
+	var B = sumBlind(c.NewG2().Null(), q.right, tr.Xi2)
+	s := c.NewElement().Pick(random.New())
+	sd := c.NewG2().Mul(s, tr.Delta2)
 	B = B.Add(B, sd)
 	B = B.Add(tr.Beta2, B)
 
@@ -167,36 +201,36 @@ func Groth16Prove(tr Groth16Setup, q QAP, sol Vector) Groth16Proof {
 	// where NioLP = beta*u_i(x) + alpha * v_i(x) + w_i(x) where we only sum
 	// over the intermediates / non IO variables.
 	//
-	C := NewG1().Null()
+	C := c.NewG1().Null()
 	// for the part with NioLP we use the NioLP part of the trusted setup and
 	// multiply every entry by the piecewise solution element
-	nio := NewG1().Null()
+	nio := c.NewG1().Null()
 	// we only take variables which are _not_ io
-	diff := q.nbVars - q.nbIO
+	diff := q.nbIO
 	for i := range tr.NioLP {
-		nio = nio.Add(nio, NewG1().Mul(sol[i+diff].ToFieldElement(), tr.NioLP[i]))
+		nio = nio.Add(nio, c.NewG1().Mul(sol[i+diff], tr.NioLP[i]))
 	}
 	C = C.Add(C, nio)
 	// we can compute h(x)t(x)/delta from the XiT part of the trusted setup
 	// We can construct h(x) thanks to x^i and since we want to multiply by t(x)
 	// and divide by delta, then we directly use x^i * t(x) / delta which is XiT
 	// we first compute polynomial h so we get the coefficients
-	h := q.Quotient(sol)
-	htd := h.BlindEval(zeroG1, tr.XiT)
+	h := q.quotient(sol)
+	htd := h.BlindEval(c.NewG1().Null(), tr.XiT)
 	C = C.Add(C, htd)
 
 	//  As is simple multiplication
-	As := NewG1().Mul(s, A)
+	As := c.NewG1().Mul(s, A)
 	C = C.Add(C, As)
 	// Br forces us to recompute B in G1 group though
-	B1 := sumBlind(zeroG1, q.right, tr.Xi)
-	sd1 := NewG1().Mul(s, tr.Delta)
+	B1 := sumBlind(c.NewG1().Null(), q.right, tr.Xi)
+	sd1 := c.NewG1().Mul(s, tr.Delta)
 	B1 = B1.Add(B1, sd1)
 	B1 = B1.Add(B1, tr.Beta)
-	Br := NewG1().Mul(r, B1)
+	Br := c.NewG1().Mul(r, B1)
 	C = C.Add(C, Br)
 	//  - r*s*delta
-	rsd := NewG1().Mul(NewElement().Mul(r, s), tr.Delta)
+	rsd := c.NewG1().Mul(c.NewElement().Mul(r, s), tr.Delta)
 	C = C.Add(C, rsd.Neg(rsd))
 
 	return Groth16Proof{
@@ -212,23 +246,39 @@ func Groth16Prove(tr Groth16Setup, q QAP, sol Vector) Groth16Proof {
 
 // Groth16Verify returns true if the proof is valid
 func Groth16Verify(tr Groth16Setup, q QAP, p Groth16Proof, io Vector) bool {
+	return tr.VerifyingKey().Verify(p, io)
+}
+
+// Verify returns true if the proof is valid for the public values, "const"
+// followed by the inputs and outputs
+func (vk Groth16VerifyingKey) Verify(p Groth16Proof, io Vector) bool {
+	return vk.VerifyElements(p, vk.Curve.Elements(io))
+}
+
+// VerifyElements is Verify for public values which are elements of the scalar
+// field
+func (vk Groth16VerifyingKey) VerifyElements(p Groth16Proof, io []Element) bool {
+	c := vk.Curve
+	if len(io) != len(vk.IoLP) {
+		return false
+	}
 	// Proof verification consists in 4 pairings (without optimizations) and one
 	// equation check:
 	// left side :  e(A * B)
-	left := Pair(p.A, p.B)
+	left := c.Pair(p.A, p.B)
 	// right side: a * b * c
 	//  	a. e(alpha, beta)
 	//		b. e(SUM IoLP, gamma)
-	//		c. e(C1,  delta)
+	//		cd. e(C1,  delta)
 
-	a := Pair(tr.Alpha, tr.Beta2)
-	b1 := NewG1().Null()
-	for i, iolp := range tr.IoLP {
-		b1 = b1.Add(b1, NewG1().Mul(io[i].ToFieldElement(), iolp))
+	a := c.Pair(vk.Alpha, vk.Beta2)
+	b1 := c.NewG1().Null()
+	for i, iolp := range vk.IoLP {
+		b1 = b1.Add(b1, c.NewG1().Mul(io[i], iolp))
 	}
-	b := Pair(b1, tr.Gamma)
-	c := Pair(p.C, tr.Delta2)
-	right := a.Add(a, b.Add(b, c))
+	b := c.Pair(b1, vk.Gamma)
+	cd := c.Pair(p.C, vk.Delta2)
+	right := a.Add(a, b.Add(b, cd))
 	return left.Equal(right)
 }
 
@@ -236,29 +286,31 @@ func Groth16Verify(tr Groth16Setup, q QAP, p Groth16Proof, io Vector) bool {
 // I call this relation "linearPoly". fullLinearPoly iterates over multiples
 // variables and returns the list and its commitment
 func linearPolyForVar(qap QAP, i int, x, alpha, beta Element) Element {
+	c := qap.curve
 	// u_i(x)
 	ui := qap.left[i].Eval(x)
 	// beta * u_i(x)
-	bui := NewElement().Mul(ui, beta)
+	bui := c.NewElement().Mul(ui, beta)
 	// v_i(x)
 	vi := qap.right[i].Eval(x)
 	// alpha * v_i(x)
-	avi := NewElement().Mul(vi, alpha)
+	avi := c.NewElement().Mul(vi, alpha)
 	wi := qap.out[i].Eval(x)
-	return wi.Add(wi, NewElement().Add(bui, avi))
+	return wi.Add(wi, c.NewElement().Add(bui, avi))
 }
 
 // call linearPolyForVar for variable between min and max, and use the "div" as
 // divider. Div should be gamma for the IO related variables and delta for the
 // non IO related variables
 func fullLinearPoly(qap QAP, min, max int, x, alpha, beta, div Element) ([]Element, []G1) {
+	c := qap.curve
 	var length = max - min
 	var lps = make([]Element, 0, length)
 	var commitLps = make([]G1, 0, length)
 	for i := min; i < max; i++ {
-		var lp = NewElement().Div(linearPolyForVar(qap, i, x, alpha, beta), div)
+		var lp = c.NewElement().Div(linearPolyForVar(qap, i, x, alpha, beta), div)
 		lps = append(lps, lp)
-		commitLps = append(commitLps, NewG1().Mul(lp, nil))
+		commitLps = append(commitLps, c.NewG1().Mul(lp, nil))
 	}
 	return lps, commitLps
 }
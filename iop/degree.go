@@ -0,0 +1,47 @@
+package iop
+
+import (
+	"github.com/nikkolasg/playsnark"
+)
+
+// A KZG commitment alone doesn't say anything about the degree of the
+// committed polynomial, besides being lower than the degree D of the setup.
+// To show that deg(p) <= d, the prover also commits to x^(D-d) * p(x): this
+// is only possible if the degree of the shifted polynomial is at most D. The
+// verifier checks the two commitments are consistent with one pairing
+// equation
+//
+//	e(C, h^(s^(D-d))) == e(C_shift, h)
+
+// DegreeBound is the proof that a committed polynomial has a degree lower or
+// equal to Bound.
+type DegreeBound struct {
+	Bound int
+	// Shifted is the commitment to x^(D-Bound) * p(x)
+	Shifted G1
+}
+
+// ProveDegreeBound returns the proof that deg(p) <= bound. It panics if the
+// degree of p is higher since the shifted polynomial can't be committed.
+func ProveDegreeBound(srs KZGSetup, p Poly, bound int) DegreeBound {
+	if bound > srs.Degree() || bound < 0 {
+		panic("degree bound out of the setup")
+	}
+	return DegreeBound{
+		Bound:   bound,
+		Shifted: playsnark.KZGCommit(srs, shift(srs.Curve, p.Normalize(), srs.Degree()-bound)),
+	}
+}
+
+// VerifyDegreeBound returns true if the polynomial committed in c has a degree
+// lower or equal to the bound of the proof. The setup must contain the power
+// s^(D-bound) on G2, which is the case for the setups returned by NewSetup.
+func VerifyDegreeBound(srs KZGSetup, c G1, proof DegreeBound) bool {
+	k := srs.Degree() - proof.Bound
+	if proof.Bound < 0 || k < 0 || k >= len(srs.G2Powers) {
+		return false
+	}
+	left := srs.Curve.Pair(c, srs.G2Powers[k])
+	right := srs.Curve.Pair(proof.Shifted, srs.G2Powers[0])
+	return left.Equal(right)
+}
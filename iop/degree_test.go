@@ -0,0 +1,38 @@
+package iop
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDegreeBound(t *testing.T) {
+	for _, c := range playsnark.Curves {
+		t.Run(c.Name, func(t *testing.T) { testDegreeBound(t, c) })
+	}
+}
+
+func testDegreeBound(t *testing.T, curve Curve) {
+	srs := NewSetupWith(curve, 16)
+	p := randomPoly(curve, 5)
+	c := playsnark.KZGCommit(srs, p)
+	for _, bound := range []int{5, 6, 16} {
+		proof := ProveDegreeBound(srs, p, bound)
+		require.True(t, VerifyDegreeBound(srs, c, proof), "bound %d", bound)
+	}
+	// the degree is too high to shift the polynomial
+	require.Panics(t, func() { ProveDegreeBound(srs, p, 4) })
+
+	// a prover claiming a lower bound with another shift is caught
+	proof := ProveDegreeBound(srs, p, 6)
+	proof.Bound = 4
+	require.False(t, VerifyDegreeBound(srs, c, proof))
+	// the shifted commitment must be for the same polynomial
+	proof = ProveDegreeBound(srs, randomPoly(curve, 5), 5)
+	require.False(t, VerifyDegreeBound(srs, c, proof))
+	// the setup doesn't have the powers on G2
+	small := playsnark.NewKZGSetupWith(curve, 16, 1)
+	proof = ProveDegreeBound(small, p, 5)
+	require.False(t, VerifyDegreeBound(small, playsnark.KZGCommit(small, p), proof))
+}
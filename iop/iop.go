@@ -0,0 +1,82 @@
+// Package iop contains building blocks of the polynomial interactive oracle
+// proofs used by the proof systems with a universal setup, such as Sonic,
+// Marlin or PLONK, compiled into arguments with the KZG commitments:
+//   - the univariate sumcheck shows that a committed polynomial sums to a
+//     given value over a multiplicative subgroup H.
+//   - the degree bound check shows that a committed polynomial has a degree
+//     lower or equal to a bound, with a commitment shifted to the maximum
+//     degree of the setup.
+//   - the rational checks show that a committed polynomial is equal to the
+//     ratio of two committed polynomials on H, or that this ratio sums to a
+//     given value over H.
+//
+// Each argument absorbs its commitments in a transcript, such that it can be
+// used on its own or as one step of a bigger protocol. The arguments run on
+// the curve of their KZG setup, with a domain of the same curve.
+package iop
+
+import (
+	"github.com/nikkolasg/playsnark"
+)
+
+type Curve = playsnark.Curve
+type Element = playsnark.Element
+type Poly = playsnark.Poly
+type G1 = playsnark.G1
+type Domain = playsnark.Domain
+type KZGSetup = playsnark.KZGSetup
+
+// NewSetup returns a KZG setup for polynomials of degree up to degree with
+// all the powers on G2 required by the degree bound checks, on the default
+// curve.
+func NewSetup(degree int) KZGSetup {
+	return NewSetupWith(playsnark.BLS12381, degree)
+}
+
+// NewSetupWith returns the setup of NewSetup on the given curve.
+func NewSetupWith(c Curve, degree int) KZGSetup {
+	return playsnark.NewKZGSetupWith(c, degree, degree)
+}
+
+func zero(c Curve) Element {
+	return c.NewElement()
+}
+
+func one(c Curve) Element {
+	return c.Element(1)
+}
+
+// constant returns the constant polynomial c
+func constant(c Element) Poly {
+	return Poly([]Element{c})
+}
+
+// shift returns x^k * p(x)
+func shift(c Curve, p Poly, k int) Poly {
+	out := make(Poly, k, k+len(p))
+	for i := range out {
+		out[i] = zero(c)
+	}
+	for _, c := range p {
+		out = append(out, c.Clone())
+	}
+	return out
+}
+
+// coeffs returns the polynomial made of the coefficients of p between from
+// and to
+func coeffs(c Curve, p Poly, from, to int) Poly {
+	out := make(Poly, to-from)
+	for i := range out {
+		out[i] = zero(c)
+		if from+i < len(p) {
+			out[i] = p[from+i].Clone()
+		}
+	}
+	return out
+}
+
+// divByN returns c / n
+func divByN(c Element, n int) Element {
+	return c.Clone().Div(c, c.Clone().SetInt64(int64(n)))
+}
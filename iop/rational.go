@@ -0,0 +1,174 @@
+package iop
+
+import (
+	"errors"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// A rational function f = a / b can't be committed directly, but the prover
+// can show that a committed f agrees with a / b on H, i.e. that
+// a(x) - b(x) * f(x) vanishes on H, with a quotient h:
+//
+//	a(x) - b(x) * f(x) = h(x) * Z_H(x)
+//
+// Combined with the sumcheck, the prover shows that a / b sums to sigma over
+// H without committing to f at all, as in the holographic sumcheck of Marlin:
+// f is replaced by x * g(x) + sigma / n in the equation above.
+
+// RationalProof is the proof that a committed polynomial f is equal to a / b
+// on the domain, for committed polynomials a and b.
+type RationalProof struct {
+	// Commitment to the quotient h
+	H G1
+	// Evaluations of a, b, f and h at the challenge zeta
+	AEval Element
+	BEval Element
+	FEval Element
+	HEval Element
+	// W is the KZG opening proof at zeta
+	W G1
+}
+
+// ProveRational returns the proof that f(k) = a(k) / b(k) for all k in the
+// domain, given the commitments to the three polynomials.
+func ProveRational(srs KZGSetup, tr *transcript.Transcript, d Domain, a, b, f Poly, ca, cb, cf G1) RationalProof {
+	var proof RationalProof
+	h, _ := d.DivideByVanishing(a.Sub(b.Mul(f)))
+	proof.H = playsnark.KZGCommit(srs, h)
+	zeta := rationalChallenge(srs.Curve, tr, []G1{ca, cb, cf, proof.H})
+	multi := playsnark.KZGOpenMulti(srs, tr, []Poly{a, b, f, h}, []G1{ca, cb, cf, proof.H}, zeta)
+	proof.AEval, proof.BEval, proof.FEval, proof.HEval = multi.Ys[0], multi.Ys[1], multi.Ys[2], multi.Ys[3]
+	proof.W = multi.W
+	return proof
+}
+
+// VerifyRational returns true if the proof shows that the polynomial committed
+// in cf is equal to the ratio of the polynomials committed in ca and cb on
+// the domain.
+func VerifyRational(srs KZGSetup, tr *transcript.Transcript, d Domain, ca, cb, cf G1, proof RationalProof) bool {
+	zeta := rationalChallenge(srs.Curve, tr, []G1{ca, cb, cf, proof.H})
+	left := d.Curve.NewElement().Mul(proof.BEval, proof.FEval)
+	left = left.Sub(proof.AEval, left)
+	if !left.Equal(d.Curve.NewElement().Mul(proof.HEval, d.EvalVanishing(zeta))) {
+		return false
+	}
+	multi := playsnark.KZGMultiProof{
+		Z:  zeta,
+		Ys: []Element{proof.AEval, proof.BEval, proof.FEval, proof.HEval},
+		W:  proof.W,
+	}
+	return playsnark.KZGVerifyMulti(srs, tr, []G1{ca, cb, cf, proof.H}, multi)
+}
+
+// RationalSumcheckProof is the proof that a / b sums to a given value over the
+// domain, for committed polynomials a and b.
+type RationalSumcheckProof struct {
+	// Commitments to g, with its degree bound, and to h
+	G      G1
+	GBound DegreeBound
+	H      G1
+	// Evaluations of a, b, g and h at the challenge zeta
+	AEval Element
+	BEval Element
+	GEval Element
+	HEval Element
+	// W is the KZG opening proof at zeta
+	W G1
+}
+
+// SumRational returns SUM a(k) / b(k) for all k in the domain and an error if
+// b vanishes on the domain.
+func SumRational(d Domain, a, b Poly) (Element, error) {
+	f, err := rationalEvals(d, a, b)
+	if err != nil {
+		return nil, err
+	}
+	acc := zero(d.Curve)
+	for _, e := range f {
+		acc = acc.Add(acc, e)
+	}
+	return acc, nil
+}
+
+// ProveRationalSumcheck returns the proof that a / b sums to SumRational(d, a,
+// b) over the domain, and an error if b vanishes on the domain.
+func ProveRationalSumcheck(srs KZGSetup, tr *transcript.Transcript, d Domain, a, b Poly, ca, cb G1) (RationalSumcheckProof, error) {
+	var proof RationalSumcheckProof
+	evals, err := rationalEvals(d, a, b)
+	if err != nil {
+		return proof, err
+	}
+	// f interpolates a / b on H and its constant term is sigma / n
+	f := d.Interpolate(evals)
+	sigma := d.Curve.NewElement().Mul(f[0], d.Curve.Element(playsnark.Value(d.Size)))
+	g := coeffs(d.Curve, f, 1, d.Size)
+	h, _ := d.DivideByVanishing(a.Sub(b.Mul(f)))
+	if len(h) == 0 {
+		h = constant(zero(d.Curve))
+	}
+	proof.G = playsnark.KZGCommit(srs, g)
+	proof.GBound = ProveDegreeBound(srs, g, d.Size-2)
+	proof.H = playsnark.KZGCommit(srs, h)
+	zeta := rationalSumcheckChallenge(srs.Curve, tr, ca, cb, sigma, &proof)
+	cs := []G1{ca, cb, proof.G, proof.H}
+	multi := playsnark.KZGOpenMulti(srs, tr, []Poly{a, b, g, h}, cs, zeta)
+	proof.AEval, proof.BEval, proof.GEval, proof.HEval = multi.Ys[0], multi.Ys[1], multi.Ys[2], multi.Ys[3]
+	proof.W = multi.W
+	return proof, nil
+}
+
+// VerifyRationalSumcheck returns true if the proof shows that the ratio of
+// the polynomials committed in ca and cb sums to sigma over the domain.
+func VerifyRationalSumcheck(srs KZGSetup, tr *transcript.Transcript, d Domain, ca, cb G1, sigma Element, proof RationalSumcheckProof) bool {
+	if proof.GBound.Bound != d.Size-2 || !VerifyDegreeBound(srs, proof.G, proof.GBound) {
+		return false
+	}
+	zeta := rationalSumcheckChallenge(srs.Curve, tr, ca, cb, sigma, &proof)
+	// a - b * (zeta * g + sigma / n) = h * Z_H(zeta)
+	f := SumcheckEval(d, zeta, zero(d.Curve), proof.GEval, sigma)
+	left := d.Curve.NewElement().Mul(proof.BEval, f)
+	left = left.Sub(proof.AEval, left)
+	if !left.Equal(d.Curve.NewElement().Mul(proof.HEval, d.EvalVanishing(zeta))) {
+		return false
+	}
+	multi := playsnark.KZGMultiProof{
+		Z:  zeta,
+		Ys: []Element{proof.AEval, proof.BEval, proof.GEval, proof.HEval},
+		W:  proof.W,
+	}
+	return playsnark.KZGVerifyMulti(srs, tr, []G1{ca, cb, proof.G, proof.H}, multi)
+}
+
+// rationalEvals returns a(k) / b(k) for all k in the domain
+func rationalEvals(d Domain, a, b Poly) ([]Element, error) {
+	out := make([]Element, d.Size)
+	for i, x := range d.Elements {
+		den := b.Eval(x)
+		if den.Equal(zero(d.Curve)) {
+			return nil, errors.New("iop: the denominator vanishes on the domain")
+		}
+		out[i] = d.Curve.NewElement().Div(a.Eval(x), den)
+	}
+	return out, nil
+}
+
+func rationalChallenge(curve Curve, tr *transcript.Transcript, cs []G1) Element {
+	tr.AppendMessage("dom-sep", []byte("iop.rational"))
+	for _, c := range cs {
+		tr.AppendPoint("c", c)
+	}
+	return tr.ChallengeScalar("zeta", curve.Suite.G1())
+}
+
+func rationalSumcheckChallenge(curve Curve, tr *transcript.Transcript, ca, cb G1, sigma Element, proof *RationalSumcheckProof) Element {
+	tr.AppendMessage("dom-sep", []byte("iop.rational_sumcheck"))
+	tr.AppendPoint("a", ca)
+	tr.AppendPoint("b", cb)
+	tr.AppendScalar("sigma", sigma)
+	tr.AppendPoint("g", proof.G)
+	tr.AppendPoint("g_shift", proof.GBound.Shifted)
+	tr.AppendPoint("h", proof.H)
+	return tr.ChallengeScalar("zeta", curve.Suite.G1())
+}
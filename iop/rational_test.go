@@ -0,0 +1,73 @@
+package iop
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRational(t *testing.T) {
+	for _, c := range playsnark.Curves {
+		t.Run(c.Name, func(t *testing.T) { testRational(t, c) })
+	}
+}
+
+func testRational(t *testing.T, curve Curve) {
+	srs := NewSetupWith(curve, 32)
+	d := playsnark.NewDomainWith(curve, 8)
+	a := randomPoly(curve, 7)
+	b := randomPoly(curve, 7)
+	evals, err := rationalEvals(d, a, b)
+	require.NoError(t, err)
+	f := d.Interpolate(evals)
+	ca := playsnark.KZGCommit(srs, a)
+	cb := playsnark.KZGCommit(srs, b)
+	cf := playsnark.KZGCommit(srs, f)
+	proof := ProveRational(srs, transcript.New("test"), d, a, b, f, ca, cb, cf)
+	require.True(t, VerifyRational(srs, transcript.New("test"), d, ca, cb, cf, proof))
+
+	// f is not a / b on the domain
+	f2 := f.Add(constant(one(curve)))
+	cf2 := playsnark.KZGCommit(srs, f2)
+	proof = ProveRational(srs, transcript.New("test"), d, a, b, f2, ca, cb, cf2)
+	require.False(t, VerifyRational(srs, transcript.New("test"), d, ca, cb, cf2, proof))
+	// swapped numerator and denominator
+	proof = ProveRational(srs, transcript.New("test"), d, a, b, f, ca, cb, cf)
+	require.False(t, VerifyRational(srs, transcript.New("test"), d, cb, ca, cf, proof))
+}
+
+func TestRationalSumcheck(t *testing.T) {
+	for _, c := range playsnark.Curves {
+		t.Run(c.Name, func(t *testing.T) { testRationalSumcheck(t, c) })
+	}
+}
+
+func testRationalSumcheck(t *testing.T, curve Curve) {
+	srs := NewSetupWith(curve, 32)
+	d := playsnark.NewDomainWith(curve, 8)
+	a := randomPoly(curve, 10)
+	b := randomPoly(curve, 7)
+	ca := playsnark.KZGCommit(srs, a)
+	cb := playsnark.KZGCommit(srs, b)
+	sigma, err := SumRational(d, a, b)
+	require.NoError(t, err)
+	proof, err := ProveRationalSumcheck(srs, transcript.New("test"), d, a, b, ca, cb)
+	require.NoError(t, err)
+	require.True(t, VerifyRationalSumcheck(srs, transcript.New("test"), d, ca, cb, sigma, proof))
+
+	// wrong sum
+	wrong := curve.NewElement().Add(sigma, one(curve))
+	require.False(t, VerifyRationalSumcheck(srs, transcript.New("test"), d, ca, cb, wrong, proof))
+	// other numerator
+	ca2 := playsnark.KZGCommit(srs, randomPoly(curve, 10))
+	require.False(t, VerifyRationalSumcheck(srs, transcript.New("test"), d, ca2, cb, sigma, proof))
+
+	// the denominator vanishes on the domain
+	zb := d.Vanishing().Mul(randomPoly(curve, 1))
+	_, err = SumRational(d, a, zb)
+	require.Error(t, err)
+	_, err = ProveRationalSumcheck(srs, transcript.New("test"), d, a, zb, ca, playsnark.KZGCommit(srs, zb))
+	require.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package iop
+
+import (
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// The univariate sumcheck from Aurora https://eprint.iacr.org/2018/828.pdf
+// For H of size n, SUM k^i over H is n if i = 0 mod n and 0 otherwise, so
+// only the constant term of p mod Z_H contributes to the sum. Hence
+// SUM p(k) over H is sigma if and only if
+//
+//	p(x) = h(x) * Z_H(x) + x * g(x) + sigma / n	with deg(g) < n - 1
+//
+// The prover commits to g, with a degree bound, and to h, and the verifier
+// checks the equation at a random point.
+
+// Sum returns SUM p(k) for all k in the domain
+func Sum(d Domain, p Poly) Element {
+	acc := zero(d.Curve)
+	for i := 0; i < len(p); i += d.Size {
+		acc = acc.Add(acc, p[i])
+	}
+	return acc.Mul(acc, d.Curve.Element(playsnark.Value(d.Size)))
+}
+
+// Decompose returns h and g such that p(x) = h(x) * Z_H(x) + x * g(x) + c
+// where c is a constant and deg(g) < n - 1.
+func Decompose(d Domain, p Poly) (h Poly, g Poly) {
+	h, r := d.DivideByVanishing(p)
+	if len(h) == 0 {
+		h = constant(zero(d.Curve))
+	}
+	return h, coeffs(d.Curve, r, 1, d.Size)
+}
+
+// SumcheckProof is the proof that a committed polynomial p sums to a given
+// value over the domain
+type SumcheckProof struct {
+	// Commitments to g, with its degree bound, and to h
+	G      G1
+	GBound DegreeBound
+	H      G1
+	// Evaluations of p, g and h at the challenge zeta
+	PEval Element
+	GEval Element
+	HEval Element
+	// W is the KZG opening proof at zeta
+	W G1
+}
+
+// ProveSumcheck returns the proof that the polynomial p committed in c sums to
+// Sum(d, p) over the domain.
+func ProveSumcheck(srs KZGSetup, tr *transcript.Transcript, d Domain, p Poly, c G1) SumcheckProof {
+	var proof SumcheckProof
+	h, g := Decompose(d, p)
+	proof.G = playsnark.KZGCommit(srs, g)
+	proof.GBound = ProveDegreeBound(srs, g, d.Size-2)
+	proof.H = playsnark.KZGCommit(srs, h)
+	zeta := sumcheckChallenge(srs.Curve, tr, c, Sum(d, p), &proof)
+	multi := playsnark.KZGOpenMulti(srs, tr, []Poly{p, g, h}, []G1{c, proof.G, proof.H}, zeta)
+	proof.PEval, proof.GEval, proof.HEval = multi.Ys[0], multi.Ys[1], multi.Ys[2]
+	proof.W = multi.W
+	return proof
+}
+
+// VerifySumcheck returns true if the proof shows the polynomial committed in c
+// sums to sigma over the domain.
+func VerifySumcheck(srs KZGSetup, tr *transcript.Transcript, d Domain, c G1, sigma Element, proof SumcheckProof) bool {
+	if proof.GBound.Bound != d.Size-2 || !VerifyDegreeBound(srs, proof.G, proof.GBound) {
+		return false
+	}
+	zeta := sumcheckChallenge(srs.Curve, tr, c, sigma, &proof)
+	if !proof.PEval.Equal(SumcheckEval(d, zeta, proof.HEval, proof.GEval, sigma)) {
+		return false
+	}
+	multi := playsnark.KZGMultiProof{
+		Z:  zeta,
+		Ys: []Element{proof.PEval, proof.GEval, proof.HEval},
+		W:  proof.W,
+	}
+	return playsnark.KZGVerifyMulti(srs, tr, []G1{c, proof.G, proof.H}, multi)
+}
+
+// SumcheckEval returns h * Z_H(x) + x * g + sigma / n, which must be equal to
+// p(x) given the evaluations h = h(x) and g = g(x)
+func SumcheckEval(d Domain, x, h, g, sigma Element) Element {
+	res := d.Curve.NewElement().Mul(h, d.EvalVanishing(x))
+	res = res.Add(res, d.Curve.NewElement().Mul(x, g))
+	return res.Add(res, divByN(sigma, d.Size))
+}
+
+func sumcheckChallenge(curve Curve, tr *transcript.Transcript, c G1, sigma Element, proof *SumcheckProof) Element {
+	tr.AppendMessage("dom-sep", []byte("iop.sumcheck"))
+	tr.AppendPoint("p", c)
+	tr.AppendScalar("sigma", sigma)
+	tr.AppendPoint("g", proof.G)
+	tr.AppendPoint("g_shift", proof.GBound.Shifted)
+	tr.AppendPoint("h", proof.H)
+	return tr.ChallengeScalar("zeta", curve.Suite.G1())
+}
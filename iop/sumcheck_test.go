@@ -0,0 +1,106 @@
+package iop
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPoly(c Curve, d int) Poly {
+	p := make(Poly, d+1)
+	for i := range p {
+		p[i] = c.NewElement().Pick(random.New())
+	}
+	return p
+}
+
+func TestSum(t *testing.T) {
+	for _, c := range playsnark.Curves {
+		t.Run(c.Name, func(t *testing.T) { testSum(t, c) })
+	}
+}
+
+func testSum(t *testing.T, curve Curve) {
+	d := playsnark.NewDomainWith(curve, 8)
+	for _, deg := range []int{0, 5, 7, 8, 20} {
+		p := randomPoly(curve, deg)
+		sum := zero(curve)
+		for _, x := range d.Elements {
+			sum = sum.Add(sum, p.Eval(x))
+		}
+		require.True(t, Sum(d, p).Equal(sum))
+
+		h, g := Decompose(d, p)
+		require.Len(t, g, d.Size-1)
+		x := curve.NewElement().Pick(random.New())
+		require.True(t, p.Eval(x).Equal(SumcheckEval(d, x, h.Eval(x), g.Eval(x), sum)))
+	}
+}
+
+func TestSumcheck(t *testing.T) {
+	for _, c := range playsnark.Curves {
+		t.Run(c.Name, func(t *testing.T) { testSumcheck(t, c) })
+	}
+}
+
+func testSumcheck(t *testing.T, curve Curve) {
+	srs := NewSetupWith(curve, 32)
+	d := playsnark.NewDomainWith(curve, 8)
+	p := randomPoly(curve, 20)
+	c := playsnark.KZGCommit(srs, p)
+	sigma := Sum(d, p)
+	proof := ProveSumcheck(srs, transcript.New("test"), d, p, c)
+	require.True(t, VerifySumcheck(srs, transcript.New("test"), d, c, sigma, proof))
+
+	// wrong sum
+	wrong := curve.NewElement().Add(sigma, one(curve))
+	require.False(t, VerifySumcheck(srs, transcript.New("test"), d, c, wrong, proof))
+	// wrong polynomial
+	c2 := playsnark.KZGCommit(srs, randomPoly(curve, 20))
+	require.False(t, VerifySumcheck(srs, transcript.New("test"), d, c2, sigma, proof))
+	// wrong domain
+	require.False(t, VerifySumcheck(srs, transcript.New("test"), playsnark.NewDomainWith(curve, 16), c, sigma, proof))
+	// tampered evaluation
+	tampered := proof
+	tampered.GEval = curve.NewElement().Add(proof.GEval, one(curve))
+	require.False(t, VerifySumcheck(srs, transcript.New("test"), d, c, sigma, tampered))
+}
+
+func TestSumcheckDegreeBound(t *testing.T) {
+	for _, c := range playsnark.Curves {
+		t.Run(c.Name, func(t *testing.T) { testSumcheckDegreeBound(t, c) })
+	}
+}
+
+func testSumcheckDegreeBound(t *testing.T, curve Curve) {
+	// without the degree bound, a prover could claim any sum by moving the
+	// difference into a coefficient of g of degree n - 1: x * x^(n-1) = 1 on H
+	srs := NewSetupWith(curve, 32)
+	d := playsnark.NewDomainWith(curve, 8)
+	p := randomPoly(curve, 20)
+	c := playsnark.KZGCommit(srs, p)
+	sigma := Sum(d, p)
+	wrong := curve.NewElement().Add(sigma, curve.NewElement().SetInt64(8))
+
+	var proof SumcheckProof
+	h, g := Decompose(d, p)
+	// x * g + sigma / n = x * (g - x^(n-1)) + (sigma + n) / n - x^n + 1
+	// and x^n - 1 is moved to h
+	g = append(g, curve.NewElement().Neg(one(curve)))
+	h = h.Add(constant(one(curve)))
+	proof.G = playsnark.KZGCommit(srs, g)
+	proof.GBound = DegreeBound{Bound: d.Size - 2, Shifted: playsnark.KZGCommit(srs, shift(curve, g, srs.Degree()-d.Size+1))}
+	proof.H = playsnark.KZGCommit(srs, h)
+	tr := transcript.New("test")
+	zeta := sumcheckChallenge(curve, tr, c, wrong, &proof)
+	require.True(t, p.Eval(zeta).Equal(SumcheckEval(d, zeta, h.Eval(zeta), g.Eval(zeta), wrong)))
+	multi := playsnark.KZGOpenMulti(srs, tr, []Poly{p, g, h}, []G1{c, proof.G, proof.H}, zeta)
+	proof.PEval, proof.GEval, proof.HEval, proof.W = multi.Ys[0], multi.Ys[1], multi.Ys[2], multi.W
+	// only the degree bound catches it
+	require.False(t, VerifySumcheck(srs, transcript.New("test"), d, c, wrong, proof))
+	proof.GBound = ProveDegreeBound(srs, g, d.Size-1)
+	require.False(t, VerifySumcheck(srs, transcript.New("test"), d, c, wrong, proof))
+}
@@ -0,0 +1,243 @@
+package playsnark
+
+import (
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements the KZG polynomial commitment scheme from the paper "Constant-Size
+// Commitments to Polynomials and Their Applications"
+// https://www.iacr.org/archive/asiacrypt2010/6477178/6477178.pdf
+// The commitment to a polynomial p is simply g^p(s), where s is a secret
+// element from the trusted setup, computed blindly from the powers of s as in
+// the QAP based proof systems. The main difference is that the setup doesn't
+// depend on any circuit: it only depends on the maximum degree of the
+// polynomials committed to, which is why it is called universal.
+// To open the commitment at a point z, the prover uses the fact that
+// p(x) - p(z) is divisible by (x - z) and commits to the quotient
+//		w(x) = (p(x) - p(z)) / (x - z)
+// The verifier then checks the division holds at the point s with one
+// pairing equation:
+//		e(C - g^p(z), h) == e(W, h^s - h^z)
+
+// kzgToxicWaste contains the secret point of the setup. Anyone knowing it can
+// open a commitment to any value, it is kept here for testing purpose.
+type kzgToxicWaste struct {
+	S Element
+}
+
+// KZGSetup is the structured reference string (SRS) shared by all the provers
+// and verifiers.
+type KZGSetup struct {
+	tw kzgToxicWaste
+	// Curve is the curve of the commitments, the polynomials are defined over
+	// its scalar field
+	Curve Curve
+	// {s^i} on G1 for i:0->degree, used to commit to polynomials
+	G1Powers []G1
+	// {s^i} on G2 for i:0->maxPoints. The verifier only needs the first two
+	// for a single opening but opening a polynomial at k points requires
+	// committing to a polynomial of degree k on G2.
+	G2Powers []G2
+}
+
+// NewKZGSetup returns a setup on the default curve allowing to commit to
+// polynomials of degree up to degree and to open them at up to maxPoints points
+// at once.
+func NewKZGSetup(degree, maxPoints int) KZGSetup {
+	return NewKZGSetupWith(BLS12381, degree, maxPoints)
+}
+
+// NewKZGSetupWith returns a setup as NewKZGSetup on the given curve. The
+// systems built on KZG run on the curve of their setup.
+func NewKZGSetupWith(c Curve, degree, maxPoints int) KZGSetup {
+	if maxPoints < 1 {
+		maxPoints = 1
+	}
+	var tw kzgToxicWaste
+	tw.S = c.NewElement().Pick(random.New())
+	return KZGSetup{
+		tw:       tw,
+		Curve:    c,
+		G1Powers: GeneratePowersCommit(c.NewG1().Null(), tw.S, c.Element(1), degree),
+		G2Powers: GeneratePowersCommit(c.NewG2().Null(), tw.S, c.Element(1), maxPoints),
+	}
+}
+
+// Degree returns the maximum degree of the polynomials this setup can commit
+// to.
+func (k KZGSetup) Degree() int {
+	return len(k.G1Powers) - 1
+}
+
+// KZGCommit returns the commitment g^p(s) to the polynomial
+func KZGCommit(k KZGSetup, p Poly) G1 {
+	if len(p) > len(k.G1Powers) {
+		panic("polynomial degree too high for the setup")
+	}
+	return p.BlindEval(k.Curve.NewG1().Null(), k.G1Powers[:len(p)])
+}
+
+// kzgCommitG2 returns h^p(s), only used to commit to vanishing and
+// interpolation polynomials of small degree
+func kzgCommitG2(k KZGSetup, p Poly) G2 {
+	if len(p) > len(k.G2Powers) {
+		panic("too many opening points for the setup")
+	}
+	return p.BlindEval(k.Curve.NewG2().Null(), k.G2Powers[:len(p)])
+}
+
+// KZGProof is the proof that a committed polynomial evaluates to Y at the
+// point Z.
+type KZGProof struct {
+	Z Element
+	Y Element
+	// W is the commitment to the quotient (p(x) - p(z)) / (x - z)
+	W G1
+}
+
+// KZGOpen returns the evaluation of p at z with the proof that it is correct
+func KZGOpen(k KZGSetup, p Poly, z Element) KZGProof {
+	y := p.Eval(z)
+	return KZGProof{
+		Z: z.Clone(),
+		Y: y,
+		W: KZGCommit(k, kzgQuotient(p, Poly([]Element{y}), Poly([]Element{k.Curve.NewElement().Neg(z), k.Curve.Element(1)}))),
+	}
+}
+
+// KZGVerify returns true if the proof shows the polynomial committed in c
+// evaluates to proof.Y at proof.Z:
+//
+//	e(C - g^y, h) == e(W, h^s - h^z)
+func KZGVerify(k KZGSetup, c G1, proof KZGProof) bool {
+	// C - g^y
+	left := k.Curve.NewG1().Mul(proof.Y, nil)
+	left = left.Sub(c, left)
+	// h^s - h^z
+	sz := k.Curve.NewG2().Mul(proof.Z, nil)
+	sz = sz.Sub(k.G2Powers[1], sz)
+	return k.Curve.Pair(left, k.G2Powers[0]).Equal(k.Curve.Pair(proof.W, sz))
+}
+
+// KZGBatchProof is the proof that a committed polynomial evaluates to Ys[i] at
+// the point Zs[i] for all i, with a single group element.
+type KZGBatchProof struct {
+	Zs []Element
+	Ys []Element
+	// W is the commitment to the quotient (p(x) - I(x)) / Z(x) where I is the
+	// polynomial interpolating all the evaluations and Z the polynomial
+	// vanishing on all the points
+	W G1
+}
+
+// KZGOpenBatch returns the evaluations of p at all the given points with a
+// single proof that they are all correct. The points must be distinct.
+func KZGOpenBatch(k KZGSetup, p Poly, zs []Element) KZGBatchProof {
+	var proof KZGBatchProof
+	for _, z := range zs {
+		proof.Zs = append(proof.Zs, z.Clone())
+		proof.Ys = append(proof.Ys, p.Eval(z))
+	}
+	i := interpolatePoints(k.Curve, proof.Zs, proof.Ys)
+	proof.W = KZGCommit(k, kzgQuotient(p, i, vanishingPoly(k.Curve, proof.Zs)))
+	return proof
+}
+
+// KZGVerifyBatch returns true if the proof shows the polynomial committed in c
+// evaluates to all the given evaluations:
+//
+//	e(C - g^I(s), h) == e(W, h^Z(s))
+func KZGVerifyBatch(k KZGSetup, c G1, proof KZGBatchProof) bool {
+	if len(proof.Zs) != len(proof.Ys) || len(proof.Zs) == 0 || len(proof.Zs) >= len(k.G2Powers) {
+		return false
+	}
+	i := interpolatePoints(k.Curve, proof.Zs, proof.Ys)
+	left := KZGCommit(k, i)
+	left = left.Sub(c, left)
+	z := kzgCommitG2(k, vanishingPoly(k.Curve, proof.Zs))
+	return k.Curve.Pair(left, k.G2Powers[0]).Equal(k.Curve.Pair(proof.W, z))
+}
+
+// KZGMultiProof is the proof that several committed polynomials evaluate to
+// Ys[i] at the same point Z, with a single group element.
+type KZGMultiProof struct {
+	Z  Element
+	Ys []Element
+	// W is the commitment to SUM gamma^i * (p_i(x) - p_i(z)) / (x - z) where
+	// gamma is derived from the transcript
+	W G1
+}
+
+// KZGOpenMulti returns the evaluations of all the polynomials at z with a
+// single proof. The commitments cs to the polynomials are absorbed in the
+// transcript before deriving the random challenge combining them, so the
+// verifier must use a transcript in the same state.
+func KZGOpenMulti(k KZGSetup, tr *transcript.Transcript, ps []Poly, cs []G1, z Element) KZGMultiProof {
+	if len(ps) != len(cs) {
+		panic("mismatch of length between polynomials and commitments")
+	}
+	proof := KZGMultiProof{Z: z.Clone()}
+	for _, p := range ps {
+		proof.Ys = append(proof.Ys, p.Eval(z))
+	}
+	gamma := kzgMultiChallenge(k.Curve, tr, cs, proof.Z, proof.Ys)
+	// since the division by (x - z) is linear, we can first combine all the
+	// polynomials and then divide only once
+	var acc = Poly([]Element{k.Curve.NewElement()})
+	var accY = k.Curve.NewElement()
+	var gi = k.Curve.Element(1)
+	for i, p := range ps {
+		acc = acc.Add(p.Mul(Poly([]Element{gi})))
+		accY = accY.Add(accY, k.Curve.NewElement().Mul(gi, proof.Ys[i]))
+		gi = gi.Mul(gi, gamma)
+	}
+	divisor := Poly([]Element{k.Curve.NewElement().Neg(z), k.Curve.Element(1)})
+	proof.W = KZGCommit(k, kzgQuotient(acc, Poly([]Element{accY}), divisor))
+	return proof
+}
+
+// KZGVerifyMulti returns true if the proof shows each polynomial committed in
+// cs[i] evaluates to proof.Ys[i] at proof.Z. It combines all the commitments
+// with the powers of gamma and checks a single opening:
+//
+//	e(SUM gamma^i * (C_i - g^y_i), h) == e(W, h^s - h^z)
+func KZGVerifyMulti(k KZGSetup, tr *transcript.Transcript, cs []G1, proof KZGMultiProof) bool {
+	if len(cs) != len(proof.Ys) {
+		return false
+	}
+	gamma := kzgMultiChallenge(k.Curve, tr, cs, proof.Z, proof.Ys)
+	var c = k.Curve.NewG1().Null()
+	var y = k.Curve.NewElement()
+	var gi = k.Curve.Element(1)
+	for i := range cs {
+		c = c.Add(c, k.Curve.NewG1().Mul(gi, cs[i]))
+		y = y.Add(y, k.Curve.NewElement().Mul(gi, proof.Ys[i]))
+		gi = gi.Mul(gi, gamma)
+	}
+	return KZGVerify(k, c, KZGProof{Z: proof.Z, Y: y, W: proof.W})
+}
+
+// kzgMultiChallenge absorbs all the public information of a multi opening in
+// the transcript and returns the challenge combining the polynomials.
+func kzgMultiChallenge(c Curve, tr *transcript.Transcript, cs []G1, z Element, ys []Element) Element {
+	tr.AppendMessage("dom-sep", []byte("kzg.multi"))
+	tr.AppendUint64("n", uint64(len(cs)))
+	for i := range cs {
+		tr.AppendPoint("C", cs[i])
+		tr.AppendScalar("y", ys[i])
+	}
+	tr.AppendScalar("z", z)
+	return tr.ChallengeScalar("gamma", c.Suite.G1())
+}
+
+// kzgQuotient returns (p - i) / d and panics if the division has a remainder,
+// i.e. if the prover tries to open to a wrong evaluation.
+func kzgQuotient(p, i, d Poly) Poly {
+	num := p.Sub(i)
+	q, rem := num.Div2(d)
+	if len(rem.Normalize()) > 0 {
+		panic("kzg: the polynomial doesn't evaluate to the opened values")
+	}
+	return q
+}
@@ -0,0 +1,108 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKZGCommit(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			k := NewKZGSetupWith(curve, 8, 1)
+			require.Equal(t, 8, k.Degree())
+			p := randomPolyWith(curve, 5)
+			// the commitment is g^p(s)
+			exp := curve.NewG1().Mul(p.Eval(k.tw.S), nil)
+			require.True(t, exp.Equal(KZGCommit(k, p)))
+			// degree too high
+			require.Panics(t, func() { KZGCommit(k, randomPolyWith(curve, 9)) })
+		})
+	}
+}
+
+func TestKZGOpen(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			k := NewKZGSetupWith(curve, 8, 1)
+			p := randomPolyWith(curve, 8)
+			c := KZGCommit(k, p)
+			z := curve.NewElement().Pick(random.New())
+			proof := KZGOpen(k, p, z)
+			require.True(t, proof.Y.Equal(p.Eval(z)))
+			require.True(t, KZGVerify(k, c, proof))
+
+			// wrong evaluation
+			wrong := proof
+			wrong.Y = curve.NewElement().Add(proof.Y, curve.Element(1))
+			require.False(t, KZGVerify(k, c, wrong))
+			// wrong point
+			wrong = proof
+			wrong.Z = curve.NewElement().Add(proof.Z, curve.Element(1))
+			require.False(t, KZGVerify(k, c, wrong))
+			// wrong commitment
+			require.False(t, KZGVerify(k, KZGCommit(k, randomPolyWith(curve, 8)), proof))
+
+			// opening a constant polynomial gives an empty quotient
+			cst := randomPolyWith(curve, 0)
+			require.True(t, KZGVerify(k, KZGCommit(k, cst), KZGOpen(k, cst, z)))
+		})
+	}
+}
+
+func TestKZGOpenBatch(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			k := NewKZGSetupWith(curve, 8, 4)
+			p := randomPolyWith(curve, 6)
+			c := KZGCommit(k, p)
+			var zs []Element
+			for i := 0; i < 4; i++ {
+				zs = append(zs, curve.NewElement().Pick(random.New()))
+			}
+			proof := KZGOpenBatch(k, p, zs)
+			for i := range zs {
+				require.True(t, proof.Ys[i].Equal(p.Eval(zs[i])))
+			}
+			require.True(t, KZGVerifyBatch(k, c, proof))
+			// a single point is the same as a regular opening
+			require.True(t, KZGVerifyBatch(k, c, KZGOpenBatch(k, p, zs[:1])))
+
+			// one wrong evaluation
+			proof.Ys[2] = curve.NewElement().Add(proof.Ys[2], curve.Element(1))
+			require.False(t, KZGVerifyBatch(k, c, proof))
+			// too many points for the setup
+			zs = append(zs, curve.NewElement().Pick(random.New()))
+			require.False(t, KZGVerifyBatch(k, c, KZGBatchProof{Zs: zs, Ys: zs, W: c}))
+		})
+	}
+}
+
+func TestKZGOpenMulti(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			k := NewKZGSetupWith(curve, 8, 1)
+			ps := []Poly{randomPolyWith(curve, 8), randomPolyWith(curve, 3), randomPolyWith(curve, 0)}
+			var cs []G1
+			for _, p := range ps {
+				cs = append(cs, KZGCommit(k, p))
+			}
+			z := curve.NewElement().Pick(random.New())
+			proof := KZGOpenMulti(k, transcript.New("test.kzg"), ps, cs, z)
+			for i := range ps {
+				require.True(t, proof.Ys[i].Equal(ps[i].Eval(z)))
+			}
+			require.True(t, KZGVerifyMulti(k, transcript.New("test.kzg"), cs, proof))
+			// different transcript
+			require.False(t, KZGVerifyMulti(k, transcript.New("test.other"), cs, proof))
+			// swapped commitments
+			swapped := []G1{cs[1], cs[0], cs[2]}
+			require.False(t, KZGVerifyMulti(k, transcript.New("test.kzg"), swapped, proof))
+			// wrong evaluation
+			proof.Ys[1] = curve.NewElement().Add(proof.Ys[1], curve.Element(1))
+			require.False(t, KZGVerifyMulti(k, transcript.New("test.kzg"), cs, proof))
+		})
+	}
+}
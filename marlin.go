@@ -0,0 +1,609 @@
+package playsnark
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements Marlin https://eprint.iacr.org/2019/1047.pdf
+// As PLONK, Marlin uses a universal KZG setup but it directly proves the
+// satisfiability of a R1CS: given the matrices A, B and C (left, right and out)
+// and the vector z of all the variables, the prover shows that
+//
+//	z_A = A * z, z_B = B * z, z_C = C * z and z_A o z_B = z_C
+//
+// All the vectors are interpolated over a multiplicative subgroup H, the i-th
+// variable and the i-th constraint corresponding to the point w^i. The
+// Hadamard product is a simple divisibility check by Z_H. The linear relations
+// are checked at once on a random linear combination of the constraints and
+// the matrices with the univariate sumcheck: for a polynomial q, SUM q(k) over
+// H is equal to sigma if and only if
+//
+//	q(x) = h(x) * Z_H(x) + x * g(x) + sigma / |H|	with deg(g) < |H| - 1
+//
+// since SUM k^i over H is |H| if i = 0 mod |H| and 0 otherwise.
+//
+// The matrices are "holographic": the indexer encodes each matrix with three
+// polynomials row, col and val interpolating its non zero entries over
+// another subgroup K, and commits to them. The verifier never reads the
+// matrices, it only needs one evaluation of a polynomial depending on them,
+// which the prover proves with a second sumcheck over K.
+//
+// This implementation keeps the structure of the paper but simplifies a few
+// points: each matrix has its own row, col and val polynomials, the inputs
+// occupy the first points of H instead of a subgroup and the degree bounds
+// are enforced by committing to x^(D-d) * g(x) as well, D being the degree of
+// the setup, and checking both evaluations are consistent.
+
+// MarlinVerifyingKey contains the universal setup and the commitments to the
+// index of the R1CS
+type MarlinVerifyingKey struct {
+	SRS KZGSetup
+	// H is the domain of the variables and constraints and K the domain of
+	// the non zero entries of the matrices
+	H Domain
+	K Domain
+	// NbPublic is the number of public variables: "const", inputs and outputs
+	NbPublic int
+	// Commitments to the row, col and val polynomials of A, B and C
+	Row [3]G1
+	Col [3]G1
+	Val [3]G1
+}
+
+// MarlinProvingKey contains the verifying key and the index of the R1CS
+type MarlinProvingKey struct {
+	MarlinVerifyingKey
+	matrices [3]Matrix
+	row      [3]Poly
+	col      [3]Poly
+	val      [3]Poly
+	// evaluations of row, col and val over K
+	rowEvals [3][]Element
+	colEvals [3][]Element
+	valEvals [3][]Element
+}
+
+// NewMarlinSetup returns a universal setup for all the R1CS with up to
+// maxSize constraints and variables, and up to maxNonZero non zero entries in
+// each matrix. The setup is on the default curve.
+func NewMarlinSetup(maxSize, maxNonZero int) KZGSetup {
+	return NewMarlinSetupWith(BLS12381, maxSize, maxNonZero)
+}
+
+// NewMarlinSetupWith returns a universal setup as NewMarlinSetup on the given
+// curve.
+func NewMarlinSetupWith(c Curve, maxSize, maxNonZero int) KZGSetup {
+	return NewKZGSetupWith(c, marlinDegree(nextPowerOfTwo(maxSize), nextPowerOfTwo(maxNonZero)), 1)
+}
+
+// marlinDegree returns the degree of the highest polynomial committed for the
+// domains of size n and m: the mask of the first sumcheck has degree 2n - 1 and
+// the quotient of the second sumcheck has degree 6m - 7.
+func marlinDegree(n, m int) int {
+	if 2*n > 6*m {
+		return 2 * n
+	}
+	return 6 * m
+}
+
+// MarlinIndex is the indexer: it encodes the matrices of the R1CS and commits
+// to them. Anybody can run it and check the commitments. The R1CS is encoded
+// over the scalar field of the curve of the setup.
+func MarlinIndex(srs KZGSetup, r R1CS) MarlinProvingKey {
+	var pk MarlinProvingKey
+	curve := srs.Curve
+	pk.matrices = [3]Matrix{r.left, r.right, r.out}
+	pk.NbPublic = r.nbIO()
+	size := len(r.vars)
+	if len(r.left) > size {
+		size = len(r.left)
+	}
+	if pk.NbPublic+1 > size {
+		size = pk.NbPublic + 1
+	}
+	nonZero := 2
+	for _, m := range pk.matrices {
+		if nz := len(marlinEntries(m)); nz > nonZero {
+			nonZero = nz
+		}
+	}
+	pk.H = NewDomainWith(curve, nextPowerOfTwo(size))
+	pk.K = NewDomainWith(curve, nextPowerOfTwo(nonZero))
+	if srs.Degree() < marlinDegree(pk.H.Size, pk.K.Size) {
+		panic(fmt.Sprintf("setup of degree %d too small for the R1CS", srs.Degree()))
+	}
+	pk.SRS = srs
+
+	// the entry M[i][j] is encoded as row(k) = w^i, col(k) = w^j and
+	// val(k) = M[i][j] * w^j / n, the padding entries have val(k) = 0
+	n := pk.H.Size
+	nInv := curve.NewElement().Inv(curve.Element(Value(n)))
+	for m, matrix := range pk.matrices {
+		entries := marlinEntries(matrix)
+		for k := 0; k < pk.K.Size; k++ {
+			i, j, v := 0, 0, curve.NewElement()
+			if k < len(entries) {
+				i, j = entries[k][0], entries[k][1]
+				v = curve.Element(matrix[i][j])
+				v = v.Mul(v, curve.NewElement().Mul(pk.H.Elements[j], nInv))
+			}
+			pk.rowEvals[m] = append(pk.rowEvals[m], pk.H.Elements[i])
+			pk.colEvals[m] = append(pk.colEvals[m], pk.H.Elements[j])
+			pk.valEvals[m] = append(pk.valEvals[m], v)
+		}
+		pk.row[m] = pk.K.Interpolate(pk.rowEvals[m])
+		pk.col[m] = pk.K.Interpolate(pk.colEvals[m])
+		pk.val[m] = pk.K.Interpolate(pk.valEvals[m])
+		pk.Row[m] = KZGCommit(srs, pk.row[m])
+		pk.Col[m] = KZGCommit(srs, pk.col[m])
+		pk.Val[m] = KZGCommit(srs, pk.val[m])
+	}
+	return pk
+}
+
+// marlinEntries returns the positions [i, j] of the non zero entries of the
+// matrix, row by row
+func marlinEntries(m Matrix) [][2]int {
+	var entries [][2]int
+	for i := range m {
+		for j := range m[i] {
+			if m[i][j] != 0 {
+				entries = append(entries, [2]int{i, j})
+			}
+		}
+	}
+	return entries
+}
+
+// MarlinProof contains the commitments, the sums and the evaluations sent by
+// the prover
+type MarlinProof struct {
+	// Commitments to the private part of z, to z_A, z_B, z_C, to the quotient
+	// of z_A * z_B - z_C and to the mask of the first sumcheck
+	W  G1
+	ZA G1
+	ZB G1
+	ZC G1
+	H0 G1
+	S  G1
+	// Sigma1 is the sum of the mask over H
+	Sigma1 Element
+	// First sumcheck: the combination of the matrices t(x), the polynomials g
+	// and h over H and g shifted to the degree of the setup
+	T       G1
+	GH      G1
+	GHShift G1
+	HH      G1
+	// Second sumcheck: Sigma2 = t(beta_1) and the polynomials g and h over K
+	Sigma2  Element
+	GK      G1
+	GKShift G1
+	HK      G1
+	// Evaluations at beta_1
+	WEval       Element
+	ZAEval      Element
+	ZBEval      Element
+	ZCEval      Element
+	H0Eval      Element
+	SEval       Element
+	GHEval      Element
+	GHShiftEval Element
+	HHEval      Element
+	// Evaluations at beta_2
+	GKEval      Element
+	GKShiftEval Element
+	HKEval      Element
+	RowEvals    [3]Element
+	ColEvals    [3]Element
+	ValEvals    [3]Element
+	// KZG opening proofs at beta_1 and beta_2
+	WBeta1 G1
+	WBeta2 G1
+}
+
+// marlinChallenges are the random challenges of the verifier derived from the
+// transcript
+type marlinChallenges struct {
+	alpha Element
+	eta   [3]Element
+	beta1 Element
+	beta2 Element
+}
+
+// MarlinProve returns a proof that the prover knows a solution of the indexed
+// R1CS. As for Groth16, the solution contains all the variables in order,
+// starting with "const". If it doesn't satisfy the R1CS, the sumchecks don't
+// hold and the proof is invalid.
+func MarlinProve(pk MarlinProvingKey, sol Vector) MarlinProof {
+	var proof MarlinProof
+	var ch marlinChallenges
+	h := pk.H
+	n := h.Size
+	srs := pk.SRS
+	curve := srs.Curve
+	tr := pk.transcript(sol[:pk.NbPublic])
+
+	// Round 1: commit to the private part of z and to the vectors z_M = M * z.
+	// With x^(x) interpolating the public values on the first points of H and
+	// Z_X(x) vanishing on them, z(x) = x^(x) + w(x) * Z_X(x). All the
+	// polynomials are blinded with a random multiple of Z_H.
+	var zEvals []Element
+	for _, v := range sol {
+		zEvals = append(zEvals, curve.Element(v))
+	}
+	xhat := interpolatePoints(curve, h.Elements[:pk.NbPublic], zEvals[:pk.NbPublic])
+	zx := vanishingPoly(curve, h.Elements[:pk.NbPublic])
+	num := h.Interpolate(zEvals).Sub(xhat)
+	w, _ := num.Div2(zx)
+	// Z_H / Z_X vanishes on the other points of H
+	w = w.Add(vanishingPoly(curve, h.Elements[pk.NbPublic:]).Scale(randomElement(curve)))
+	z := xhat.Add(w.Mul(zx))
+	var zm [3]Poly
+	for m, matrix := range pk.matrices {
+		var evals []Element
+		for _, v := range matrix.Mul(sol) {
+			evals = append(evals, curve.Element(v))
+		}
+		zm[m] = h.Interpolate(evals).Add(h.Vanishing().Scale(randomElement(curve)))
+	}
+	h0, _ := h.DivideByVanishing(zm[0].Mul(zm[1]).Sub(zm[2]))
+	// the mask hides the evaluations of the first sumcheck
+	s := newPoly(curve, 2*n-1)
+	for i := range s {
+		s[i] = randomElement(curve)
+	}
+	proof.Sigma1 = marlinSum(h, s)
+	proof.W = KZGCommit(srs, w)
+	proof.ZA = KZGCommit(srs, zm[0])
+	proof.ZB = KZGCommit(srs, zm[1])
+	proof.ZC = KZGCommit(srs, zm[2])
+	proof.H0 = KZGCommit(srs, h0)
+	proof.S = KZGCommit(srs, s)
+	ch.alpha, ch.eta = pk.round1(tr, &proof)
+
+	// Round 2: the first sumcheck shows that z_M = M * z for all M at once.
+	// With u(a, x) = (a^n - x^n) / (a - x), which is zero on H except for
+	// u(a, a), the prover commits to
+	//	t(x) = SUM eta_M * SUM_i u(alpha, w^i) * M(w^i, x)
+	// where M(w^i, x) interpolates the i-th row of M, and shows that
+	//	q_1(x) = s(x) + u(alpha, x) * SUM eta_M * z_M(x) - t(x) * z(x)
+	// sums to sigma_1 over H. Without the mask, the sum would be
+	// SUM_i u(alpha, w^i) * SUM eta_M * (z_M[i] - <M[i], z>) which is zero for
+	// a random alpha only if all the constraints hold.
+	uAlpha := newPoly(curve, n-1)
+	for i := range uAlpha {
+		// u(a, x) = SUM a^(n-1-i) * x^i
+		uAlpha[n-1-i] = expElement(ch.alpha, big.NewInt(int64(i)))
+	}
+	tEvals := make([]Element, n)
+	for j := range tEvals {
+		tEvals[j] = curve.NewElement()
+	}
+	alphaN := h.EvalVanishing(ch.alpha)
+	for m, matrix := range pk.matrices {
+		for _, e := range marlinEntries(matrix) {
+			// u(alpha, w^i) = (alpha^n - 1) / (alpha - w^i)
+			u := curve.NewElement().Sub(ch.alpha, h.Elements[e[0]])
+			u = u.Div(alphaN, u)
+			u = u.Mul(u, curve.NewElement().Mul(ch.eta[m], curve.Element(matrix[e[0]][e[1]])))
+			tEvals[e[1]] = tEvals[e[1]].Add(tEvals[e[1]], u)
+		}
+	}
+	t := h.Interpolate(tEvals)
+	etaZ := Poly([]Element{curve.NewElement()})
+	for m := range zm {
+		etaZ = etaZ.Add(zm[m].Scale(ch.eta[m]))
+	}
+	q1 := s.Add(uAlpha.Mul(etaZ)).Sub(t.Mul(z))
+	hh, gh := marlinSumcheck(h, q1)
+	ghShift := marlinShift(curve, gh, n-2, srs.Degree())
+	proof.T = KZGCommit(srs, t)
+	proof.GH = KZGCommit(srs, gh)
+	proof.GHShift = KZGCommit(srs, ghShift)
+	proof.HH = KZGCommit(srs, hh)
+	ch.beta1 = pk.round2(tr, &proof)
+
+	// Round 3: the second sumcheck shows that sigma_2 = t(beta_1). Since
+	// u(x, w^j) / u(w^j, w^j) = L_j(x) and u(w^j, w^j) = n / w^j,
+	//	t(beta_1) = SUM eta_M * SUM_k u(alpha, row(k)) * u(beta_1, col(k)) * val(k)
+	//			  = SUM_k f(k) with, as row(k)^n = col(k)^n = 1,
+	//	f(x) = SUM eta_M * Z_H(alpha) * Z_H(beta_1) * val_M(x)
+	//				/ ((alpha - row_M(x)) * (beta_1 - col_M(x)))
+	// The prover interpolates f over K, such that f(x) = x * g(x) + sigma_2 / m,
+	// and shows that f(x) = a(x) / b(x) on K with a quotient h such that
+	//	a(x) - b(x) * (x * g(x) + sigma_2 / m) = h(x) * Z_K(x)
+	k := pk.K
+	proof.Sigma2 = t.Eval(ch.beta1)
+	fEvals := make([]Element, k.Size)
+	for i := range fEvals {
+		fEvals[i] = curve.NewElement()
+		for m := range pk.matrices {
+			fEvals[i] = fEvals[i].Add(fEvals[i], pk.rationalTerm(ch, m, pk.rowEvals[m][i], pk.colEvals[m][i], pk.valEvals[m][i]))
+		}
+	}
+	f := k.Interpolate(fEvals)
+	gk := plonkCoeffs(curve, f, 1, k.Size)
+	a, b := pk.rationalPolys(ch)
+	sumTerm := Poly([]Element{curve.NewElement().Div(proof.Sigma2, curve.Element(Value(k.Size)))})
+	hk, _ := k.DivideByVanishing(a.Sub(b.Mul(Poly([]Element{curve.NewElement(), curve.Element(1)}).Mul(gk).Add(sumTerm))))
+	gkShift := marlinShift(curve, gk, k.Size-2, srs.Degree())
+	proof.GK = KZGCommit(srs, gk)
+	proof.GKShift = KZGCommit(srs, gkShift)
+	proof.HK = KZGCommit(srs, hk)
+	ch.beta2 = pk.round3(tr, &proof)
+
+	// Evaluations and openings
+	proof.WEval = w.Eval(ch.beta1)
+	proof.ZAEval = zm[0].Eval(ch.beta1)
+	proof.ZBEval = zm[1].Eval(ch.beta1)
+	proof.ZCEval = zm[2].Eval(ch.beta1)
+	proof.H0Eval = h0.Eval(ch.beta1)
+	proof.SEval = s.Eval(ch.beta1)
+	proof.GHEval = gh.Eval(ch.beta1)
+	proof.GHShiftEval = ghShift.Eval(ch.beta1)
+	proof.HHEval = hh.Eval(ch.beta1)
+	proof.GKEval = gk.Eval(ch.beta2)
+	proof.GKShiftEval = gkShift.Eval(ch.beta2)
+	proof.HKEval = hk.Eval(ch.beta2)
+	for m := range pk.matrices {
+		proof.RowEvals[m] = pk.row[m].Eval(ch.beta2)
+		proof.ColEvals[m] = pk.col[m].Eval(ch.beta2)
+		proof.ValEvals[m] = pk.val[m].Eval(ch.beta2)
+	}
+	appendMarlinEvals(tr, &proof)
+
+	polys := []Poly{w, zm[0], zm[1], zm[2], h0, s, t, gh, ghShift, hh}
+	proof.WBeta1 = KZGOpenMulti(srs, tr, polys, pk.beta1Commits(&proof), ch.beta1).W
+	polys = []Poly{gk, gkShift, hk}
+	for m := range pk.matrices {
+		polys = append(polys, pk.row[m], pk.col[m], pk.val[m])
+	}
+	proof.WBeta2 = KZGOpenMulti(srs, tr, polys, pk.beta2Commits(&proof), ch.beta2).W
+	return proof
+}
+
+// MarlinVerify returns true if the proof is valid for the given public
+// values: "const" followed by the inputs and outputs, i.e. sol[:NbPublic].
+func MarlinVerify(vk MarlinVerifyingKey, p MarlinProof, io Vector) bool {
+	if len(io) != vk.NbPublic {
+		return false
+	}
+	var ch marlinChallenges
+	curve := vk.SRS.Curve
+	tr := vk.transcript(io)
+	ch.alpha, ch.eta = vk.round1(tr, &p)
+	ch.beta1 = vk.round2(tr, &p)
+	ch.beta2 = vk.round3(tr, &p)
+	appendMarlinEvals(tr, &p)
+	h := vk.H
+	k := vk.K
+	D := vk.SRS.Degree()
+
+	// z_A * z_B - z_C = h_0 * Z_H at beta_1
+	zhBeta := h.EvalVanishing(ch.beta1)
+	left := curve.NewElement().Mul(p.ZAEval, p.ZBEval)
+	left = left.Sub(left, p.ZCEval)
+	if !left.Equal(curve.NewElement().Mul(p.H0Eval, zhBeta)) {
+		return false
+	}
+
+	// first sumcheck at beta_1, with z(beta_1) = x^(beta_1) + w(beta_1) * Z_X(beta_1)
+	var xs []Element
+	for _, v := range io {
+		xs = append(xs, curve.Element(v))
+	}
+	xhat := interpolatePoints(curve, h.Elements[:vk.NbPublic], xs).Eval(ch.beta1)
+	zx := vanishingPoly(curve, h.Elements[:vk.NbPublic]).Eval(ch.beta1)
+	z := curve.NewElement().Add(xhat, curve.NewElement().Mul(p.WEval, zx))
+	// u(alpha, beta_1) = (alpha^n - beta_1^n) / (alpha - beta_1)
+	u := curve.NewElement().Sub(h.EvalVanishing(ch.alpha), zhBeta)
+	u = u.Div(u, curve.NewElement().Sub(ch.alpha, ch.beta1))
+	etaZ := curve.NewElement()
+	for m, zm := range []Element{p.ZAEval, p.ZBEval, p.ZCEval} {
+		etaZ = etaZ.Add(etaZ, curve.NewElement().Mul(ch.eta[m], zm))
+	}
+	q1 := curve.NewElement().Add(p.SEval, curve.NewElement().Mul(u, etaZ))
+	q1 = q1.Sub(q1, curve.NewElement().Mul(p.Sigma2, z))
+	if !q1.Equal(marlinSumcheckEval(h, ch.beta1, p.HHEval, p.GHEval, p.Sigma1)) {
+		return false
+	}
+	if !p.GHShiftEval.Equal(curve.NewElement().Mul(p.GHEval, expElement(ch.beta1, big.NewInt(int64(D-(h.Size-2)))))) {
+		return false
+	}
+
+	// second sumcheck at beta_2: a(beta_2) - b(beta_2) * f(beta_2) = h(beta_2) * Z_K(beta_2)
+	a, b := vk.rationalEvals(ch, p.RowEvals, p.ColEvals, p.ValEvals)
+	f := marlinSumcheckEval(k, ch.beta2, curve.NewElement(), p.GKEval, p.Sigma2)
+	left = curve.NewElement().Sub(a, curve.NewElement().Mul(b, f))
+	if !left.Equal(curve.NewElement().Mul(p.HKEval, k.EvalVanishing(ch.beta2))) {
+		return false
+	}
+	if !p.GKShiftEval.Equal(curve.NewElement().Mul(p.GKEval, expElement(ch.beta2, big.NewInt(int64(D-(k.Size-2)))))) {
+		return false
+	}
+
+	// openings, t(beta_1) must be sigma_2
+	ys := []Element{p.WEval, p.ZAEval, p.ZBEval, p.ZCEval, p.H0Eval, p.SEval, p.Sigma2, p.GHEval, p.GHShiftEval, p.HHEval}
+	if !KZGVerifyMulti(vk.SRS, tr, vk.beta1Commits(&p), KZGMultiProof{Z: ch.beta1, Ys: ys, W: p.WBeta1}) {
+		return false
+	}
+	ys = []Element{p.GKEval, p.GKShiftEval, p.HKEval}
+	for m := range p.RowEvals {
+		ys = append(ys, p.RowEvals[m], p.ColEvals[m], p.ValEvals[m])
+	}
+	return KZGVerifyMulti(vk.SRS, tr, vk.beta2Commits(&p), KZGMultiProof{Z: ch.beta2, Ys: ys, W: p.WBeta2})
+}
+
+// transcript returns the transcript bound to the index and the public values
+func (vk *MarlinVerifyingKey) transcript(io Vector) *transcript.Transcript {
+	tr := transcript.New("marlin")
+	tr.AppendUint64("n", uint64(vk.H.Size))
+	tr.AppendUint64("m", uint64(vk.K.Size))
+	for m := range vk.Row {
+		tr.AppendPoint("row", vk.Row[m])
+		tr.AppendPoint("col", vk.Col[m])
+		tr.AppendPoint("val", vk.Val[m])
+	}
+	for _, v := range io {
+		tr.AppendScalar("public", vk.SRS.Curve.Element(v))
+	}
+	return tr
+}
+
+func (vk *MarlinVerifyingKey) round1(tr *transcript.Transcript, p *MarlinProof) (Element, [3]Element) {
+	for _, c := range []G1{p.W, p.ZA, p.ZB, p.ZC, p.H0, p.S} {
+		tr.AppendPoint("round1", c)
+	}
+	tr.AppendScalar("sigma1", p.Sigma1)
+	alpha := tr.ChallengeScalar("alpha", vk.SRS.Curve.Suite.G1())
+	var eta [3]Element
+	for m := range eta {
+		eta[m] = tr.ChallengeScalar("eta", vk.SRS.Curve.Suite.G1())
+	}
+	return alpha, eta
+}
+
+func (vk *MarlinVerifyingKey) round2(tr *transcript.Transcript, p *MarlinProof) Element {
+	for _, c := range []G1{p.T, p.GH, p.GHShift, p.HH} {
+		tr.AppendPoint("round2", c)
+	}
+	return tr.ChallengeScalar("beta1", vk.SRS.Curve.Suite.G1())
+}
+
+func (vk *MarlinVerifyingKey) round3(tr *transcript.Transcript, p *MarlinProof) Element {
+	tr.AppendScalar("sigma2", p.Sigma2)
+	for _, c := range []G1{p.GK, p.GKShift, p.HK} {
+		tr.AppendPoint("round3", c)
+	}
+	return tr.ChallengeScalar("beta2", vk.SRS.Curve.Suite.G1())
+}
+
+// beta1Commits returns the commitments opened at beta_1
+func (vk *MarlinVerifyingKey) beta1Commits(p *MarlinProof) []G1 {
+	return []G1{p.W, p.ZA, p.ZB, p.ZC, p.H0, p.S, p.T, p.GH, p.GHShift, p.HH}
+}
+
+// beta2Commits returns the commitments opened at beta_2
+func (vk *MarlinVerifyingKey) beta2Commits(p *MarlinProof) []G1 {
+	cs := []G1{p.GK, p.GKShift, p.HK}
+	for m := range vk.Row {
+		cs = append(cs, vk.Row[m], vk.Col[m], vk.Val[m])
+	}
+	return cs
+}
+
+// rationalTerm returns the term of the matrix m in f for the given
+// evaluations of row, col and val:
+//
+//	eta_M * Z_H(alpha) * Z_H(beta_1) * val / ((alpha - row) * (beta_1 - col))
+func (vk *MarlinVerifyingKey) rationalTerm(ch marlinChallenges, m int, row, col, val Element) Element {
+	curve := vk.SRS.Curve
+	num := curve.NewElement().Mul(vk.H.EvalVanishing(ch.alpha), vk.H.EvalVanishing(ch.beta1))
+	num = num.Mul(num, curve.NewElement().Mul(ch.eta[m], val))
+	den := curve.NewElement().Mul(curve.NewElement().Sub(ch.alpha, row), curve.NewElement().Sub(ch.beta1, col))
+	return num.Div(num, den)
+}
+
+// rationalPolys returns the numerator a(x) and the denominator b(x) of f(x)
+// on K, with the same denominator for the three matrices:
+//
+//	b(x) = PROD (alpha - row_M(x)) * (beta_1 - col_M(x))
+//	a(x) = SUM eta_M * Z_H(alpha) * Z_H(beta_1) * val_M(x) * PROD_(N != M) ...
+func (pk *MarlinProvingKey) rationalPolys(ch marlinChallenges) (Poly, Poly) {
+	curve := pk.SRS.Curve
+	var dens [3]Poly
+	for m := range dens {
+		ar := Poly([]Element{ch.alpha}).Sub(pk.row[m])
+		bc := Poly([]Element{ch.beta1}).Sub(pk.col[m])
+		dens[m] = ar.Mul(bc)
+	}
+	zz := curve.NewElement().Mul(pk.H.EvalVanishing(ch.alpha), pk.H.EvalVanishing(ch.beta1))
+	a := Poly([]Element{curve.NewElement()})
+	for m := range dens {
+		term := pk.val[m].Scale(curve.NewElement().Mul(zz, ch.eta[m]))
+		for o := range dens {
+			if o != m {
+				term = term.Mul(dens[o])
+			}
+		}
+		a = a.Add(term)
+	}
+	return a, dens[0].Mul(dens[1]).Mul(dens[2])
+}
+
+// rationalEvals returns a(beta_2) and b(beta_2) from the evaluations of the
+// index polynomials
+func (vk *MarlinVerifyingKey) rationalEvals(ch marlinChallenges, row, col, val [3]Element) (Element, Element) {
+	curve := vk.SRS.Curve
+	var dens [3]Element
+	for m := range dens {
+		dens[m] = curve.NewElement().Mul(curve.NewElement().Sub(ch.alpha, row[m]), curve.NewElement().Sub(ch.beta1, col[m]))
+	}
+	zz := curve.NewElement().Mul(vk.H.EvalVanishing(ch.alpha), vk.H.EvalVanishing(ch.beta1))
+	a := curve.NewElement()
+	for m := range dens {
+		term := curve.NewElement().Mul(zz, curve.NewElement().Mul(ch.eta[m], val[m]))
+		for o := range dens {
+			if o != m {
+				term = term.Mul(term, dens[o])
+			}
+		}
+		a = a.Add(a, term)
+	}
+	b := curve.NewElement().Mul(dens[0], curve.NewElement().Mul(dens[1], dens[2]))
+	return a, b
+}
+
+func appendMarlinEvals(tr *transcript.Transcript, p *MarlinProof) {
+	for _, e := range []Element{p.WEval, p.ZAEval, p.ZBEval, p.ZCEval, p.H0Eval, p.SEval, p.GHEval, p.GHShiftEval, p.HHEval} {
+		tr.AppendScalar("beta1", e)
+	}
+	for _, e := range []Element{p.GKEval, p.GKShiftEval, p.HKEval} {
+		tr.AppendScalar("beta2", e)
+	}
+	for m := range p.RowEvals {
+		tr.AppendScalar("beta2", p.RowEvals[m])
+		tr.AppendScalar("beta2", p.ColEvals[m])
+		tr.AppendScalar("beta2", p.ValEvals[m])
+	}
+}
+
+// marlinSum returns SUM p(k) over the domain, i.e. n times the sum of the
+// coefficients of degree 0 mod n
+func marlinSum(d Domain, p Poly) Element {
+	curve := d.Curve
+	acc := curve.NewElement()
+	for i := 0; i < len(p); i += d.Size {
+		acc = acc.Add(acc, p[i])
+	}
+	return acc.Mul(acc, curve.Element(Value(d.Size)))
+}
+
+// marlinSumcheck returns h and g such that q(x) = h(x) * Z(x) + x * g(x) + c
+// for the constant c, deg(g) < n - 1 and Z the vanishing polynomial of the
+// domain. The constant is sigma / n if q sums to sigma over the domain.
+func marlinSumcheck(d Domain, q Poly) (Poly, Poly) {
+	curve := d.Curve
+	h, r := d.DivideByVanishing(q)
+	if len(h) == 0 {
+		h = Poly([]Element{curve.NewElement()})
+	}
+	return h, plonkCoeffs(curve, r, 1, d.Size)
+}
+
+// marlinSumcheckEval returns h(x) * Z(x) + x * g(x) + sigma / n
+func marlinSumcheckEval(d Domain, x, h, g, sigma Element) Element {
+	curve := d.Curve
+	res := curve.NewElement().Mul(h, d.EvalVanishing(x))
+	res = res.Add(res, curve.NewElement().Mul(x, g))
+	return res.Add(res, curve.NewElement().Div(sigma, curve.Element(Value(d.Size))))
+}
+
+// marlinShift returns x^(D - bound) * p(x) which can only be committed with a
+// setup of degree D if p has a degree lower or equal to bound
+func marlinShift(c Curve, p Poly, bound, D int) Poly {
+	return plonkMonomial(c, c.Element(1), D-bound).Mul(p)
+}
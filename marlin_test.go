@@ -0,0 +1,114 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarlinIndex(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			pk := MarlinIndex(NewMarlinSetupWith(curve, 8, 8), r1cs)
+			// 6 variables and 4 constraints
+			require.Equal(t, 8, pk.H.Size)
+			require.Equal(t, 3, pk.NbPublic)
+			// the left matrix has 6 non zero entries
+			require.Equal(t, 8, pk.K.Size)
+
+			// the index encodes the entries: row(k) = w^i, col(k) = w^j and
+			// val(k) * n / col(k) = M[i][j]
+			entries := marlinEntries(r1cs.left)
+			require.Len(t, entries, 6)
+			n := curve.NewElement().SetInt64(int64(pk.H.Size))
+			for k, e := range entries {
+				x := pk.K.Elements[k]
+				require.True(t, pk.row[0].Eval(x).Equal(pk.H.Elements[e[0]]))
+				col := pk.col[0].Eval(x)
+				require.True(t, col.Equal(pk.H.Elements[e[1]]))
+				v := curve.NewElement().Mul(pk.val[0].Eval(x), n)
+				require.True(t, v.Div(v, col).Equal(curve.Element(r1cs.left[e[0]][e[1]])))
+			}
+			require.True(t, KZGCommit(pk.SRS, pk.val[2]).Equal(pk.Val[2]))
+
+			// setup too small
+			require.Panics(t, func() { MarlinIndex(NewKZGSetupWith(curve, 8, 1), r1cs) })
+		})
+	}
+}
+
+func TestMarlinSumcheck(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			d := NewDomainWith(curve, 8)
+			q := randomPolyWith(curve, 20)
+			sum := curve.NewElement()
+			for _, x := range d.Elements {
+				sum = sum.Add(sum, q.Eval(x))
+			}
+			require.True(t, marlinSum(d, q).Equal(sum))
+			h, g := marlinSumcheck(d, q)
+			require.Len(t, g, d.Size-1)
+			x := randomElement(curve)
+			require.True(t, q.Eval(x).Equal(marlinSumcheckEval(d, x, h.Eval(x), g.Eval(x), sum)))
+		})
+	}
+}
+
+func TestMarlinProof(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			s := createWitness(r1cs)
+			pk := MarlinIndex(NewMarlinSetupWith(curve, 8, 8), r1cs)
+			proof := MarlinProve(pk, s)
+			require.True(t, MarlinVerify(pk.MarlinVerifyingKey, proof, s[:pk.NbPublic]))
+
+			// wrong public output
+			require.False(t, MarlinVerify(pk.MarlinVerifyingKey, proof, Vector{1, 3, 36}))
+			// missing public output
+			require.False(t, MarlinVerify(pk.MarlinVerifyingKey, proof, Vector{1, 3}))
+			// tampered sum and evaluation
+			tampered := proof
+			tampered.Sigma2 = curve.NewElement().Add(proof.Sigma2, curve.Element(1))
+			require.False(t, MarlinVerify(pk.MarlinVerifyingKey, tampered, s[:pk.NbPublic]))
+			tampered = proof
+			tampered.WEval = curve.NewElement().Add(proof.WEval, curve.Element(1))
+			require.False(t, MarlinVerify(pk.MarlinVerifyingKey, tampered, s[:pk.NbPublic]))
+
+			// invalid witness
+			s[r1cs.vars.IndexOf("u")] = 10
+			proof = MarlinProve(pk, s)
+			require.False(t, MarlinVerify(pk.MarlinVerifyingKey, proof, s[:pk.NbPublic]))
+		})
+	}
+}
+
+func TestMarlinSameCircuitAsGroth16(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			// the same R1CS and solution are proven with both systems, and a single
+			// setup serves several circuits
+			srs := NewMarlinSetupWith(curve, 16, 16)
+			for _, r1cs := range []R1CS{createR1CS(), createLinearR1CS()} {
+				var s Vector
+				if len(r1cs.inputs) == 1 {
+					s = createWitness(r1cs)
+				} else {
+					s = make(Vector, len(r1cs.vars))
+					for name, v := range map[string]Value{"const": 1, "x": 2, "y": 3, "a": 22, "out": 28, "b": 112} {
+						s[r1cs.vars.IndexOf(name)] = v
+					}
+				}
+				pk := MarlinIndex(srs, r1cs)
+				require.True(t, MarlinVerify(pk.MarlinVerifyingKey, MarlinProve(pk, s), s[:pk.NbPublic]))
+
+				qap := ToQAPWith(curve, r1cs)
+				diff := qap.nbIO
+				tr := NewGroth16TrustedSetup(qap)
+				require.True(t, Groth16Verify(tr, qap, Groth16Prove(tr, qap, s), s[:diff]))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Package merkle implements Merkle trees hashed with Poseidon, natively and as
+// a gadget of the frontend package, to prove in a circuit that a leaf belongs
+// to a tree with a public root without revealing its position.
+//
+// The tree is complete: the leaves are padded with zeros up to a power of
+// two. Each node is the hash of its two children, poseidon.Hash(left, right),
+// and the path of a leaf contains the siblings of its ancestors from the leaf
+// up to the root, with the direction bits given by the index of the leaf: the
+// bit i is 1 if the node at height i is a right child.
+package merkle
+
+import (
+	"errors"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+	"github.com/nikkolasg/playsnark/gadget"
+	"github.com/nikkolasg/playsnark/poseidon"
+)
+
+type Element = playsnark.Element
+
+// Tree is a Merkle tree whose levels are stored from the leaves to the root
+type Tree struct {
+	levels [][]Element
+}
+
+// Path is the path from a leaf to the root
+type Path struct {
+	// Siblings are the siblings of the nodes from the leaf to the root
+	Siblings []Element
+	// Directions are the bits of the index of the leaf, least significant
+	// first: 1 if the node is a right child
+	Directions []bool
+}
+
+// NewTree returns the tree of the leaves, padded with zeros to the next power
+// of two
+func NewTree(leaves []Element) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, errors.New("tree without leaves")
+	}
+	size := 1
+	for size < len(leaves) {
+		size *= 2
+	}
+	level := make([]Element, size)
+	copy(level, leaves)
+	for i := len(leaves); i < size; i++ {
+		level[i] = leaves[0].Clone().Zero()
+	}
+	t := &Tree{levels: [][]Element{level}}
+	for len(level) > 1 {
+		parents := make([]Element, len(level)/2)
+		for i := range parents {
+			parents[i] = poseidon.Hash(level[2*i], level[2*i+1])
+		}
+		t.levels = append(t.levels, parents)
+		level = parents
+	}
+	return t, nil
+}
+
+// Root returns the root of the tree
+func (t *Tree) Root() Element {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Depth returns the number of levels above the leaves, which is the length
+// of the paths
+func (t *Tree) Depth() int {
+	return len(t.levels) - 1
+}
+
+// Path returns the path of the i-th leaf
+func (t *Tree) Path(i int) (Path, error) {
+	if i < 0 || i >= len(t.levels[0]) {
+		return Path{}, errors.New("leaf index out of range")
+	}
+	var p Path
+	for _, level := range t.levels[:t.Depth()] {
+		p.Siblings = append(p.Siblings, level[i^1])
+		p.Directions = append(p.Directions, i&1 == 1)
+		i /= 2
+	}
+	return p, nil
+}
+
+// ComputeRoot returns the root of the tree from the leaf and its path
+func ComputeRoot(leaf Element, p Path) Element {
+	node := leaf
+	for i, sibling := range p.Siblings {
+		if p.Directions[i] {
+			node = poseidon.Hash(sibling, node)
+		} else {
+			node = poseidon.Hash(node, sibling)
+		}
+	}
+	return node
+}
+
+// Verify returns true if the path leads from the leaf to the root
+func Verify(root, leaf Element, p Path) bool {
+	if len(p.Siblings) != len(p.Directions) {
+		return false
+	}
+	return ComputeRoot(leaf, p).Equal(root)
+}
+
+// ComputeRootGadget returns the root of the tree from the leaf and its path in
+// the circuit, whose depth is the number of siblings. The directions are
+// constrained to be boolean. It costs 242 constraints per level: 2 for the
+// swap of the node and its sibling and 240 for their hash, whose capacity is
+// a constant.
+func ComputeRootGadget(api frontend.API, leaf frontend.Variable, siblings, directions []frontend.Variable) frontend.Variable {
+	if len(siblings) != len(directions) {
+		panic("merkle: as many directions as siblings are required")
+	}
+	node := leaf
+	for i, sibling := range siblings {
+		left, right := gadget.Swap(api, directions[i], node, sibling)
+		node = poseidon.HashGadget(api, left, right)
+	}
+	return node
+}
+
+// VerifyGadget constrains the path to lead from the leaf to the root
+func VerifyGadget(api frontend.API, root, leaf frontend.Variable, siblings, directions []frontend.Variable) {
+	api.AssertIsEqual(ComputeRootGadget(api, leaf, siblings, directions), root)
+}
@@ -0,0 +1,140 @@
+package merkle
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+var curve = playsnark.BLS12381
+
+// membership proves the leaf is in the tree of public root
+type membership struct {
+	Root       frontend.Variable   `snark:"public"`
+	Leaf       frontend.Variable   `snark:"secret"`
+	Siblings   []frontend.Variable `snark:"secret"`
+	Directions []frontend.Variable `snark:"secret"`
+}
+
+func (c *membership) Define(api frontend.API) error {
+	VerifyGadget(api, c.Root, c.Leaf, c.Siblings, c.Directions)
+	return nil
+}
+
+// newMembership returns an empty circuit of the given depth
+func newMembership(depth int) *membership {
+	return &membership{
+		Siblings:   make([]frontend.Variable, depth),
+		Directions: make([]frontend.Variable, depth),
+	}
+}
+
+// assign returns the assignment of the circuit for the leaf and its path
+func assign(root, leaf Element, p Path) *membership {
+	c := newMembership(len(p.Siblings))
+	c.Root, c.Leaf = root, leaf
+	for i, s := range p.Siblings {
+		c.Siblings[i] = s
+		c.Directions[i] = 0
+		if p.Directions[i] {
+			c.Directions[i] = 1
+		}
+	}
+	return c
+}
+
+func leaves(n int) []Element {
+	ls := make([]Element, n)
+	for i := range ls {
+		ls[i] = curve.Element(playsnark.Value(100 + i))
+	}
+	return ls
+}
+
+func TestTree(t *testing.T) {
+	for _, n := range []int{1, 2, 5, 8} {
+		tree, err := NewTree(leaves(n))
+		require.NoError(t, err)
+		for i, leaf := range leaves(n) {
+			p, err := tree.Path(i)
+			require.NoError(t, err)
+			require.Len(t, p.Siblings, tree.Depth())
+			require.True(t, Verify(tree.Root(), leaf, p))
+			// another leaf or another position
+			require.False(t, Verify(tree.Root(), curve.Element(99), p))
+			if tree.Depth() > 0 {
+				p.Directions[0] = !p.Directions[0]
+				require.False(t, Verify(tree.Root(), leaf, p))
+			}
+		}
+		_, err = tree.Path(1 << tree.Depth())
+		require.Error(t, err)
+	}
+	// 5 leaves are padded to 8
+	tree, err := NewTree(leaves(5))
+	require.NoError(t, err)
+	require.Equal(t, 3, tree.Depth())
+	p, err := tree.Path(7)
+	require.NoError(t, err)
+	require.True(t, Verify(tree.Root(), curve.NewElement(), p))
+
+	_, err = NewTree(nil)
+	require.Error(t, err)
+}
+
+func TestVerifyGadget(t *testing.T) {
+	tree, err := NewTree(leaves(8))
+	require.NoError(t, err)
+	r1cs, err := frontend.Compile(curve, newMembership(tree.Depth()))
+	require.NoError(t, err)
+	require.Equal(t, 242*tree.Depth()+1, r1cs.NbConstraints())
+
+	root, leaf := tree.Root(), leaves(8)[5]
+	p, err := tree.Path(5)
+	require.NoError(t, err)
+	_, err = r1cs.Solve(assign(root, leaf, p))
+	require.NoError(t, err)
+	// the leaf at another position
+	other, err := tree.Path(4)
+	require.NoError(t, err)
+	_, err = r1cs.Solve(assign(root, leaf, other))
+	require.Error(t, err)
+	// a direction which isn't a bit
+	invalid := assign(root, leaf, p)
+	invalid.Directions[1] = 2
+	_, err = r1cs.Solve(invalid)
+	require.Error(t, err)
+}
+
+func TestGroth16Membership(t *testing.T) {
+	// a depth of 2 keeps the setup and the proof fast
+	tree, err := NewTree(leaves(4))
+	require.NoError(t, err)
+	r1cs, err := frontend.Compile(curve, newMembership(tree.Depth()))
+	require.NoError(t, err)
+	qap := r1cs.QAP()
+	setup := playsnark.NewGroth16TrustedSetup(qap)
+
+	leaf := leaves(4)[2]
+	p, err := tree.Path(2)
+	require.NoError(t, err)
+	sol, err := r1cs.Solve(assign(tree.Root(), leaf, p))
+	require.NoError(t, err)
+	proof := playsnark.Groth16ProveElements(setup, qap, sol)
+
+	// the verifier only knows the root
+	public := newMembership(tree.Depth())
+	public.Root = tree.Root()
+	values, err := r1cs.PublicValues(public)
+	require.NoError(t, err)
+	vk := setup.VerifyingKey()
+	require.True(t, vk.VerifyElements(proof, values))
+	other, err := NewTree(leaves(3))
+	require.NoError(t, err)
+	public.Root = other.Root()
+	values, err = r1cs.PublicValues(public)
+	require.NoError(t, err)
+	require.False(t, vk.VerifyElements(proof, values))
+}
@@ -0,0 +1,108 @@
+// Package mimc implements the MiMC block cipher and hash function over the
+// scalar field of BLS12-381, natively and as a gadget of the frontend package
+// with the same output. MiMC is cheap in a circuit since it is made of field
+// operations only: each round computes
+//
+//	x = (x + k + c_i)^5
+//
+// which takes 3 constraints, and x^5 is a permutation of the field since 5 is
+// coprime with r - 1. The round constants c_i are derived from SHA-256, so
+// the hashes are specific to this package.
+package mimc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+)
+
+type Element = playsnark.Element
+
+// Rounds is the number of rounds, log_5(r) rounded up such that the degree of
+// the cipher covers the field
+const Rounds = 110
+
+// curve is the curve whose scalar field MiMC is defined over
+var curve = playsnark.BLS12381
+
+// constants are the round constants, the first one being zero
+var constants = roundConstants()
+
+func roundConstants() []Element {
+	cs := make([]Element, Rounds)
+	cs[0] = curve.NewElement()
+	for i := 1; i < Rounds; i++ {
+		cs[i] = curve.ElementFromBig(seed("mimc", i))
+	}
+	return cs
+}
+
+// seed returns the integer SHA-256(label || i)
+func seed(label string, i int) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(label))
+	binary.Write(h, binary.BigEndian, uint32(i))
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// Encrypt returns the encryption of x with the key k
+func Encrypt(k, x Element) Element {
+	x = x.Clone()
+	for _, c := range constants {
+		x = x.Add(x, k).Add(x, c)
+		x = pow5(x)
+	}
+	return x.Add(x, k)
+}
+
+func pow5(x Element) Element {
+	x2 := curve.NewElement().Mul(x, x)
+	x4 := x2.Mul(x2, x2)
+	return x4.Mul(x4, x)
+}
+
+// Hash returns the hash of the elements with the Miyaguchi-Preneel
+// construction, starting from h = 0:
+//
+//	h = Encrypt(h, m) + h + m
+//
+// for each element m.
+func Hash(data ...Element) Element {
+	h := curve.NewElement()
+	for _, m := range data {
+		e := Encrypt(h, m)
+		h = e.Add(e, h).Add(e, m)
+	}
+	return h
+}
+
+// EncryptGadget returns the encryption of x with the key k in the circuit. It
+// costs 3 constraints per round.
+func EncryptGadget(api frontend.API, k, x frontend.Variable) frontend.Variable {
+	checkCurve(api)
+	for _, c := range constants {
+		t := api.Add(x, k, c)
+		t2 := api.Mul(t, t)
+		x = api.Mul(t2, t2, t)
+	}
+	return api.Add(x, k)
+}
+
+// HashGadget returns the hash of the variables in the circuit, equal to the
+// Hash of their values. It costs 3 * Rounds constraints per variable.
+func HashGadget(api frontend.API, data ...frontend.Variable) frontend.Variable {
+	var h frontend.Variable = 0
+	for _, m := range data {
+		h = api.Add(EncryptGadget(api, h, m), h, m)
+	}
+	return h
+}
+
+func checkCurve(api frontend.API) {
+	if api.Curve().Name != curve.Name {
+		panic("mimc: the round constants are defined over the scalar field of " + curve.Name)
+	}
+}
@@ -0,0 +1,60 @@
+package mimc
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type hashCircuit struct {
+	Data []frontend.Variable `snark:"secret"`
+	Hash frontend.Variable   `snark:"public"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(HashGadget(api, c.Data...), c.Hash)
+	return nil
+}
+
+func elements(vs ...playsnark.Value) []Element {
+	return curve.Elements(playsnark.Vector(vs))
+}
+
+func TestHash(t *testing.T) {
+	h := Hash(elements(1, 2)...)
+	require.True(t, h.Equal(Hash(elements(1, 2)...)))
+	require.False(t, h.Equal(Hash(elements(2, 1)...)))
+	require.False(t, h.Equal(Hash(elements(1, 2, 0)...)))
+	require.False(t, Hash(elements(0)...).Equal(curve.NewElement()))
+
+	// different messages or keys give different ciphertexts
+	k, x := curve.Element(42), curve.Element(7)
+	require.False(t, Encrypt(k, x).Equal(Encrypt(k, curve.Element(8))))
+	require.False(t, Encrypt(k, x).Equal(Encrypt(curve.Element(43), x)))
+}
+
+func TestHashGadget(t *testing.T) {
+	for _, n := range []int{1, 2, 3} {
+		data := make([]frontend.Variable, n)
+		r1cs, err := frontend.Compile(curve, &hashCircuit{Data: data})
+		require.NoError(t, err)
+		// 3 constraints per round and the assertion
+		require.Equal(t, 3*Rounds*n+1, r1cs.NbConstraints())
+
+		values := make([]Element, n)
+		for i := range data {
+			values[i] = curve.NewElement().Pick(random.New())
+			data[i] = values[i]
+		}
+		_, err = r1cs.Solve(&hashCircuit{Data: data, Hash: Hash(values...)})
+		require.NoError(t, err)
+		_, err = r1cs.Solve(&hashCircuit{Data: data, Hash: Hash(values[1:]...)})
+		require.Error(t, err)
+	}
+	require.Panics(t, func() {
+		frontend.Compile(playsnark.BN254, &hashCircuit{Data: make([]frontend.Variable, 1)})
+	})
+}
@@ -0,0 +1,247 @@
+package playsnark
+
+import (
+	"fmt"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements the folding scheme of Nova https://eprint.iacr.org/2021/370.pdf
+// Nova folds two instances of the same R1CS into a single one, such that the
+// folded instance is satisfied only if both original ones are. Checking the
+// final instance proves all the folded ones at once, which is the basis of
+// incremental verifiable computation.
+//
+// Plain R1CS instances can't be folded: if z1 and z2 satisfy the R1CS, the
+// combination z = z1 + r * z2 doesn't since the constraints are quadratic. Nova
+// uses relaxed R1CS instead, where the solution z = (u, x, W) has a scalar u in
+// place of the constant 1 and which is satisfied if
+//
+//	Az o Bz = u * Cz + E
+//
+// for an error vector E. A plain solution is a relaxed one with u = 1 and
+// E = 0. Folding with z = z1 + r * z2 and u = u1 + r * u2 gives
+//
+//	Az o Bz = u * Cz + E1 + r * T + r^2 * E2
+//	T = Az1 o Bz2 + Az2 o Bz1 - u1 * Cz2 - u2 * Cz1
+//
+// so the folded error is E = E1 + r * T + r^2 * E2. The instance only
+// contains Pedersen commitments to W and E, which are homomorphic: the
+// verifier folds them without knowing the witness, using the commitment to the
+// cross term T sent by the prover. The challenge r is derived by hashing the
+// two instances and the commitment to T.
+
+// NovaSetup contains the Pedersen generators for the commitments to the
+// witness and to the error vector. There is no trapdoor: they are derived by
+// hashing. The instances are on the curve of the setup, the verifier takes it
+// from the instances it folds.
+type NovaSetup struct {
+	Curve Curve
+	GW    []G1
+	GE    []G1
+	H     G1
+}
+
+// NovaInstance is the public part of a relaxed R1CS instance
+type NovaInstance struct {
+	// CommE and CommW are the commitments to the error and the witness
+	CommE G1
+	CommW G1
+	U     Element
+	// X contains the inputs and outputs, without "const" which is replaced
+	// by U
+	X []Element
+}
+
+// NovaWitness is the private part of a relaxed R1CS instance: the
+// intermediate variables, the error vector and the blinding factors of their
+// commitments
+type NovaWitness struct {
+	W  []Element
+	E  []Element
+	RW Element
+	RE Element
+}
+
+// NovaProof is the proof of a folding: the commitment to the cross term
+type NovaProof struct {
+	CommT G1
+}
+
+// NewNovaSetup returns the generators for the given R1CS on the default curve
+func NewNovaSetup(r R1CS) NovaSetup {
+	return NewNovaSetupWith(BLS12381, r)
+}
+
+// NewNovaSetupWith returns the generators for the given R1CS on the given curve
+func NewNovaSetupWith(c Curve, r R1CS) NovaSetup {
+	s := NovaSetup{Curve: c}
+	for i := 0; i < len(r.vars)-r.nbIO(); i++ {
+		s.GW = append(s.GW, hashToG1(c, fmt.Sprintf("nova.W.%d", i)))
+	}
+	for i := range r.left {
+		s.GE = append(s.GE, hashToG1(c, fmt.Sprintf("nova.E.%d", i)))
+	}
+	s.H = hashToG1(c, "nova.H")
+	return s
+}
+
+// NewNovaInstance returns the relaxed instance corresponding to a solution of
+// the R1CS, ordered as for Groth16 and starting with "const": u = 1, E = 0
+// and the commitment to E is the neutral element.
+func NewNovaInstance(s NovaSetup, r R1CS, sol Vector) (NovaInstance, NovaWitness) {
+	var inst NovaInstance
+	var wit NovaWitness
+	nbIO := r.nbIO()
+	inst.X = s.Curve.Elements(sol[1:nbIO])
+	wit.W = s.Curve.Elements(sol[nbIO:])
+	for range r.left {
+		wit.E = append(wit.E, s.Curve.NewElement())
+	}
+	wit.RW = s.Curve.NewElement().Pick(random.New())
+	wit.RE = s.Curve.NewElement()
+	inst.U = s.Curve.NewElement().One()
+	inst.CommW = novaCommit(s.GW, s.H, wit.W, wit.RW)
+	inst.CommE = s.Curve.NewG1().Null()
+	return inst, wit
+}
+
+// NovaProve folds the two instances with their witnesses into a single one
+// and returns it along with the proof of the folding.
+func NovaProve(s NovaSetup, r R1CS, u1 NovaInstance, w1 NovaWitness, u2 NovaInstance, w2 NovaWitness) (NovaInstance, NovaWitness, NovaProof) {
+	curve := s.Curve
+	var proof NovaProof
+	az1, bz1, cz1 := novaProducts(r, u1, w1)
+	az2, bz2, cz2 := novaProducts(r, u2, w2)
+	t := make([]Element, len(r.left))
+	for i := range t {
+		t[i] = curve.NewElement().Mul(az1[i], bz2[i])
+		t[i] = t[i].Add(t[i], curve.NewElement().Mul(az2[i], bz1[i]))
+		t[i] = t[i].Sub(t[i], curve.NewElement().Mul(u1.U, cz2[i]))
+		t[i] = t[i].Sub(t[i], curve.NewElement().Mul(u2.U, cz1[i]))
+	}
+	rT := curve.NewElement().Pick(random.New())
+	proof.CommT = novaCommit(s.GE, s.H, t, rT)
+	c := novaChallenge(r, u1, u2, proof)
+
+	folded := novaFoldInstances(u1, u2, proof, c)
+	var wit NovaWitness
+	c2 := curve.NewElement().Mul(c, c)
+	for i := range w1.W {
+		wit.W = append(wit.W, novaLinear(w1.W[i], w2.W[i], c))
+	}
+	for i := range w1.E {
+		e := novaLinear(w1.E[i], t[i], c)
+		wit.E = append(wit.E, e.Add(e, curve.NewElement().Mul(c2, w2.E[i])))
+	}
+	wit.RW = novaLinear(w1.RW, w2.RW, c)
+	wit.RE = novaLinear(w1.RE, rT, c)
+	wit.RE = wit.RE.Add(wit.RE, curve.NewElement().Mul(c2, w2.RE))
+	return folded, wit, proof
+}
+
+// NovaVerify returns the instance obtained by folding the two instances with
+// the proof. The verifier doesn't learn anything about the witnesses, the
+// validity of all the instances folded so far is only checked at the end with
+// NovaIsSatisfied. It returns an error if the instances don't have as many
+// public values as the R1CS.
+func NovaVerify(r R1CS, u1, u2 NovaInstance, proof NovaProof) (NovaInstance, error) {
+	nbX := r.nbIO() - 1
+	if len(u1.X) != nbX || len(u2.X) != nbX {
+		return NovaInstance{}, fmt.Errorf("nova: instances with %d and %d public values instead of %d", len(u1.X), len(u2.X), nbX)
+	}
+	return novaFoldInstances(u1, u2, proof, novaChallenge(r, u1, u2, proof)), nil
+}
+
+// NovaIsSatisfied returns true if the witness satisfies the relaxed R1CS
+// instance, Az o Bz = u * Cz + E, and opens its commitments.
+func NovaIsSatisfied(s NovaSetup, r R1CS, u NovaInstance, w NovaWitness) bool {
+	if len(u.X) != r.nbIO()-1 || len(w.W) != len(s.GW) || len(w.E) != len(s.GE) {
+		return false
+	}
+	if !u.CommW.Equal(novaCommit(s.GW, s.H, w.W, w.RW)) {
+		return false
+	}
+	if !u.CommE.Equal(novaCommit(s.GE, s.H, w.E, w.RE)) {
+		return false
+	}
+	az, bz, cz := novaProducts(r, u, w)
+	for i := range az {
+		left := s.Curve.NewElement().Mul(az[i], bz[i])
+		right := s.Curve.NewElement().Mul(u.U, cz[i])
+		if !left.Equal(right.Add(right, w.E[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// novaFoldInstances returns U1 + c * U2 where the commitments to the errors
+// are folded as E1 + c * T + c^2 * E2. Both instances must have the same
+// number of public values, the result is on their curve.
+func novaFoldInstances(u1, u2 NovaInstance, proof NovaProof, c Element) NovaInstance {
+	var folded NovaInstance
+	c2 := c.Clone().Mul(c, c)
+	folded.CommE = u1.CommE.Clone().Add(u1.CommE, proof.CommT.Clone().Mul(c, proof.CommT))
+	folded.CommE = folded.CommE.Add(folded.CommE, u2.CommE.Clone().Mul(c2, u2.CommE))
+	folded.CommW = u1.CommW.Clone().Add(u1.CommW, u2.CommW.Clone().Mul(c, u2.CommW))
+	folded.U = novaLinear(u1.U, u2.U, c)
+	for i := range u1.X {
+		folded.X = append(folded.X, novaLinear(u1.X[i], u2.X[i], c))
+	}
+	return folded
+}
+
+// novaProducts returns Az, Bz and Cz for z = (u, x, W) in the field of u
+func novaProducts(r R1CS, u NovaInstance, w NovaWitness) ([]Element, []Element, []Element) {
+	f := fieldOf(u.U)
+	z := append([]Element{u.U}, u.X...)
+	z = append(z, w.W...)
+	var out [3][]Element
+	for m, matrix := range []Matrix{r.left, r.right, r.out} {
+		for _, row := range matrix {
+			acc := f.Scalar()
+			for j, v := range row {
+				if v != 0 {
+					acc = acc.Add(acc, f.Scalar().Mul(f.Scalar().SetInt64(int64(v)), z[j]))
+				}
+			}
+			out[m] = append(out[m], acc)
+		}
+	}
+	return out[0], out[1], out[2]
+}
+
+// novaChallenge derives the folding challenge from the R1CS, the two instances
+// and the commitment to the cross term
+func novaChallenge(r R1CS, u1, u2 NovaInstance, proof NovaProof) Element {
+	tr := transcript.New("nova")
+	r.appendTo(tr)
+	for _, u := range []NovaInstance{u1, u2} {
+		tr.AppendPoint("commE", u.CommE)
+		tr.AppendPoint("commW", u.CommW)
+		tr.AppendScalar("u", u.U)
+		tr.AppendUint64("nbX", uint64(len(u.X)))
+		for _, x := range u.X {
+			tr.AppendScalar("x", x)
+		}
+	}
+	tr.AppendPoint("commT", proof.CommT)
+	return tr.ChallengeScalar("r", fieldOf(u1.U))
+}
+
+// novaCommit returns SUM v_i * G_i + blinding * H
+func novaCommit(gs []G1, h G1, v []Element, blinding Element) G1 {
+	c := h.Clone().Mul(blinding, h)
+	for i := range v {
+		c = c.Add(c, h.Clone().Mul(v[i], gs[i]))
+	}
+	return c
+}
+
+// novaLinear returns a + c * b
+func novaLinear(a, b, c Element) Element {
+	res := c.Clone().Mul(c, b)
+	return res.Add(res, a)
+}
@@ -0,0 +1,109 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cubicSolution returns the solution of createR1CS for the input x
+func cubicSolution(r R1CS, x Value) Vector {
+	sol := make(Vector, len(r.vars))
+	sol[r.vars.IndexOf("const")] = 1
+	sol[r.vars.IndexOf("x")] = x
+	sol[r.vars.IndexOf("u")] = x * x
+	sol[r.vars.IndexOf("v")] = x * x * x
+	sol[r.vars.IndexOf("w")] = x*x*x + x
+	sol[r.vars.IndexOf("out")] = x*x*x + x + 5
+	return sol
+}
+
+func TestNovaInstance(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			s := NewNovaSetupWith(curve, r1cs)
+			require.Len(t, s.GW, 3)
+			require.Len(t, s.GE, 4)
+			u, w := NewNovaInstance(s, r1cs, createWitness(r1cs))
+			require.Len(t, u.X, 2)
+			require.True(t, u.X[1].Equal(curve.NewElement().SetInt64(35)))
+			require.True(t, NovaIsSatisfied(s, r1cs, u, w))
+
+			// u != 1 without any error doesn't satisfy the relaxed R1CS
+			u.U = curve.NewElement().SetInt64(2)
+			require.False(t, NovaIsSatisfied(s, r1cs, u, w))
+			u.U = curve.NewElement().One()
+			// the commitment must open to the witness
+			w.RW = curve.NewElement().Add(w.RW, curve.Element(1))
+			require.False(t, NovaIsSatisfied(s, r1cs, u, w))
+		})
+	}
+}
+
+func TestNovaFolding(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			s := NewNovaSetupWith(curve, r1cs)
+			// incrementally fold the instances for x = 3, 4, 5 into an accumulator
+			acc, accW := NewNovaInstance(s, r1cs, cubicSolution(r1cs, 3))
+			for _, x := range []Value{4, 5} {
+				u, w := NewNovaInstance(s, r1cs, cubicSolution(r1cs, x))
+				folded, foldedW, proof := NovaProve(s, r1cs, acc, accW, u, w)
+				verified, err := NovaVerify(r1cs, acc, u, proof)
+				require.NoError(t, err)
+				require.True(t, verified.CommE.Equal(folded.CommE))
+				require.True(t, verified.CommW.Equal(folded.CommW))
+				require.True(t, verified.U.Equal(folded.U))
+				require.True(t, NovaIsSatisfied(s, r1cs, verified, foldedW))
+				// the error term is not zero anymore
+				require.False(t, foldedW.E[0].Equal(curve.NewElement()))
+				acc, accW = verified, foldedW
+			}
+
+			// a wrong cross term changes the folded instance
+			u, w := NewNovaInstance(s, r1cs, cubicSolution(r1cs, 6))
+			_, foldedW, proof := NovaProve(s, r1cs, acc, accW, u, w)
+			proof.CommT = curve.NewG1().Add(proof.CommT, s.H)
+			verified, err := NovaVerify(r1cs, acc, u, proof)
+			require.NoError(t, err)
+			require.False(t, NovaIsSatisfied(s, r1cs, verified, foldedW))
+
+			// the instances must have as many public values as the R1CS
+			short := u
+			short.X = short.X[:len(short.X)-1]
+			_, err = NovaVerify(r1cs, acc, short, proof)
+			require.Error(t, err)
+			_, err = NovaVerify(r1cs, short, acc, proof)
+			require.Error(t, err)
+			long := u
+			long.X = append(append([]Element{}, u.X...), curve.NewElement())
+			_, err = NovaVerify(r1cs, acc, long, proof)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestNovaFoldingInvalid(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			s := NewNovaSetupWith(curve, r1cs)
+			u1, w1 := NewNovaInstance(s, r1cs, cubicSolution(r1cs, 3))
+			invalid := cubicSolution(r1cs, 4)
+			invalid[r1cs.vars.IndexOf("u")] = 15
+			u2, w2 := NewNovaInstance(s, r1cs, invalid)
+			require.False(t, NovaIsSatisfied(s, r1cs, u2, w2))
+			// folding an invalid instance gives an invalid instance, in any order
+			_, w, proof := NovaProve(s, r1cs, u1, w1, u2, w2)
+			verified, err := NovaVerify(r1cs, u1, u2, proof)
+			require.NoError(t, err)
+			require.False(t, NovaIsSatisfied(s, r1cs, verified, w))
+			_, w, proof = NovaProve(s, r1cs, u2, w2, u1, w1)
+			verified, err = NovaVerify(r1cs, u2, u1, proof)
+			require.NoError(t, err)
+			require.False(t, NovaIsSatisfied(s, r1cs, verified, w))
+		})
+	}
+}
@@ -68,8 +68,8 @@ type PHGR13VerifKey struct {
 	g1 G1
 	// g^a_v
 	av G2
-	// g^a_w
-	aw G2
+	// g^a_w, in G1 since it is paired with wss
+	aw G1
 	// g^a_y
 	ay G2
 	// g^gamma
@@ -91,38 +91,39 @@ type PHGR13VerifKey struct {
 
 // https://eprint.iacr.org/2013/279.pdf
 func NewPHGR13TrustedSetup(qap QAP) PHGR13Setup {
+	c := qap.curve
 	var ek PHGR13EvalKey
 	var vk PHGR13VerifKey
 	// s is the private point at which the prover evaluates its QAP polynomials
 	// s must thrown away after the trusted setup such that the prover doesn't
 	// it, it only evaluates its polynomials blindly to this point
-	s := NewElement().Pick(random.New())
+	s := c.NewElement().Pick(random.New())
 	// gsi contains g^(s^i) from i=0 to g^(si^#of gates) included
-	ek.gsi = GeneratePowersCommit(zeroG1, s, one.Clone(), qap.z.Degree()-2)
+	ek.gsi = GeneratePowersCommit(c.NewG1().Null(), s, c.NewElement().One(), qap.z.Degree()-2)
 	// alpha for the left right and outputs are for generating the linear
 	// combination in the exponent
-	av := NewElement().Pick(random.New())
-	aw := NewElement().Pick(random.New())
-	ay := NewElement().Pick(random.New())
+	av := c.NewElement().Pick(random.New())
+	aw := c.NewElement().Pick(random.New())
+	ay := c.NewElement().Pick(random.New())
 	// random element to form the basis of the commitments of al,al,a
 	// that then form the different basis of evaluation of the three polynomials
 	// g_v (in G1), g_w (in G2) and g_y (in G1)
-	rv := NewElement().Pick(random.New())
-	gv := NewG1().Mul(rv, nil)
-	rw := NewElement().Pick(random.New())
-	gw := NewG2().Mul(rw, nil)
-	g1w := NewG1().Mul(rw, nil)
+	rv := c.NewElement().Pick(random.New())
+	gv := c.NewG1().Mul(rv, nil)
+	rw := c.NewElement().Pick(random.New())
+	gw := c.NewG2().Mul(rw, nil)
+	g1w := c.NewG1().Mul(rw, nil)
 	// ry = rv * rw
-	ry := NewElement().Mul(rv, rw)
-	gy := NewG1().Mul(ry, nil)
-	g2y := NewG2().Mul(ry, nil)
+	ry := c.NewElement().Mul(rv, rw)
+	gy := c.NewG1().Mul(ry, nil)
+	g2y := c.NewG2().Mul(ry, nil)
 	// Compute the evaluation of the polynomials at the point s and their
 	// shifted version. This is to make sure that prover indeed used a
 	// the part of the CRS with a polynomial to build up its proof
-	diff := qap.nbVars - qap.nbIO
-	ek.vs = generateEvalCommit(gv, qap.left[diff:], s, one)
-	ek.ws = generateEvalCommit(gw, qap.right[diff:], s, one)
-	ek.ys = generateEvalCommit(gy, qap.out[diff:], s, one)
+	diff := qap.nbIO
+	ek.vs = generateEvalCommit(gv, qap.left[diff:], s, c.NewElement().One())
+	ek.ws = generateEvalCommit(gw, qap.right[diff:], s, c.NewElement().One())
+	ek.ys = generateEvalCommit(gy, qap.out[diff:], s, c.NewElement().One())
 	// compute the same evaluation but shifted by their respective alpha
 	ek.vas = generateEvalCommit(gv, qap.left[diff:], s, av)
 	ek.was = generateEvalCommit(g1w, qap.right[diff:], s, aw)
@@ -130,35 +131,35 @@ func NewPHGR13TrustedSetup(qap QAP) PHGR13Setup {
 
 	// Beta and gamma are used to check that same coefficients - same
 	// polynomials - were used during the linear combination
-	beta := NewElement().Pick(random.New())
+	beta := c.NewElement().Pick(random.New())
 	// we now evaluate the commitments of the polynomials shifted by beta
 	ek.vbs = generateEvalCommit(gv, qap.left[diff:], s, beta)
 	ek.wbs = generateEvalCommit(g1w, qap.right[diff:], s, beta)
 	ek.ybs = generateEvalCommit(gy, qap.out[diff:], s, beta)
 
-	gamma := NewElement().Pick(random.New())
-	bgamma := NewElement().Mul(gamma, beta)
+	gamma := c.NewElement().Pick(random.New())
+	bgamma := c.NewElement().Mul(gamma, beta)
 
-	vk.g1 = NewG1()
+	vk.g1 = c.NewG1()
 	// g^alpha_v
-	vk.av = NewG2().Mul(av, nil)
+	vk.av = c.NewG2().Mul(av, nil)
 	// g^alpha_w
-	vk.aw = NewG1().Mul(aw, nil)
+	vk.aw = c.NewG1().Mul(aw, nil)
 	// g^alpha_y
-	vk.ay = NewG2().Mul(ay, nil)
+	vk.ay = c.NewG2().Mul(ay, nil)
 	// g^gamma
-	vk.gamma = NewG2().Mul(gamma, nil)
+	vk.gamma = c.NewG2().Mul(gamma, nil)
 	// g^(beta*gamma)
-	vk.bgamma = NewG1().Mul(bgamma, nil)
-	vk.bgamma2 = NewG2().Mul(bgamma, nil)
+	vk.bgamma = c.NewG1().Mul(bgamma, nil)
+	vk.bgamma2 = c.NewG2().Mul(bgamma, nil)
 	// evaluation of the minimal polynomial at the unknonw index s
 	ts := qap.z.Eval(s)
 	// t(s) * (r_y * G2)
-	vk.yts = NewG2().Mul(ts, g2y)
+	vk.yts = c.NewG2().Mul(ts, g2y)
 	// g^(v_k(s)) for all k (input/output + intermediate)
-	vk.vs = generateEvalCommit(gv, qap.left, s, one)
-	vk.ws = generateEvalCommit(gw, qap.right, s, one)
-	vk.ys = generateEvalCommit(gy, qap.out, s, one)
+	vk.vs = generateEvalCommit(gv, qap.left, s, c.NewElement().One())
+	vk.ws = generateEvalCommit(gw, qap.right, s, c.NewElement().One())
+	vk.ys = generateEvalCommit(gy, qap.out, s, c.NewElement().One())
 	return PHGR13Setup{
 		EK: ek,
 		VK: vk,
@@ -205,6 +206,7 @@ type PHGR13Proof struct {
 // PHGR13Prove takes the evaluation key, the QAP polynomials and the solution
 // vector and returns the corresponding proof.
 func PHGR13Prove(ek PHGR13EvalKey, qap QAP, solution Vector) PHGR13Proof {
+	c := qap.curve
 	// compute h(x) then evaluate it blindly at point s
 	left, right, out := qap.computeAggregatePoly(solution)
 	// p(x) = t(x) * h(x)
@@ -215,31 +217,31 @@ func PHGR13Prove(ek PHGR13EvalKey, qap QAP, solution Vector) PHGR13Proof {
 		panic("apocalypse")
 	}
 	// g^h(s) = SUM(s_i * G)
-	ghs := hx.BlindEval(zeroG1, ek.gsi)
-	diff := qap.nbVars - qap.nbIO
+	ghs := hx.BlindEval(c.NewG1().Null(), ek.gsi)
+	diff := qap.nbIO
 	// compute g_v^(SUM v_k(s) * sol[k]) for k being NON IO
 	// same for y and w
 	computeSolCommit := func(zero Commit, evalCommit []Commit) Commit {
 		var tmp = zero.Clone()
 		var acc = zero.Clone()
 		for i, ec := range evalCommit {
-			acc = acc.Add(acc, tmp.Mul(solution[diff+i].ToFieldElement(), ec))
+			acc = acc.Add(acc, tmp.Mul(c.Element(solution[diff+i]), ec))
 		}
 		return acc
 	}
 	// g^(SUM sol[k] * v_k(s))
-	gvmids := computeSolCommit(zeroG1, ek.vs)
-	gwmids := computeSolCommit(zeroG2, ek.ws)
-	gymids := computeSolCommit(zeroG1, ek.ys)
-	gvamids := computeSolCommit(zeroG1, ek.vas)
-	gwamids := computeSolCommit(zeroG1, ek.was)
-	gyamids := computeSolCommit(zeroG1, ek.yas)
+	gvmids := computeSolCommit(c.NewG1().Null(), ek.vs)
+	gwmids := computeSolCommit(c.NewG2().Null(), ek.ws)
+	gymids := computeSolCommit(c.NewG1().Null(), ek.ys)
+	gvamids := computeSolCommit(c.NewG1().Null(), ek.vas)
+	gwamids := computeSolCommit(c.NewG1().Null(), ek.was)
+	gyamids := computeSolCommit(c.NewG1().Null(), ek.yas)
 
 	// g^(SUM sol[k] * v_k(s) * beta)
-	gvbmids := computeSolCommit(zeroG1, ek.vbs)
-	gwbmids := computeSolCommit(zeroG1, ek.wbs)
-	gybmids := computeSolCommit(zeroG1, ek.ybs)
-	gz := NewG1().Add(gvbmids, NewG1().Add(gwbmids, gybmids))
+	gvbmids := computeSolCommit(c.NewG1().Null(), ek.vbs)
+	gwbmids := computeSolCommit(c.NewG1().Null(), ek.wbs)
+	gybmids := computeSolCommit(c.NewG1().Null(), ek.ybs)
+	gz := c.NewG1().Add(gvbmids, c.NewG1().Add(gwbmids, gybmids))
 
 	return PHGR13Proof{
 		hs:   ghs,
@@ -279,6 +281,7 @@ func (p *PHGR13Proof) String() string {
 // the circuit, the proof generated by the prover and the inputs and outputs
 // expected
 func PHGR13Verify(vk PHGR13VerifKey, qap QAP, p PHGR13Proof, io Vector) bool {
+	c := qap.curve
 	// DIVISION CHECK: we look if the prover correctly evaluated the polynomials
 	// such that the QAP equation resolves:
 	// r_v * r_w * v(s) * w(s) == r_y * (p(s) +  y(s))
@@ -288,11 +291,11 @@ func PHGR13Verify(vk PHGR13VerifKey, qap QAP, p PHGR13Proof, io Vector) bool {
 
 	// g^v_io(s)^ck where ck are the "valid" coefficients since they're the
 	// inputs
-	diff := qap.nbVars - qap.nbIO
+	diff := qap.nbIO
 	{
-		gvkio := computeCommitIOSolution(zeroG1, vk.vs[:diff], io)
-		gwkio := computeCommitIOSolution(zeroG2, vk.ws[:diff], io)
-		gykio := computeCommitIOSolution(zeroG1, vk.ys[:diff], io)
+		gvkio := computeCommitIOSolution(c, c.NewG1().Null(), vk.vs[:diff], io)
+		gwkio := computeCommitIOSolution(c, c.NewG2().Null(), vk.ws[:diff], io)
+		gykio := computeCommitIOSolution(c, c.NewG1().Null(), vk.ys[:diff], io)
 
 		// first term is reconstructed above from the verification key and the
 		// input/output, second term is given by the prover (the intermediate
@@ -301,21 +304,21 @@ func PHGR13Verify(vk PHGR13VerifKey, qap QAP, p PHGR13Proof, io Vector) bool {
 		// gv = g^(r_v * SUM_all v_k(s) * c_k)
 		// where io represents the indices of the inputs / outputs variables
 		// and nio the rest
-		gv := NewG1().Add(gvkio, p.vss)
+		gv := c.NewG1().Add(gvkio, p.vss)
 		// g_w_io(s) * g_w_mid(s)
-		gw := NewG2().Add(gwkio, p.wss)
+		gw := c.NewG2().Add(gwkio, p.wss)
 		// g_y_io(s) * g_y_mid(s)
-		gy := NewG1().Add(gykio, p.yss)
+		gy := c.NewG1().Add(gykio, p.yss)
 
 		// e(g1^(r_v * v(s)),g2^(r_w * w(s)) =
 		// e(g1,g2)^(r_v * v(s) * r_w * w(s))
-		left := Pair(gv, gw)
+		left := c.Pair(gv, gw)
 		// e(g^t(s) , g^h(s)) = e(g1,g2)^(t(s) * h(s))
 		//					  = e(g1,g2)^p(s)
-		right1 := Pair(p.hs, vk.yts)
+		right1 := c.Pair(p.hs, vk.yts)
 		// e(g1^(r_y * y(s)), g2)
-		right2 := Pair(gy, zeroG2.Clone().Base())
-		right := zeroGT.Clone().Add(right1, right2)
+		right2 := c.Pair(gy, c.NewG2().Null().Clone().Base())
+		right := c.NewGT().Add(right1, right2)
 		if !left.Equal(right) {
 			return false
 		}
@@ -330,21 +333,21 @@ func PHGR13Verify(vk PHGR13VerifKey, qap QAP, p PHGR13Proof, io Vector) bool {
 		// following equation pass:
 		// e(g^a_v * v(s),g) = e(g^v(s),g^a_v)
 		// g^a_v * v(s), g^v(s) term is provided by prover, g^a_v by the CRS
-		left := Pair(p.vass, NewG2().Base())
-		right := Pair(p.vss, vk.av)
+		left := c.Pair(p.vass, c.NewG2().Base())
+		right := c.Pair(p.vss, vk.av)
 		if !left.Equal(right) {
 			return false
 		}
 		// we do the same for W except here we computed the evaluation of g^w(s) on
 		// G2 so we switch the argument order
-		left = Pair(p.wass, NewG2().Base())
-		right = Pair(vk.aw, p.wss)
+		left = c.Pair(p.wass, c.NewG2().Base())
+		right = c.Pair(vk.aw, p.wss)
 		if !left.Equal(right) {
 			return false
 		}
 		// we do the same for Y as in V
-		left = Pair(p.yass, NewG2().Base())
-		right = Pair(p.yss, vk.ay)
+		left = c.Pair(p.yass, c.NewG2().Base())
+		right = c.Pair(p.yss, vk.ay)
 		if !left.Equal(right) {
 			return false
 		}
@@ -355,17 +358,17 @@ func PHGR13Verify(vk PHGR13VerifKey, qap QAP, p PHGR13Proof, io Vector) bool {
 		// sum check with randomnized coefficient for each polynomial to avoid
 		// malleability.
 		// left is e(g^(r_v*v(s) + r_w*w(s) + r_y *y(s)) * beta,g^gamma)
-		left := Pair(p.gz, vk.gamma)
+		left := c.Pair(p.gz, vk.gamma)
 		// right is splitted into two GT elements as an optimization of
 		// https://eprint.iacr.org/2013/879.pdf because of polynomial w which is in
 		// g2
 		// t1 := e(g^(r_v*v(s)) * g^(r_y*y(s)), g^beta*gamma)
 		// t2 := e(g^beta*gamma, g^(r_w*w(s))
 		// right = t1*t2 = left !
-		lt1 := NewG1().Add(p.vss, p.yss)
-		t1 := Pair(lt1, vk.bgamma2)
-		t2 := Pair(vk.bgamma, p.wss)
-		right := zeroGT.Clone().Add(t1, t2)
+		lt1 := c.NewG1().Add(p.vss, p.yss)
+		t1 := c.Pair(lt1, vk.bgamma2)
+		t2 := c.Pair(vk.bgamma, p.wss)
+		right := c.NewGT().Add(t1, t2)
 		if !right.Equal(left) {
 			fmt.Println("damdam")
 			return false
@@ -387,7 +390,7 @@ func generateEvalCommit(base Commit, polys []Poly, x, shift Element) []Commit {
 	return ret
 }
 
-func computeCommitIOSolution(base Commit, poly []Commit, io Vector) Commit {
+func computeCommitIOSolution(c Curve, base Commit, poly []Commit, io Vector) Commit {
 	var acc = base.Clone().Null()
 	var tmp = base.Clone()
 	for i, gs := range poly {
@@ -400,7 +403,7 @@ func computeCommitIOSolution(base Commit, poly []Commit, io Vector) Commit {
 		// (g^v_k(s)) ^ c_k = g^(v_k(s) * c_k)
 		// We then compute g^SUM(v_k(s) * c_k) which is equal
 		// SUM [v_k(s) * c_k * G] = [SUM v_k(s) * c_k] * G
-		tmp := tmp.Mul(io[i].ToFieldElement(), gs)
+		tmp := tmp.Mul(c.Element(io[i]), gs)
 		acc = acc.Add(acc, tmp)
 	}
 	return acc
@@ -0,0 +1,783 @@
+package playsnark
+
+import (
+	"fmt"
+
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements PLONK https://eprint.iacr.org/2019/953.pdf
+// Contrary to Groth16 and Pinocchio, the setup is universal: it is a KZG
+// setup that only depends on the maximum size of the circuits. Each circuit is
+// then "preprocessed" by committing to the polynomials describing it, which
+// anybody can do and verify.
+//
+// A circuit is a list of n gates, each gate i having a left, right and output
+// wire with values a_i, b_i and c_i and the selectors such that
+//
+//	q_L_i * a_i + q_R_i * b_i + q_O_i * c_i + q_M_i * a_i * b_i + q_C_i = 0
+//
+// For example a multiplication gate has q_M = 1 and q_O = -1 and an addition
+// gate has q_L = q_R = 1 and q_O = -1. The gates alone don't say anything about
+// which wires hold the same variable: these are the "copy constraints" proven
+// with a permutation argument. All the wires are numbered and the permutation
+// sigma maps each wire to the next wire holding the same variable, in a cycle.
+// The values are correctly copied if and only if permuting the wires
+// according to sigma doesn't change the values.
+//
+// All vectors over the gates are interpolated as polynomials over a
+// multiplicative subgroup H = {1, w, ... w^(n-1)}, such that the i-th gate
+// corresponds to the point w^i. The gate equation then becomes a polynomial
+// equation that must vanish on H, i.e. be divisible by Z_H(x) = x^n - 1.
+
+// PlonkGate is one row of a PLONK circuit: the selectors and the indices of
+// the variables wired to the left (A), right (B) and output (C) wires.
+type PlonkGate struct {
+	QL Element
+	QR Element
+	QO Element
+	QM Element
+	QC Element
+	A  int
+	B  int
+	C  int
+	// Lookup marks a gate whose wires must be a tuple of the lookup table
+	Lookup bool
+}
+
+// PlonkCircuit describes a circuit with PLONK gates. As for the R1CS, the
+// variables are referenced by name and the inputs and outputs are public.
+type PlonkCircuit struct {
+	inputs        []string
+	outputs       []string
+	intermediates []string
+	// the concatenated variables [inputs..., outputs..., intermediates...] in
+	// order. Contrary to R1CS, there is no "const" variable since the gates
+	// have a constant selector.
+	vars  Variables
+	gates []PlonkGate
+	// table is the lookup table used by the lookup gates, if any
+	table PlookupTable
+	// curve whose scalar field contains the selectors and the values
+	curve Curve
+}
+
+// NewPlonkCircuit returns an empty circuit over the scalar field of the
+// default curve
+func NewPlonkCircuit() PlonkCircuit {
+	return NewPlonkCircuitWith(BLS12381)
+}
+
+// NewPlonkCircuitWith returns an empty circuit over the scalar field of the
+// given curve. It must be preprocessed with a setup on the same curve.
+func NewPlonkCircuitWith(c Curve) PlonkCircuit {
+	return PlonkCircuit{curve: c}
+}
+
+func (c *PlonkCircuit) NewInput(name string) {
+	c.inputs = append(c.inputs, name)
+	c.mergeVars()
+}
+
+func (c *PlonkCircuit) NewOutput(name string) {
+	c.outputs = append(c.outputs, name)
+	c.mergeVars()
+}
+
+func (c *PlonkCircuit) NewVar(name string) {
+	c.intermediates = append(c.intermediates, name)
+	c.mergeVars()
+}
+
+// mergeVars ensure that the variables are in order: first the inputs, then
+// the outputs and then the intermediate variables
+func (c *PlonkCircuit) mergeVars() {
+	var vars Variables
+	for _, n := range c.inputs {
+		vars = append(vars, newVar(len(vars), n))
+	}
+	for _, n := range c.outputs {
+		vars = append(vars, newVar(len(vars), n))
+	}
+	for _, n := range c.intermediates {
+		vars = append(vars, newVar(len(vars), n))
+	}
+	c.vars = vars
+}
+
+func (c *PlonkCircuit) nbPublic() int {
+	return len(c.inputs) + len(c.outputs)
+}
+
+// Gate adds a gate with the given selectors, wired to the given variables
+func (c *PlonkCircuit) Gate(ql, qr, qo, qm, qc Element, left, right, out string) {
+	c.gates = append(c.gates, PlonkGate{
+		QL: ql.Clone(),
+		QR: qr.Clone(),
+		QO: qo.Clone(),
+		QM: qm.Clone(),
+		QC: qc.Clone(),
+		A:  c.vars.IndexOf(left),
+		B:  c.vars.IndexOf(right),
+		C:  c.vars.IndexOf(out),
+	})
+}
+
+// Mul adds a gate such that left * right = out
+func (c *PlonkCircuit) Mul(left, right, out string) {
+	zero, one := c.curve.Element(0), c.curve.Element(1)
+	c.Gate(zero, zero, c.curve.Element(-1), one, zero, left, right, out)
+}
+
+// Add adds a gate such that var1 + var2 = out
+func (c *PlonkCircuit) Add(var1, var2, out string) {
+	zero, one := c.curve.Element(0), c.curve.Element(1)
+	c.Gate(one, one, c.curve.Element(-1), zero, zero, var1, var2, out)
+}
+
+// AddConst adds a gate such that var1 + add = out. The right wire is not used
+// so it is simply wired to var1 as well.
+func (c *PlonkCircuit) AddConst(var1 string, add int, out string) {
+	zero, one := c.curve.Element(0), c.curve.Element(1)
+	c.Gate(one, zero, c.curve.Element(-1), zero, c.curve.Element(Value(add)), var1, var1, out)
+}
+
+// SetTable sets the table of the lookup gates of the circuit
+func (c *PlonkCircuit) SetTable(table PlookupTable) {
+	c.table = table
+}
+
+// Lookup adds a gate such that the values of the variables form a tuple of the
+// lookup table, e.g. a range check with a single variable or (a, b, a XOR b).
+// The gate has no arithmetic selectors and the unused wires are wired to the
+// first variable.
+func (c *PlonkCircuit) Lookup(vars ...string) {
+	if c.table == nil || len(vars) != len(c.table) {
+		panic("lookup needs a table with one column per variable")
+	}
+	w := []string{vars[0], vars[0], vars[0]}
+	copy(w, vars)
+	zero := c.curve.Element(0)
+	c.Gate(zero, zero, zero, zero, zero, w[0], w[1], w[2])
+	c.gates[len(c.gates)-1].Lookup = true
+}
+
+// rows returns all the gates of the circuit, padded with empty gates up to
+// the size of the domain. The first gates are the public input gates: the
+// i-th one only has q_L = 1 and the i-th variable on its left wire, such that
+// adding the public input polynomial PI(x) = -SUM x_i * L_i(x) to the gate
+// equation forces the variable to be equal to x_i. With a lookup table, the
+// domain is big enough for the table and the last row is a padding gate since
+// the lookup argument can't select it.
+func (c *PlonkCircuit) rows() []PlonkGate {
+	var rows []PlonkGate
+	zero := c.curve.NewElement
+	for i := 0; i < c.nbPublic(); i++ {
+		rows = append(rows, PlonkGate{
+			QL: c.curve.Element(1), QR: zero(), QO: zero(), QM: zero(), QC: zero(),
+			A: i, B: i, C: i,
+		})
+	}
+	rows = append(rows, c.gates...)
+	n := nextPowerOfTwo(len(rows))
+	if c.table != nil {
+		n = nextPowerOfTwo(len(rows) + 1)
+		if t := nextPowerOfTwo(c.table.Len()); t > n {
+			n = t
+		}
+	}
+	for len(rows) < n {
+		rows = append(rows, PlonkGate{
+			QL: zero(), QR: zero(), QO: zero(), QM: zero(), QC: zero(),
+		})
+	}
+	return rows
+}
+
+// Selectors returns the selectors of all the gates, including the public input
+// gates and the padding gates.
+func (c *PlonkCircuit) Selectors() (ql, qr, qo, qm, qc []Element) {
+	for _, g := range c.rows() {
+		ql = append(ql, g.QL)
+		qr = append(qr, g.QR)
+		qo = append(qo, g.QO)
+		qm = append(qm, g.QM)
+		qc = append(qc, g.QC)
+	}
+	return
+}
+
+// Permutation returns the permutation sigma encoding the copy constraints. The
+// wire j (0 for left, 1 for right and 2 for output) of the gate i is numbered
+// j * n + i where n is the number of gates including the public input and
+// padding gates. All the wires holding the same variable form a cycle: each
+// one is mapped to the next one.
+func (c *PlonkCircuit) Permutation() []int {
+	rows := c.rows()
+	n := len(rows)
+	wiresOf := make(map[int][]int)
+	for i, g := range rows {
+		for j, v := range []int{g.A, g.B, g.C} {
+			wiresOf[v] = append(wiresOf[v], j*n+i)
+		}
+	}
+	perm := make([]int, 3*n)
+	for _, wires := range wiresOf {
+		for k, w := range wires {
+			perm[w] = wires[(k+1)%len(wires)]
+		}
+	}
+	return perm
+}
+
+// Wires returns the values of all the wires, numbered as in Permutation, given
+// the value of each variable.
+func (c *PlonkCircuit) Wires(sol Vector) []Element {
+	w := plonkWires(c.curve, c.rows(), sol)
+	return append(append(w[0], w[1]...), w[2]...)
+}
+
+// IsSatisfied returns true if the solution satisfies all the gates, with the
+// public input gates checking the public variables, all the copy constraints,
+// i.e. the wire values are invariant by the permutation, and all the lookups.
+func (c *PlonkCircuit) IsSatisfied(sol Vector) bool {
+	if len(sol) != len(c.vars) {
+		return false
+	}
+	rows := c.rows()
+	wires := c.Wires(sol)
+	n := len(rows)
+	for i, g := range rows {
+		a, b, o := wires[i], wires[n+i], wires[2*n+i]
+		res := c.curve.NewElement().Mul(g.QM, c.curve.NewElement().Mul(a, b))
+		res = res.Add(res, c.curve.NewElement().Mul(g.QL, a))
+		res = res.Add(res, c.curve.NewElement().Mul(g.QR, b))
+		res = res.Add(res, c.curve.NewElement().Mul(g.QO, o))
+		res = res.Add(res, g.QC)
+		if i < c.nbPublic() {
+			// PI(w^i) = -x_i
+			res = res.Sub(res, c.curve.Element(sol[i]))
+		}
+		if !res.Equal(c.curve.NewElement()) {
+			return false
+		}
+		if g.Lookup && !c.inTable([]Element{a, b, o}[:len(c.table)]) {
+			return false
+		}
+	}
+	for w, next := range c.Permutation() {
+		if !wires[w].Equal(wires[next]) {
+			return false
+		}
+	}
+	return true
+}
+
+// inTable returns true if the tuple is in the lookup table
+func (c *PlonkCircuit) inTable(tuple []Element) bool {
+	for i := 0; i < c.table.Len(); i++ {
+		found := true
+		for j := range tuple {
+			found = found && tuple[j].Equal(c.table[j][i])
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// plonkWires returns the values of the left, right and output wires of all the
+// gates
+func plonkWires(c Curve, rows []PlonkGate, sol Vector) [3][]Element {
+	var wires [3][]Element
+	for _, g := range rows {
+		wires[0] = append(wires[0], c.Element(sol[g.A]))
+		wires[1] = append(wires[1], c.Element(sol[g.B]))
+		wires[2] = append(wires[2], c.Element(sol[g.C]))
+	}
+	return wires
+}
+
+// PlonkVerifyingKey contains the universal setup and the commitments to the
+// polynomials describing the circuit.
+type PlonkVerifyingKey struct {
+	SRS      KZGSetup
+	Domain   Domain
+	NbPublic int
+	// K1 and K2 define the cosets H, K1 * H and K2 * H used to number
+	// respectively the left, right and output wires
+	K1 Element
+	K2 Element
+	// Commitments to the selector polynomials
+	QL G1
+	QR G1
+	QO G1
+	QM G1
+	QC G1
+	// Commitments to the permutation polynomials
+	S1 G1
+	S2 G1
+	S3 G1
+	// Lookup is the key of the lookup argument on the wires of the lookup
+	// gates, nil if the circuit has no table
+	Lookup *PlookupKey
+}
+
+// PlonkProvingKey contains the verifying key and all the polynomials
+// describing the circuit.
+type PlonkProvingKey struct {
+	PlonkVerifyingKey
+	rows []PlonkGate
+	ql   Poly
+	qr   Poly
+	qo   Poly
+	qm   Poly
+	qc   Poly
+	s1   Poly
+	s2   Poly
+	s3   Poly
+	// sigma[j][i] = S_j(w^i) is the identifier of the wire following the j-th
+	// wire of the i-th gate
+	sigma [3][]Element
+}
+
+// NewPlonkSetup returns a universal setup for all circuits of up to maxGates
+// gates, including one gate per public variable. The degree is the one of the
+// highest polynomial committed during the proof: one third of the quotient.
+// The setup is on the default curve.
+func NewPlonkSetup(maxGates int) KZGSetup {
+	return NewPlonkSetupWith(BLS12381, maxGates)
+}
+
+// NewPlonkSetupWith returns a universal setup as NewPlonkSetup on the given
+// curve.
+func NewPlonkSetupWith(c Curve, maxGates int) KZGSetup {
+	return NewKZGSetupWith(c, nextPowerOfTwo(maxGates)+5, 1)
+}
+
+// PlonkPreprocess interpolates and commits to the selectors and the
+// permutation of the circuit. The circuit must be on the curve of the setup.
+func PlonkPreprocess(srs KZGSetup, c PlonkCircuit) PlonkProvingKey {
+	if c.curve.Name != srs.Curve.Name {
+		panic(fmt.Sprintf("circuit on %s with a setup on %s", c.curve.Name, srs.Curve.Name))
+	}
+	rows := c.rows()
+	n := len(rows)
+	if srs.Degree() < n+5 {
+		panic(fmt.Sprintf("setup of degree %d too small for %d gates", srs.Degree(), n))
+	}
+	var pk PlonkProvingKey
+	pk.SRS = srs
+	pk.Domain = NewDomainWith(srs.Curve, n)
+	pk.NbPublic = c.nbPublic()
+	// g and g^2 for a generator g of the multiplicative group are in distinct
+	// cosets of H
+	pk.K1 = srs.Curve.Element(srs.Curve.Generator)
+	pk.K2 = srs.Curve.NewElement().Mul(pk.K1, pk.K1)
+	pk.rows = rows
+
+	ql, qr, qo, qm, qc := c.Selectors()
+	pk.ql = pk.Domain.Interpolate(ql)
+	pk.qr = pk.Domain.Interpolate(qr)
+	pk.qo = pk.Domain.Interpolate(qo)
+	pk.qm = pk.Domain.Interpolate(qm)
+	pk.qc = pk.Domain.Interpolate(qc)
+
+	// the wire j of gate i is identified by k_j * w^i
+	perm := c.Permutation()
+	for j := 0; j < 3; j++ {
+		pk.sigma[j] = make([]Element, n)
+		for i := 0; i < n; i++ {
+			pk.sigma[j][i] = pk.wireID(perm[j*n+i])
+		}
+	}
+	pk.s1 = pk.Domain.Interpolate(pk.sigma[0])
+	pk.s2 = pk.Domain.Interpolate(pk.sigma[1])
+	pk.s3 = pk.Domain.Interpolate(pk.sigma[2])
+
+	pk.QL = KZGCommit(srs, pk.ql)
+	pk.QR = KZGCommit(srs, pk.qr)
+	pk.QO = KZGCommit(srs, pk.qo)
+	pk.QM = KZGCommit(srs, pk.qm)
+	pk.QC = KZGCommit(srs, pk.qc)
+	pk.S1 = KZGCommit(srs, pk.s1)
+	pk.S2 = KZGCommit(srs, pk.s2)
+	pk.S3 = KZGCommit(srs, pk.s3)
+
+	if c.table != nil {
+		selector := make([]bool, n)
+		for i, g := range rows {
+			selector[i] = g.Lookup
+		}
+		lookup := newPlookupKey(srs, pk.Domain, c.table, selector)
+		pk.Lookup = &lookup
+	}
+	return pk
+}
+
+// cosets returns the shifts [1, K1, K2] of the three wire columns
+func (vk *PlonkVerifyingKey) cosets() [3]Element {
+	return [3]Element{vk.SRS.Curve.Element(1), vk.K1, vk.K2}
+}
+
+// wireID returns k_j * w^i for the wire numbered j * n + i
+func (vk *PlonkVerifyingKey) wireID(wire int) Element {
+	n := vk.Domain.Size
+	return vk.SRS.Curve.NewElement().Mul(vk.cosets()[wire/n], vk.Domain.Elements[wire%n])
+}
+
+// PlonkProof contains the commitments and evaluations sent by the prover
+type PlonkProof struct {
+	// Commitments to the wire polynomials a(x), b(x) and c(x)
+	A G1
+	B G1
+	C G1
+	// Commitment to the permutation accumulator z(x)
+	Z G1
+	// Commitments to the three parts of the quotient t(x)
+	TLo  G1
+	TMid G1
+	THi  G1
+	// Evaluations at the challenge zeta, and z at zeta * w
+	AEval      Element
+	BEval      Element
+	CEval      Element
+	S1Eval     Element
+	S2Eval     Element
+	ZOmegaEval Element
+	// KZG opening proofs at zeta and zeta * w
+	WZeta      G1
+	WZetaOmega G1
+	// Lookup is the lookup argument on the wires, when the circuit has a table
+	Lookup *PlookupProof
+}
+
+// wellFormed returns true if the proof contains all its commitments and
+// evaluations. The lookup argument is checked by its own verifier.
+func (p *PlonkProof) wellFormed() bool {
+	for _, c := range []G1{p.A, p.B, p.C, p.Z, p.TLo, p.TMid, p.THi, p.WZeta, p.WZetaOmega} {
+		if c == nil {
+			return false
+		}
+	}
+	for _, e := range []Element{p.AEval, p.BEval, p.CEval, p.S1Eval, p.S2Eval, p.ZOmegaEval} {
+		if e == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// plonkChallenges are the random challenges of the verifier derived from the
+// transcript
+type plonkChallenges struct {
+	beta  Element
+	gamma Element
+	alpha Element
+	zeta  Element
+}
+
+// PlonkProve returns a proof that the prover knows a solution for the
+// preprocessed circuit. The solution contains the value of each variable of
+// the circuit, in order. If the solution doesn't satisfy the circuit, the
+// quotient has a remainder that the prover ignores and the proof is invalid.
+func PlonkProve(pk PlonkProvingKey, sol Vector) PlonkProof {
+	var proof PlonkProof
+	var ch plonkChallenges
+	d := pk.Domain
+	n := d.Size
+	srs := pk.SRS
+	curve := srs.Curve
+	tr := pk.transcript(sol[:pk.NbPublic])
+
+	// Round 1: commit to the wire polynomials, blinded with a random multiple
+	// of Z_H so they don't reveal anything outside of H
+	//	a(x) = (b_1 + b_2 * x) * Z_H(x) + SUM a_i * L_i(x)
+	wires := plonkWires(curve, pk.rows, sol)
+	var wirePolys [3]Poly
+	for j := range wires {
+		blinding := Poly([]Element{randomElement(curve), randomElement(curve)}).Mul(d.Vanishing())
+		wirePolys[j] = d.Interpolate(wires[j]).Add(blinding)
+	}
+	a, b, c := wirePolys[0], wirePolys[1], wirePolys[2]
+	proof.A = KZGCommit(srs, a)
+	proof.B = KZGCommit(srs, b)
+	proof.C = KZGCommit(srs, c)
+	tr.AppendPoint("a", proof.A)
+	tr.AppendPoint("b", proof.B)
+	tr.AppendPoint("c", proof.C)
+	ch.beta = tr.ChallengeScalar("beta", curve.Suite.G1())
+	ch.gamma = tr.ChallengeScalar("gamma", curve.Suite.G1())
+
+	// Round 2: commit to the permutation accumulator. z(w^0) = 1 and
+	//	z(w^(i+1)) = z(w^i) * PROD_j (w_j_i + beta * k_j * w^i + gamma)
+	//				 / PROD_j (w_j_i + beta * S_j(w^i) + gamma)
+	// If the wires are invariant by the permutation, the products of the
+	// numerators and the denominators over the whole domain are the same, so z
+	// comes back to 1 after n steps.
+	cosets := pk.cosets()
+	zEvals := []Element{curve.Element(1)}
+	for i := 0; i < n-1; i++ {
+		num := curve.Element(1)
+		den := curve.Element(1)
+		for j := 0; j < 3; j++ {
+			id := curve.NewElement().Mul(cosets[j], d.Elements[i])
+			num = num.Mul(num, plonkPermTerm(wires[j][i], id, ch.beta, ch.gamma))
+			den = den.Mul(den, plonkPermTerm(wires[j][i], pk.sigma[j][i], ch.beta, ch.gamma))
+		}
+		zi := curve.NewElement().Mul(zEvals[i], num)
+		zEvals = append(zEvals, zi.Div(zi, den))
+	}
+	blinding := Poly([]Element{randomElement(curve), randomElement(curve), randomElement(curve)}).Mul(d.Vanishing())
+	z := d.Interpolate(zEvals).Add(blinding)
+	proof.Z = KZGCommit(srs, z)
+	tr.AppendPoint("z", proof.Z)
+	ch.alpha = tr.ChallengeScalar("alpha", curve.Suite.G1())
+
+	// Round 3: compute the quotient t(x) such that
+	//	t(x) * Z_H(x) = gate(x) + PI(x)
+	//		+ alpha * (a(x) + beta * x + gamma) * (b(x) + beta * k1 * x + gamma)
+	//			* (c(x) + beta * k2 * x + gamma) * z(x)
+	//		- alpha * (a(x) + beta * S1(x) + gamma) * (b(x) + beta * S2(x) + gamma)
+	//			* (c(x) + beta * S3(x) + gamma) * z(w * x)
+	//		+ alpha^2 * (z(x) - 1) * L_1(x)
+	// The last term forces z(w^0) = 1.
+	gate := a.Mul(b).Mul(pk.qm).
+		Add(a.Mul(pk.ql)).
+		Add(b.Mul(pk.qr)).
+		Add(c.Mul(pk.qo)).
+		Add(pk.qc).
+		Add(pk.publicPoly(sol[:pk.NbPublic]))
+	perm1 := z
+	for j, w := range wirePolys {
+		// w_j(x) + beta * k_j * x + gamma
+		perm1 = perm1.Mul(w.Add(Poly([]Element{ch.gamma, curve.NewElement().Mul(ch.beta, cosets[j])})))
+	}
+	perm2 := z.ScaleVar(d.Omega)
+	for j, s := range []Poly{pk.s1, pk.s2, pk.s3} {
+		// w_j(x) + beta * S_j(x) + gamma
+		perm2 = perm2.Mul(wirePolys[j].Add(s.Scale(ch.beta)).Add(Poly([]Element{ch.gamma})))
+	}
+	alpha2 := curve.NewElement().Mul(ch.alpha, ch.alpha)
+	first := z.Sub(Poly([]Element{curve.Element(1)})).Mul(d.Lagrange(0))
+	num := gate.Add(perm1.Sub(perm2).Scale(ch.alpha)).Add(first.Scale(alpha2))
+	t, _ := d.DivideByVanishing(num)
+	// t has degree 3n+5 so we split it in three parts of degree n such that
+	//	t(x) = t_lo(x) + x^n * t_mid(x) + x^2n * t_hi(x)
+	// with random blinding factors that cancel out
+	b10, b11 := randomElement(curve), randomElement(curve)
+	tLo := plonkCoeffs(curve, t, 0, n).Add(plonkMonomial(curve, b10, n))
+	tMid := plonkCoeffs(curve, t, n, 2*n).Sub(Poly([]Element{b10})).Add(plonkMonomial(curve, b11, n))
+	tHi := plonkCoeffs(curve, t, 2*n, 3*n+6).Sub(Poly([]Element{b11}))
+	proof.TLo = KZGCommit(srs, tLo)
+	proof.TMid = KZGCommit(srs, tMid)
+	proof.THi = KZGCommit(srs, tHi)
+	tr.AppendPoint("t_lo", proof.TLo)
+	tr.AppendPoint("t_mid", proof.TMid)
+	tr.AppendPoint("t_hi", proof.THi)
+	ch.zeta = tr.ChallengeScalar("zeta", curve.Suite.G1())
+
+	// Round 4: evaluate the polynomials the verifier can't compute itself at
+	// zeta
+	zetaOmega := curve.NewElement().Mul(ch.zeta, d.Omega)
+	proof.AEval = a.Eval(ch.zeta)
+	proof.BEval = b.Eval(ch.zeta)
+	proof.CEval = c.Eval(ch.zeta)
+	proof.S1Eval = pk.s1.Eval(ch.zeta)
+	proof.S2Eval = pk.s2.Eval(ch.zeta)
+	proof.ZOmegaEval = z.Eval(zetaOmega)
+	appendPlonkEvals(tr, &proof)
+
+	// Round 5: the linearization polynomial r(x) replaces the products of
+	// polynomials of the quotient equation by the evaluations at zeta, such
+	// that it is a linear combination of committed polynomials that evaluates
+	// to 0 at zeta.
+	coeffs := pk.linearization(ch, &proof, sol[:pk.NbPublic])
+	polys := []Poly{pk.qm, pk.ql, pk.qr, pk.qo, pk.qc, z, pk.s3, tLo, tMid, tHi, Poly([]Element{curve.Element(1)})}
+	r := Poly([]Element{curve.NewElement()})
+	for i, p := range polys {
+		r = r.Add(p.Scale(coeffs[i]))
+	}
+	// open r, a, b, c, S1 and S2 at zeta with a single proof and z at zeta * w
+	opened := []Poly{r, a, b, c, pk.s1, pk.s2}
+	commits := []G1{KZGCommit(srs, r), proof.A, proof.B, proof.C, pk.S1, pk.S2}
+	proof.WZeta = KZGOpenMulti(srs, tr, opened, commits, ch.zeta).W
+	proof.WZetaOmega = KZGOpen(srs, z, zetaOmega).W
+
+	// the lookup argument runs on the same transcript with the commitments to
+	// the wires used by the table. If a value is not in the table, the lookup
+	// proof is incomplete and invalid.
+	if pk.Lookup != nil {
+		k := len(pk.Lookup.table)
+		lookup, _ := plookupProve(pk.Lookup, tr, wirePolys[:k], wires[:k], []G1{proof.A, proof.B, proof.C}[:k])
+		proof.Lookup = &lookup
+	}
+	return proof
+}
+
+// PlonkVerify returns true if the proof is valid for the given public inputs
+// and outputs. The verifier recomputes the commitment to the linearization
+// polynomial from the commitments and checks the openings.
+func PlonkVerify(vk PlonkVerifyingKey, p PlonkProof, io Vector) bool {
+	if len(io) != vk.NbPublic || !p.wellFormed() {
+		return false
+	}
+	var ch plonkChallenges
+	curve := vk.SRS.Curve
+	tr := vk.transcript(io)
+	tr.AppendPoint("a", p.A)
+	tr.AppendPoint("b", p.B)
+	tr.AppendPoint("c", p.C)
+	ch.beta = tr.ChallengeScalar("beta", curve.Suite.G1())
+	ch.gamma = tr.ChallengeScalar("gamma", curve.Suite.G1())
+	tr.AppendPoint("z", p.Z)
+	ch.alpha = tr.ChallengeScalar("alpha", curve.Suite.G1())
+	tr.AppendPoint("t_lo", p.TLo)
+	tr.AppendPoint("t_mid", p.TMid)
+	tr.AppendPoint("t_hi", p.THi)
+	ch.zeta = tr.ChallengeScalar("zeta", curve.Suite.G1())
+	appendPlonkEvals(tr, &p)
+
+	coeffs := vk.linearization(ch, &p, io)
+	points := []G1{vk.QM, vk.QL, vk.QR, vk.QO, vk.QC, p.Z, vk.S3, p.TLo, p.TMid, p.THi, vk.SRS.G1Powers[0]}
+	r := curve.NewG1().Null()
+	for i, c := range points {
+		r = r.Add(r, curve.NewG1().Mul(coeffs[i], c))
+	}
+	// r(zeta) must be 0
+	multi := KZGMultiProof{
+		Z:  ch.zeta,
+		Ys: []Element{curve.NewElement(), p.AEval, p.BEval, p.CEval, p.S1Eval, p.S2Eval},
+		W:  p.WZeta,
+	}
+	if !KZGVerifyMulti(vk.SRS, tr, []G1{r, p.A, p.B, p.C, vk.S1, vk.S2}, multi) {
+		return false
+	}
+	zOmega := KZGProof{
+		Z: curve.NewElement().Mul(ch.zeta, vk.Domain.Omega),
+		Y: p.ZOmegaEval,
+		W: p.WZetaOmega,
+	}
+	if !KZGVerify(vk.SRS, p.Z, zOmega) {
+		return false
+	}
+	if vk.Lookup != nil {
+		k := len(vk.Lookup.table)
+		return p.Lookup != nil && plookupVerify(vk.Lookup, tr, []G1{p.A, p.B, p.C}[:k], *p.Lookup)
+	}
+	return true
+}
+
+// linearization returns the scalars of the linear combination giving r(x)
+// from the polynomials, in order, q_M, q_L, q_R, q_O, q_C, z, S3, t_lo, t_mid,
+// t_hi and the constant 1:
+//
+//	r(x) = a * b * q_M(x) + a * q_L(x) + b * q_R(x) + c * q_O(x) + q_C(x) + PI(zeta)
+//		+ alpha * (a + beta * zeta + gamma) * (b + beta * k1 * zeta + gamma)
+//			* (c + beta * k2 * zeta + gamma) * z(x)
+//		- alpha * (a + beta * s1 + gamma) * (b + beta * s2 + gamma)
+//			* (c + beta * S3(x) + gamma) * z_w
+//		+ alpha^2 * (z(x) - 1) * L_1(zeta)
+//		- Z_H(zeta) * (t_lo(x) + zeta^n * t_mid(x) + zeta^2n * t_hi(x))
+//
+// where a, b, c, s1, s2 and z_w are the evaluations sent by the prover.
+func (vk *PlonkVerifyingKey) linearization(ch plonkChallenges, p *PlonkProof, io Vector) []Element {
+	d := vk.Domain
+	curve := vk.SRS.Curve
+	cosets := vk.cosets()
+	alpha2 := curve.NewElement().Mul(ch.alpha, ch.alpha)
+	l1 := d.EvalLagrange(0, ch.zeta)
+	zh := d.EvalVanishing(ch.zeta)
+
+	ab := curve.NewElement().Mul(p.AEval, p.BEval)
+	// alpha * PROD (w_j + beta * k_j * zeta + gamma) + alpha^2 * L_1(zeta)
+	zc := ch.alpha.Clone()
+	for j, w := range []Element{p.AEval, p.BEval, p.CEval} {
+		id := curve.NewElement().Mul(cosets[j], ch.zeta)
+		zc = zc.Mul(zc, plonkPermTerm(w, id, ch.beta, ch.gamma))
+	}
+	zc = zc.Add(zc, curve.NewElement().Mul(alpha2, l1))
+	// alpha * (a + beta * s1 + gamma) * (b + beta * s2 + gamma) * z_w
+	sc := curve.NewElement().Mul(ch.alpha, p.ZOmegaEval)
+	sc = sc.Mul(sc, plonkPermTerm(p.AEval, p.S1Eval, ch.beta, ch.gamma))
+	sc = sc.Mul(sc, plonkPermTerm(p.BEval, p.S2Eval, ch.beta, ch.gamma))
+	// the coefficient of S3(x) is -beta * sc and the constant part is
+	// -(c + gamma) * sc
+	s3c := curve.NewElement().Neg(curve.NewElement().Mul(ch.beta, sc))
+	cst := curve.NewElement().Neg(curve.NewElement().Mul(curve.NewElement().Add(p.CEval, ch.gamma), sc))
+	// PI(zeta) - alpha^2 * L_1(zeta)
+	cst = cst.Add(cst, vk.publicEval(io, ch.zeta))
+	cst = cst.Sub(cst, curve.NewElement().Mul(alpha2, l1))
+	// -Z_H(zeta), -Z_H(zeta) * zeta^n and -Z_H(zeta) * zeta^2n
+	zetaN := curve.NewElement().Add(zh, curve.Element(1))
+	tLo := curve.NewElement().Neg(zh)
+	tMid := curve.NewElement().Mul(tLo, zetaN)
+	tHi := curve.NewElement().Mul(tMid, zetaN)
+	return []Element{ab, p.AEval, p.BEval, p.CEval, curve.Element(1), zc, s3c, tLo, tMid, tHi, cst}
+}
+
+// transcript returns the transcript bound to the circuit and its public inputs
+func (vk *PlonkVerifyingKey) transcript(io Vector) *transcript.Transcript {
+	tr := transcript.New("plonk")
+	tr.AppendUint64("n", uint64(vk.Domain.Size))
+	for _, c := range []G1{vk.QL, vk.QR, vk.QO, vk.QM, vk.QC, vk.S1, vk.S2, vk.S3} {
+		tr.AppendPoint("preprocessed", c)
+	}
+	for _, v := range io {
+		tr.AppendScalar("public", vk.SRS.Curve.Element(v))
+	}
+	return tr
+}
+
+// publicPoly returns PI(x) = -SUM x_i * L_i(x)
+func (vk *PlonkVerifyingKey) publicPoly(io Vector) Poly {
+	evals := make([]Element, len(io))
+	for i, v := range io {
+		evals[i] = vk.SRS.Curve.Element(-v)
+	}
+	return vk.Domain.Interpolate(evals)
+}
+
+// publicEval returns PI(zeta) = -SUM x_i * L_i(zeta)
+func (vk *PlonkVerifyingKey) publicEval(io Vector, zeta Element) Element {
+	curve := vk.SRS.Curve
+	acc := curve.NewElement()
+	for i, v := range io {
+		acc = acc.Sub(acc, curve.NewElement().Mul(curve.Element(v), vk.Domain.EvalLagrange(i, zeta)))
+	}
+	return acc
+}
+
+func appendPlonkEvals(tr *transcript.Transcript, p *PlonkProof) {
+	tr.AppendScalar("a_zeta", p.AEval)
+	tr.AppendScalar("b_zeta", p.BEval)
+	tr.AppendScalar("c_zeta", p.CEval)
+	tr.AppendScalar("s1_zeta", p.S1Eval)
+	tr.AppendScalar("s2_zeta", p.S2Eval)
+	tr.AppendScalar("z_omega_zeta", p.ZOmegaEval)
+}
+
+// plonkPermTerm returns w + beta * id + gamma
+func plonkPermTerm(w, id, beta, gamma Element) Element {
+	t := beta.Clone().Mul(beta, id)
+	t = t.Add(t, w)
+	return t.Add(t, gamma)
+}
+
+// plonkCoeffs returns the polynomial made of the coefficients of p between
+// from and to
+func plonkCoeffs(c Curve, p Poly, from, to int) Poly {
+	out := newPoly(c, to-from-1)
+	for i := from; i < to && i < len(p); i++ {
+		out[i-from] = p[i].Clone()
+	}
+	return out
+}
+
+// plonkMonomial returns e * x^d
+func plonkMonomial(c Curve, e Element, d int) Poly {
+	out := newPoly(c, d)
+	out[d] = e.Clone()
+	return out
+}
@@ -0,0 +1,183 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// createPlonkCircuit returns the PLONK circuit for the same equation as
+// createR1CS: x^3 + x + 5 = 35
+func createPlonkCircuit(curve Curve) PlonkCircuit {
+	c := NewPlonkCircuitWith(curve)
+	c.NewInput("x")
+	c.NewOutput("out")
+	c.NewVar("u")
+	c.NewVar("v")
+	c.NewVar("w")
+	// u = x * x
+	c.Mul("x", "x", "u")
+	// v = u * x
+	c.Mul("u", "x", "v")
+	// w = v + x
+	c.Add("v", "x", "w")
+	// out = w + 5
+	c.AddConst("w", 5, "out")
+	return c
+}
+
+// createPlonkWitness returns the same solution as createWitness, without the
+// "const" variable
+func createPlonkWitness(c PlonkCircuit) Vector {
+	var solution = make(Vector, len(c.vars))
+	solution[c.vars.IndexOf("x")] = 3
+	solution[c.vars.IndexOf("out")] = 35
+	solution[c.vars.IndexOf("u")] = 9
+	solution[c.vars.IndexOf("v")] = 27
+	solution[c.vars.IndexOf("w")] = 30
+	return solution
+}
+
+func TestPlonkPreprocess(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			c := createPlonkCircuit(curve)
+			pk := PlonkPreprocess(NewPlonkSetupWith(curve, 8), c)
+			// 2 public gates + 4 gates
+			require.Equal(t, 8, pk.Domain.Size)
+			require.Equal(t, 2, pk.NbPublic)
+
+			// sigma is a permutation of all the wire identifiers
+			seen := make(map[string]bool)
+			for j := 0; j < 3; j++ {
+				for i := 0; i < pk.Domain.Size; i++ {
+					seen[pk.sigma[j][i].String()] = true
+				}
+			}
+			require.Len(t, seen, 3*pk.Domain.Size)
+			for w := 0; w < 3*pk.Domain.Size; w++ {
+				require.True(t, seen[pk.wireID(w).String()])
+			}
+
+			// the selectors describe the gates: the first multiplication is the third
+			// row, after the public gates
+			mul := pk.Domain.Elements[2]
+			require.True(t, pk.qm.Eval(mul).Equal(curve.Element(1)))
+			require.True(t, pk.qo.Eval(mul).Equal(curve.NewElement().Neg(curve.Element(1))))
+			require.True(t, pk.ql.Eval(mul).Equal(curve.NewElement()))
+			require.True(t, KZGCommit(pk.SRS, pk.qm).Equal(pk.QM))
+
+			// setup too small
+			require.Panics(t, func() { PlonkPreprocess(NewPlonkSetupWith(curve, 4), c) })
+		})
+	} // circuit and setup on different curves
+	require.Panics(t, func() { PlonkPreprocess(NewPlonkSetupWith(BN254, 8), createPlonkCircuit(BLS12381)) })
+}
+
+func TestPlonkProof(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			srs := NewPlonkSetupWith(curve, 8)
+			c := createPlonkCircuit(curve)
+			s := createPlonkWitness(c)
+			pk := PlonkPreprocess(srs, c)
+			proof := PlonkProve(pk, s)
+			require.True(t, PlonkVerify(pk.PlonkVerifyingKey, proof, s[:pk.NbPublic]))
+
+			// wrong public output
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, proof, Vector{3, 36}))
+			// missing public output
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, proof, Vector{3}))
+			// tampered evaluation
+			tampered := proof
+			tampered.AEval = curve.NewElement().Add(proof.AEval, curve.Element(1))
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, tampered, s[:pk.NbPublic]))
+			// a proof missing any of its elements is rejected without panicking
+			missing := proof
+			for _, c := range []*G1{&missing.A, &missing.B, &missing.C, &missing.Z, &missing.TLo,
+				&missing.TMid, &missing.THi, &missing.WZeta, &missing.WZetaOmega} {
+				saved := *c
+				*c = nil
+				require.False(t, PlonkVerify(pk.PlonkVerifyingKey, missing, s[:pk.NbPublic]))
+				*c = saved
+			}
+			for _, e := range []*Element{&missing.AEval, &missing.BEval, &missing.CEval,
+				&missing.S1Eval, &missing.S2Eval, &missing.ZOmegaEval} {
+				saved := *e
+				*e = nil
+				require.False(t, PlonkVerify(pk.PlonkVerifyingKey, missing, s[:pk.NbPublic]))
+				*e = saved
+			}
+			require.True(t, PlonkVerify(pk.PlonkVerifyingKey, missing, s[:pk.NbPublic]))
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, PlonkProof{}, s[:pk.NbPublic]))
+		})
+	}
+}
+
+func TestPlonkInvalidWitness(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			srs := NewPlonkSetupWith(curve, 8)
+			c := createPlonkCircuit(curve)
+			pk := PlonkPreprocess(srs, c)
+
+			// a gate is not satisfied: u != x * x
+			s := createPlonkWitness(c)
+			s[c.vars.IndexOf("u")] = 10
+			proof := PlonkProve(pk, s)
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, proof, s[:pk.NbPublic]))
+
+			// all gates are satisfied but a copy constraint is not: the prover wires
+			// another variable y to the addition instead of u
+			c2 := NewPlonkCircuitWith(curve)
+			c2.NewInput("x")
+			c2.NewOutput("out")
+			c2.NewVar("u")
+			c2.NewVar("y")
+			// u = x * x
+			c2.Mul("x", "x", "u")
+			// out = u + 5
+			c2.AddConst("u", 5, "out")
+			pk2 := PlonkPreprocess(srs, c2)
+			s2 := Vector{3, 14, 9, 0}
+			require.True(t, PlonkVerify(pk2.PlonkVerifyingKey, PlonkProve(pk2, s2), s2[:2]))
+
+			cheat := pk2
+			cheat.rows = append([]PlonkGate{}, pk2.rows...)
+			y := c2.vars.IndexOf("y")
+			cheat.rows[3].A = y
+			cheat.rows[3].B = y
+			// 3 * 3 = 9 and 31 + 5 = 36
+			s2 = Vector{3, 36, 9, 31}
+			require.False(t, PlonkVerify(pk2.PlonkVerifyingKey, PlonkProve(cheat, s2), s2[:2]))
+		})
+	}
+}
+
+func TestPlonkUniversalSetup(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			// the same setup is used for two different circuits
+			srs := NewPlonkSetupWith(curve, 16)
+			c1 := createPlonkCircuit(curve)
+			pk1 := PlonkPreprocess(srs, c1)
+			s1 := createPlonkWitness(c1)
+
+			// x^2 = out
+			c2 := NewPlonkCircuitWith(curve)
+			c2.NewInput("x")
+			c2.NewOutput("out")
+			c2.Mul("x", "x", "out")
+			pk2 := PlonkPreprocess(srs, c2)
+			s2 := Vector{4, 16}
+			require.Equal(t, 4, pk2.Domain.Size)
+
+			p1 := PlonkProve(pk1, s1)
+			p2 := PlonkProve(pk2, s2)
+			require.True(t, PlonkVerify(pk1.PlonkVerifyingKey, p1, s1[:2]))
+			require.True(t, PlonkVerify(pk2.PlonkVerifyingKey, p2, s2))
+			// a proof for a circuit is not valid for the other
+			require.False(t, PlonkVerify(pk2.PlonkVerifyingKey, p1, s1[:2]))
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package playsnark
+
+import (
+	"fmt"
+)
+
+// ToPlonk converts a R1CS circuit into a PLONK circuit. Each row of the R1CS
+// states that <left,s> * <right,s> = <out,s> where each side is a linear
+// combination of the variables, while a PLONK gate only has three wires. Each
+// row is therefore converted as follows:
+//   - a linear combination of a single variable with coefficient one is
+//     directly wired to the gate.
+//   - any other linear combination is accumulated in new intermediate
+//     variables, two variables per gate then one more variable per gate:
+//     acc_1 = c_1 * x_1 + c_2 * x_2 + c_0 then acc_k = acc_(k-1) + c_k * x_k
+//     where c_0 is the coefficient of the "const" variable which becomes the
+//     constant selector.
+//   - the multiplication is then a single gate q_M * a * b + q_O * c + q_C = 0
+//     where a single variable output (with its constant) is folded in q_O and
+//     q_C.
+//
+// When one of the two sides is a constant, as for the additions of the R1CS,
+// the row is a linear equation k * <left,s> - <out,s> = 0 that needs no
+// multiplication: a single gate if it contains up to three variables.
+// The variables of the R1CS are kept with the same names, such that the copy
+// constraints of the PLONK circuit link all the gates using the same variable.
+// Use ToPlonkWitness to map a solution of the R1CS to a solution of the PLONK
+// circuit. The circuit is over the scalar field of the default curve.
+func ToPlonk(circuit R1CS) PlonkCircuit {
+	return ToPlonkWith(BLS12381, circuit)
+}
+
+// ToPlonkWith converts the R1CS as ToPlonk into a PLONK circuit over the
+// scalar field of the given curve.
+func ToPlonkWith(c Curve, circuit R1CS) PlonkCircuit {
+	p, _ := convertR1CS(c, circuit)
+	return p
+}
+
+// ToPlonkWitness maps a solution of the R1CS to a solution of the circuit
+// returned by ToPlonk: it drops the "const" variable and computes the values
+// of the intermediate variables introduced by the conversion.
+func ToPlonkWitness(circuit R1CS, sol Vector) Vector {
+	c, aux := convertR1CS(BLS12381, circuit)
+	out := make(Vector, len(c.vars))
+	for _, v := range circuit.vars[1:] {
+		out[c.vars.IndexOf(v.Name)] = sol[v.Index]
+	}
+	for _, a := range aux {
+		var acc Value
+		for i := range a.lc {
+			acc += a.lc[i] * sol[i]
+		}
+		out[c.vars.IndexOf(a.name)] = acc
+	}
+	return out
+}
+
+// plonkAux is an intermediate variable created during the conversion, whose
+// value is the linear combination lc of the R1CS variables
+type plonkAux struct {
+	name string
+	lc   Vector
+}
+
+// plonkConverter holds the state of the conversion of a R1CS
+type plonkConverter struct {
+	r1cs    R1CS
+	circuit PlonkCircuit
+	aux     []plonkAux
+}
+
+func convertR1CS(c Curve, circuit R1CS) (PlonkCircuit, []plonkAux) {
+	conv := &plonkConverter{r1cs: circuit, circuit: NewPlonkCircuitWith(c)}
+	for _, n := range circuit.inputs {
+		conv.circuit.NewInput(n)
+	}
+	for _, n := range circuit.outputs {
+		conv.circuit.NewOutput(n)
+	}
+	for _, n := range circuit.intermediates {
+		conv.circuit.NewVar(n)
+	}
+	for i := range circuit.left {
+		conv.convertRow(circuit.left[i], circuit.right[i], circuit.out[i])
+	}
+	return conv.circuit, conv.aux
+}
+
+// convertRow adds the gates for the R1CS row left * right = out
+func (p *plonkConverter) convertRow(left, right, out Vector) {
+	if k, ok := constantLC(right); ok {
+		p.assertZero(scaleLC(left, k).Sub(out))
+		return
+	}
+	if k, ok := constantLC(left); ok {
+		p.assertZero(scaleLC(right, k).Sub(out))
+		return
+	}
+	a := p.lcVar(left)
+	b := p.lcVar(right)
+	f := p.circuit.curve
+	// out is folded in the gate if it is at most one variable
+	qo, qc := f.NewElement(), f.NewElement()
+	o := a
+	if terms := termsLC(out); len(terms) > 1 {
+		o = p.lcVar(out)
+		qo = f.Element(-1)
+	} else {
+		qc = f.Element(-out[0])
+		if len(terms) == 1 {
+			o = p.name(terms[0])
+			qo = f.Element(-out[terms[0]])
+		}
+	}
+	p.circuit.Gate(f.NewElement(), f.NewElement(), qo, f.Element(1), qc, a, b, o)
+}
+
+// lcVar returns the name of a variable equal to the linear combination,
+// creating intermediate variables if needed
+func (p *plonkConverter) lcVar(lc Vector) string {
+	terms := termsLC(lc)
+	if len(terms) == 1 && lc[terms[0]] == 1 && lc[0] == 0 {
+		return p.name(terms[0])
+	}
+	f := p.circuit.curve
+	minusOne := f.Element(-1)
+	if len(terms) == 0 {
+		// acc = c_0
+		acc := p.newAux(lc)
+		p.linearGate([]Element{minusOne}, []string{acc}, f.Element(lc[0]))
+		return acc
+	}
+	// acc_1 = c_1 * x_1 + c_2 * x_2 + c_0
+	first := terms[:1]
+	if len(terms) > 1 {
+		first = terms[:2]
+	}
+	acc := p.newAux(prefixLC(lc, first[len(first)-1]))
+	var coeffs []Element
+	var vars []string
+	for _, t := range first {
+		coeffs = append(coeffs, f.Element(lc[t]))
+		vars = append(vars, p.name(t))
+	}
+	p.linearGate(append(coeffs, minusOne), append(vars, acc), f.Element(lc[0]))
+	// acc_k = acc_(k-1) + c_k * x_k
+	for _, t := range terms[len(first):] {
+		next := p.newAux(prefixLC(lc, t))
+		p.linearGate([]Element{f.Element(1), f.Element(lc[t]), minusOne}, []string{acc, p.name(t), next}, f.NewElement())
+		acc = next
+	}
+	return acc
+}
+
+// assertZero adds the gates such that the linear combination is zero. Up to
+// three variables fit in a single gate, otherwise all the terms but the last
+// two are first accumulated in an intermediate variable.
+func (p *plonkConverter) assertZero(lc Vector) {
+	terms := termsLC(lc)
+	if len(terms) == 0 {
+		if lc[0] != 0 {
+			panic("r1cs row is never satisfied")
+		}
+		return
+	}
+	var coeffs []Element
+	var vars []string
+	f := p.circuit.curve
+	cst := f.Element(lc[0])
+	last := terms
+	if len(terms) > 3 {
+		last = terms[len(terms)-2:]
+		coeffs = append(coeffs, f.Element(1))
+		vars = append(vars, p.lcVar(prefixLC(lc, terms[len(terms)-3])))
+		cst = f.NewElement()
+	}
+	for _, t := range last {
+		coeffs = append(coeffs, f.Element(lc[t]))
+		vars = append(vars, p.name(t))
+	}
+	p.linearGate(coeffs, vars, cst)
+}
+
+// linearGate adds the gate c_1 * x_1 + c_2 * x_2 + c_3 * x_3 + cst = 0 for up
+// to three variables, the unused wires are wired to the first variable
+func (p *plonkConverter) linearGate(coeffs []Element, vars []string, cst Element) {
+	zero := p.circuit.curve.NewElement()
+	q := []Element{zero, zero, zero}
+	w := []string{vars[0], vars[0], vars[0]}
+	for i := range vars {
+		q[i] = coeffs[i]
+		w[i] = vars[i]
+	}
+	p.circuit.Gate(q[0], q[1], q[2], zero, cst, w[0], w[1], w[2])
+}
+
+// name returns the name of the i-th variable of the R1CS
+func (p *plonkConverter) name(i int) string {
+	return p.r1cs.vars[i].Name
+}
+
+// newAux creates a new intermediate variable whose value is the given linear
+// combination
+func (p *plonkConverter) newAux(lc Vector) string {
+	name := fmt.Sprintf("_aux%d", len(p.aux))
+	p.circuit.NewVar(name)
+	p.aux = append(p.aux, plonkAux{name: name, lc: lc})
+	return name
+}
+
+// termsLC returns the indices of the variables with a non zero coefficient in
+// the linear combination, excluding the "const" variable
+func termsLC(lc Vector) []int {
+	var terms []int
+	for i := 1; i < len(lc); i++ {
+		if lc[i] != 0 {
+			terms = append(terms, i)
+		}
+	}
+	return terms
+}
+
+// constantLC returns the constant and true if the linear combination only
+// contains the "const" variable
+func constantLC(lc Vector) (Value, bool) {
+	return lc[0], len(termsLC(lc)) == 0
+}
+
+// prefixLC returns the linear combination with only the coefficients up to
+// the index last included
+func prefixLC(lc Vector, last int) Vector {
+	out := make(Vector, len(lc))
+	copy(out, lc[:last+1])
+	return out
+}
+
+func scaleLC(lc Vector, k Value) Vector {
+	out := make(Vector, len(lc))
+	for i := range lc {
+		out[i] = lc[i] * k
+	}
+	return out
+}
@@ -0,0 +1,105 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToPlonk(t *testing.T) {
+	r1cs := createR1CS()
+	s := createWitness(r1cs)
+	c := ToPlonk(r1cs)
+	w := ToPlonkWitness(r1cs, s)
+	// each row of the R1CS fits in a single gate: the two multiplications
+	// directly use the variables and the additions are linear gates
+	require.Len(t, c.gates, 4)
+	require.Len(t, w, len(s)-1)
+	require.True(t, c.IsSatisfied(w))
+
+	// 2 public gates and 4 gates, padded to 8
+	ql, _, _, qm, _ := c.Selectors()
+	require.Len(t, ql, 8)
+	require.True(t, qm[2].Equal(one))
+	require.Len(t, c.Permutation(), 3*8)
+	require.Len(t, c.Wires(w), 3*8)
+
+	// invalid witness
+	s[r1cs.vars.IndexOf("u")] = 10
+	require.False(t, c.IsSatisfied(ToPlonkWitness(r1cs, s)))
+}
+
+// createLinearR1CS returns a R1CS whose rows contain longer linear
+// combinations than the ones created by Mul and Add
+func createLinearR1CS() R1CS {
+	r := NewR1CS()
+	r.NewInput("x")
+	r.NewInput("y")
+	r.NewOutput("out")
+	r.NewVar("a")
+	r.NewVar("b")
+	lc := func(terms map[string]Value) Vector {
+		v := make(Vector, len(r.vars))
+		for name, c := range terms {
+			v[r.vars.IndexOf(name)] = c
+		}
+		return v
+	}
+	row := func(left, right, out map[string]Value) {
+		r.left = append(r.left, lc(left))
+		r.right = append(r.right, lc(right))
+		r.out = append(r.out, lc(out))
+	}
+	// (x + 2y + 3) * (y - 1) = a
+	row(map[string]Value{"x": 1, "y": 2, "const": 3}, map[string]Value{"y": 1, "const": -1}, map[string]Value{"a": 1})
+	// (x + y + a + 1) * 1 = out
+	row(map[string]Value{"x": 1, "y": 1, "a": 1, "const": 1}, map[string]Value{"const": 1}, map[string]Value{"out": 1})
+	// (x + y + a + out + 1) * 2 = b
+	row(map[string]Value{"x": 1, "y": 1, "a": 1, "out": 1, "const": 1}, map[string]Value{"const": 2}, map[string]Value{"b": 1})
+	// x * (y + 1) = a + b - 126
+	row(map[string]Value{"x": 1}, map[string]Value{"y": 1, "const": 1}, map[string]Value{"a": 1, "b": 1, "const": -126})
+	return r
+}
+
+func TestToPlonkLinearCombinations(t *testing.T) {
+	r1cs := createLinearR1CS()
+	s := make(Vector, len(r1cs.vars))
+	for name, v := range map[string]Value{"const": 1, "x": 2, "y": 3, "a": 22, "out": 28, "b": 112} {
+		s[r1cs.vars.IndexOf(name)] = v
+	}
+	// the solution satisfies the R1CS
+	res := r1cs.left.Mul(s).Hadamard(r1cs.right.Mul(s)).Sub(r1cs.out.Mul(s))
+	require.True(t, res.IsZero())
+
+	c := ToPlonk(r1cs)
+	w := ToPlonkWitness(r1cs, s)
+	require.True(t, c.IsSatisfied(w))
+	// intermediate variables: both sides of the first row, the first two
+	// terms of the second row (with out, it has four variables), the first
+	// three terms of the third row in two steps and both the right side and
+	// the output of the last row
+	require.Len(t, w, len(s)-1+7)
+
+	s[r1cs.vars.IndexOf("b")] = 113
+	require.False(t, c.IsSatisfied(ToPlonkWitness(r1cs, s)))
+}
+
+func TestToPlonkProof(t *testing.T) {
+	r1cs := createR1CS()
+	c := ToPlonk(r1cs)
+	w := ToPlonkWitness(r1cs, createWitness(r1cs))
+	pk := PlonkPreprocess(NewPlonkSetup(8), c)
+	proof := PlonkProve(pk, w)
+	require.True(t, PlonkVerify(pk.PlonkVerifyingKey, proof, w[:pk.NbPublic]))
+
+	r1cs = createLinearR1CS()
+	c = ToPlonk(r1cs)
+	s := make(Vector, len(r1cs.vars))
+	for name, v := range map[string]Value{"const": 1, "x": 2, "y": 3, "a": 22, "out": 28, "b": 112} {
+		s[r1cs.vars.IndexOf(name)] = v
+	}
+	w = ToPlonkWitness(r1cs, s)
+	pk = PlonkPreprocess(NewPlonkSetup(16), c)
+	proof = PlonkProve(pk, w)
+	require.True(t, PlonkVerify(pk.PlonkVerifyingKey, proof, w[:pk.NbPublic]))
+}
@@ -0,0 +1,551 @@
+package playsnark
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements the plookup argument https://eprint.iacr.org/2020/315.pdf
+// The prover shows that all the values f_1 ... f_(n-1) of a column are in a
+// public table t_1 ... t_n. A range check on 8 bits then costs a single lookup
+// in the table [0, 1, ..., 255] instead of 8 booleanity constraints, and any
+// function on small inputs can be "precomputed" in a table, as XOR.
+//
+// The idea is to sort the concatenation s = (f, t) in the order of the table,
+// i.e. each value of f is placed next to the same value in t. If all values of
+// f are in t, the set of pairs of consecutive elements {(s_i, s_(i+1))} is
+// the same as the set {(t_i, t_(i+1))} plus the pairs (f_i, f_i) created by
+// placing f_i next to itself. This is checked with a grand product over the
+// random challenges beta and gamma, as the PLONK permutation argument:
+//
+//	PROD (1 + beta) * (gamma + f_i) * (gamma(1 + beta) + t_i + beta * t_(i+1))
+//		== PROD (gamma(1 + beta) + s_i + beta * s_(i+1))
+//
+// s is too long to be interpolated on the domain so it is split in two
+// halves h1 and h2 that overlap by one element.
+//
+// The looked up values are not given directly: a selector q marks the rows of
+// the looked up wires w (one per column of the table) and the column is
+// f = q * w + (1 - q) * t_0, such that the argument can be plugged on the wire
+// polynomials of a PLONK circuit. Tables with several columns are compressed
+// into one with a random challenge theta: t = t_0 + theta * t_1 + ...
+
+// PlookupTable is a table of tuples stored column by column: the i-th tuple is
+// (table[0][i], table[1][i], ...).
+type PlookupTable [][]Element
+
+// NewRangeTable returns the table with the single column [0, 1, ... 2^bits-1]
+// over the scalar field of the default curve
+func NewRangeTable(bits int) PlookupTable {
+	return NewRangeTableWith(BLS12381, bits)
+}
+
+// NewRangeTableWith returns the range table over the scalar field of the given
+// curve
+func NewRangeTableWith(c Curve, bits int) PlookupTable {
+	var col []Element
+	for i := 0; i < 1<<uint(bits); i++ {
+		col = append(col, c.Element(Value(i)))
+	}
+	return PlookupTable{col}
+}
+
+// NewXORTable returns the table of all the tuples (a, b, a XOR b) for a and b
+// on the given number of bits, over the scalar field of the default curve
+func NewXORTable(bits int) PlookupTable {
+	return NewXORTableWith(BLS12381, bits)
+}
+
+// NewXORTableWith returns the XOR table over the scalar field of the given
+// curve
+func NewXORTableWith(c Curve, bits int) PlookupTable {
+	table := make(PlookupTable, 3)
+	for a := 0; a < 1<<uint(bits); a++ {
+		for b := 0; b < 1<<uint(bits); b++ {
+			table[0] = append(table[0], c.Element(Value(a)))
+			table[1] = append(table[1], c.Element(Value(b)))
+			table[2] = append(table[2], c.Element(Value(a^b)))
+		}
+	}
+	return table
+}
+
+// Len returns the number of tuples of the table
+func (t PlookupTable) Len() int {
+	return len(t[0])
+}
+
+// compress returns the i-th tuple compressed with the powers of theta
+func (t PlookupTable) compress(i int, theta Element) Element {
+	tuple := make([]Element, len(t))
+	for j := range t {
+		tuple[j] = t[j][i]
+	}
+	return compressTuple(tuple, theta)
+}
+
+// compressTuple returns SUM theta^j * tuple[j]
+func compressTuple(tuple []Element, theta Element) Element {
+	acc := theta.Clone().Zero()
+	for j := len(tuple) - 1; j >= 0; j-- {
+		acc = acc.Mul(acc, theta)
+		acc = acc.Add(acc, tuple[j])
+	}
+	return acc
+}
+
+// PlookupKey contains the preprocessed table and selector of a lookup
+// argument, interpolated on a domain.
+type PlookupKey struct {
+	SRS    KZGSetup
+	Domain Domain
+	// the table padded to the size of the domain by repeating its last tuple
+	table PlookupTable
+	// tables[j] interpolates the column j of the table and Tables[j] is its
+	// commitment
+	tables []Poly
+	Tables []G1
+	// rows marks the looked up rows, selector interpolates them and S is its
+	// commitment
+	rows     []bool
+	selector Poly
+	S        G1
+	// nbValues is the number of looked up rows for a standalone argument
+	nbValues int
+}
+
+// NewPlookupKey returns the key to prove that nbValues tuples are in the table
+// with a standalone argument. The table must be on the curve of the setup.
+func NewPlookupKey(srs KZGSetup, table PlookupTable, nbValues int) PlookupKey {
+	n := table.Len()
+	if nbValues+1 > n {
+		n = nbValues + 1
+	}
+	selector := make([]bool, nextPowerOfTwo(n))
+	for i := 0; i < nbValues; i++ {
+		selector[i] = true
+	}
+	pk := newPlookupKey(srs, NewDomainWith(srs.Curve, len(selector)), table, selector)
+	pk.nbValues = nbValues
+	return pk
+}
+
+// newPlookupKey returns the key to look up the rows marked by the selector on
+// the given domain. The last row is never looked up: the grand product doesn't
+// constrain it.
+func newPlookupKey(srs KZGSetup, d Domain, table PlookupTable, selector []bool) PlookupKey {
+	n := d.Size
+	if table.Len() > n {
+		panic(fmt.Sprintf("table of size %d too big for a domain of size %d", table.Len(), n))
+	}
+	if len(selector) != n || selector[n-1] {
+		panic("the selector must cover the domain and not select the last row")
+	}
+	if srs.Degree() < n+2 {
+		panic(fmt.Sprintf("setup of degree %d too small for a domain of size %d", srs.Degree(), n))
+	}
+	curve := srs.Curve
+	pk := PlookupKey{SRS: srs, Domain: d}
+	pk.table = make(PlookupTable, len(table))
+	for j, col := range table {
+		pk.table[j] = append([]Element{}, col...)
+		for len(pk.table[j]) < n {
+			pk.table[j] = append(pk.table[j], col[len(col)-1])
+		}
+		t := d.Interpolate(pk.table[j])
+		pk.tables = append(pk.tables, t)
+		pk.Tables = append(pk.Tables, KZGCommit(srs, t))
+	}
+	sel := make([]Element, n)
+	for i, s := range selector {
+		sel[i] = curve.NewElement()
+		if s {
+			sel[i] = curve.Element(1)
+		}
+	}
+	pk.rows = selector
+	pk.selector = d.Interpolate(sel)
+	pk.S = KZGCommit(srs, pk.selector)
+	return pk
+}
+
+// PlookupProof contains the commitments and evaluations of a lookup argument
+type PlookupProof struct {
+	// Commitments to the looked up columns, only set for a standalone
+	// argument. When plugged into PLONK these are the wire commitments.
+	W []G1
+	// Commitments to the compressed looked up column f, the two halves of the
+	// sorted vector s, the grand product z and the parts of the quotient
+	F  G1
+	H1 G1
+	H2 G1
+	Z  G1
+	Q  []G1
+	// Evaluations at zeta
+	FEval  Element
+	TEval  Element
+	H1Eval Element
+	H2Eval Element
+	ZEval  Element
+	SEval  Element
+	WEval  Element
+	QEvals []Element
+	// Evaluations at zeta * w
+	TOmegaEval  Element
+	H1OmegaEval Element
+	H2OmegaEval Element
+	ZOmegaEval  Element
+	// KZG opening proofs at zeta and zeta * w
+	WZeta      G1
+	WZetaOmega G1
+}
+
+// wellFormed returns true if the proof and the commitments to the looked up
+// columns contain all their points and evaluations
+func (p *PlookupProof) wellFormed(wCommits []G1) bool {
+	points := append([]G1{p.F, p.H1, p.H2, p.Z, p.WZeta, p.WZetaOmega}, wCommits...)
+	for _, c := range append(points, p.Q...) {
+		if c == nil {
+			return false
+		}
+	}
+	evals := []Element{p.FEval, p.TEval, p.H1Eval, p.H2Eval, p.ZEval, p.SEval, p.WEval,
+		p.TOmegaEval, p.H1OmegaEval, p.H2OmegaEval, p.ZOmegaEval}
+	for _, e := range append(evals, p.QEvals...) {
+		if e == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// PlookupProve returns a standalone proof that all the tuples are in the
+// table of the key. values[j] contains the j-th element of all the tuples.
+func PlookupProve(pk PlookupKey, values [][]Element) (PlookupProof, error) {
+	if len(values) != len(pk.table) {
+		return PlookupProof{}, errors.New("plookup: wrong number of columns")
+	}
+	d := pk.Domain
+	curve := pk.SRS.Curve
+	var wires []Poly
+	var evals [][]Element
+	var commits []G1
+	for _, col := range values {
+		if len(col) != pk.nbValues {
+			return PlookupProof{}, errors.New("plookup: wrong number of values")
+		}
+		w := append([]Element{}, col...)
+		blinding := Poly([]Element{randomElement(curve), randomElement(curve)}).Mul(d.Vanishing())
+		wires = append(wires, d.Interpolate(w).Add(blinding))
+		for len(w) < d.Size {
+			w = append(w, curve.NewElement())
+		}
+		evals = append(evals, w)
+		commits = append(commits, KZGCommit(pk.SRS, wires[len(wires)-1]))
+	}
+	proof, err := plookupProve(&pk, transcript.New("plookup"), wires, evals, commits)
+	proof.W = commits
+	return proof, err
+}
+
+// PlookupVerify returns true if the standalone proof is valid
+func PlookupVerify(pk PlookupKey, proof PlookupProof) bool {
+	return plookupVerify(&pk, transcript.New("plookup"), proof.W, proof)
+}
+
+// plookupProve runs the lookup argument on the wires, given as polynomials,
+// their evaluations on the domain and their commitments.
+func plookupProve(pk *PlookupKey, tr *transcript.Transcript, wires []Poly, wEvals [][]Element, wCommits []G1) (PlookupProof, error) {
+	var proof PlookupProof
+	d := pk.Domain
+	n := d.Size
+	srs := pk.SRS
+	curve := srs.Curve
+	pk.appendKey(tr)
+	for _, c := range wCommits {
+		tr.AppendPoint("w", c)
+	}
+	theta := tr.ChallengeScalar("theta", curve.Suite.G1())
+
+	// compress the table and the wires, and build f
+	w := Poly([]Element{curve.NewElement()})
+	var t = Poly([]Element{curve.NewElement()})
+	thetaJ := curve.Element(1)
+	for j := range wires {
+		w = w.Add(wires[j].Scale(thetaJ))
+		t = t.Add(pk.tables[j].Scale(thetaJ))
+		thetaJ = thetaJ.Mul(thetaJ, theta)
+	}
+	t0 := pk.table.compress(0, theta)
+	tEvals := make([]Element, n)
+	fEvals := make([]Element, n)
+	for i := 0; i < n; i++ {
+		tEvals[i] = pk.table.compress(i, theta)
+		fEvals[i] = t0
+		if pk.rows[i] {
+			tuple := make([]Element, len(wEvals))
+			for j := range wEvals {
+				tuple[j] = wEvals[j][i]
+			}
+			fEvals[i] = compressTuple(tuple, theta)
+		}
+	}
+	f := d.Interpolate(fEvals).Add(Poly([]Element{randomElement(curve), randomElement(curve)}).Mul(d.Vanishing()))
+	proof.F = KZGCommit(srs, f)
+	tr.AppendPoint("f", proof.F)
+
+	// s = (f, t) sorted by t: each value of f is placed right after the same
+	// value in t. The last row of f is not looked up.
+	count := make(map[string]int)
+	for _, v := range fEvals[:n-1] {
+		count[v.String()]++
+	}
+	var s []Element
+	for _, v := range tEvals {
+		s = append(s, v)
+		for ; count[v.String()] > 0; count[v.String()]-- {
+			s = append(s, v)
+		}
+	}
+	if len(s) != 2*n-1 {
+		return proof, errors.New("plookup: value not in the table")
+	}
+	blind := func() Poly {
+		return Poly([]Element{randomElement(curve), randomElement(curve), randomElement(curve)}).Mul(d.Vanishing())
+	}
+	h1 := d.Interpolate(s[:n]).Add(blind())
+	h2 := d.Interpolate(s[n-1:]).Add(blind())
+	proof.H1 = KZGCommit(srs, h1)
+	proof.H2 = KZGCommit(srs, h2)
+	tr.AppendPoint("h1", proof.H1)
+	tr.AppendPoint("h2", proof.H2)
+	beta := tr.ChallengeScalar("beta", curve.Suite.G1())
+	gamma := tr.ChallengeScalar("gamma", curve.Suite.G1())
+
+	// z(w^0) = 1 and z(w^(i+1)) = z(w^i) * num_i / den_i with
+	//	num_i = (1 + beta) * (gamma + f_i) * (gamma(1 + beta) + t_i + beta * t_(i+1))
+	//	den_i = (gamma(1 + beta) + s_i + beta * s_(i+1))
+	//			* (gamma(1 + beta) + s_(n-1+i) + beta * s_(n+i))
+	onePlusBeta := curve.NewElement().Add(curve.Element(1), beta)
+	gammaBeta := curve.NewElement().Mul(gamma, onePlusBeta)
+	pairTerm := func(a, b Element) Element {
+		r := curve.NewElement().Mul(beta, b)
+		r = r.Add(r, a)
+		return r.Add(r, gammaBeta)
+	}
+	zEvals := []Element{curve.Element(1)}
+	for i := 0; i < n-1; i++ {
+		num := curve.NewElement().Mul(onePlusBeta, curve.NewElement().Add(gamma, fEvals[i]))
+		num = num.Mul(num, pairTerm(tEvals[i], tEvals[i+1]))
+		den := curve.NewElement().Mul(pairTerm(s[i], s[i+1]), pairTerm(s[n-1+i], s[n+i]))
+		zi := curve.NewElement().Mul(zEvals[i], num)
+		zEvals = append(zEvals, zi.Div(zi, den))
+	}
+	z := d.Interpolate(zEvals).Add(blind())
+	proof.Z = KZGCommit(srs, z)
+	tr.AppendPoint("z", proof.Z)
+	alpha := tr.ChallengeScalar("alpha", curve.Suite.G1())
+
+	// the quotient q(x) = SUM alpha^k * identity_k(x) / Z_H(x) where the
+	// identities must vanish on the domain:
+	//	0: L_0(x) * (z(x) - 1)
+	//	1: (x - w^(n-1)) * [z(x) * (1 + beta) * (gamma + f(x))
+	//			* (gamma(1 + beta) + t(x) + beta * t(w * x))
+	//		- z(w * x) * (gamma(1 + beta) + h1(x) + beta * h1(w * x))
+	//			* (gamma(1 + beta) + h2(x) + beta * h2(w * x))]
+	//	2: L_(n-1)(x) * (h1(x) - h2(w * x))
+	//	3: L_(n-1)(x) * (z(x) - 1)
+	//	4: f(x) - q(x) * w(x) - (1 - q(x)) * t_0
+	// where q is the selector
+	cst := func(e Element) Poly {
+		return Poly([]Element{e})
+	}
+	lFirst := d.Lagrange(0)
+	lLast := d.Lagrange(n - 1)
+	last := Poly([]Element{curve.NewElement().Neg(d.Elements[n-1]), curve.Element(1)})
+	pairPoly := func(a Poly) Poly {
+		return a.Add(a.ScaleVar(d.Omega).Scale(beta)).Add(cst(gammaBeta))
+	}
+	ids := []Poly{
+		lFirst.Mul(z.Sub(cst(curve.Element(1)))),
+		last.Mul(z.Scale(onePlusBeta).Mul(f.Add(cst(gamma))).Mul(pairPoly(t)).
+			Sub(z.ScaleVar(d.Omega).Mul(pairPoly(h1)).Mul(pairPoly(h2)))),
+		lLast.Mul(h1.Sub(h2.ScaleVar(d.Omega))),
+		lLast.Mul(z.Sub(cst(curve.Element(1)))),
+		f.Sub(pk.selector.Mul(w)).Sub(cst(curve.Element(1)).Sub(pk.selector).Scale(t0)),
+	}
+	num := Poly([]Element{curve.NewElement()})
+	alphaK := curve.Element(1)
+	for _, id := range ids {
+		num = num.Add(id.Scale(alphaK))
+		alphaK = alphaK.Mul(alphaK, alpha)
+	}
+	q, _ := d.DivideByVanishing(num)
+	// q is split in parts of n coefficients such that
+	//	q(x) = q_0(x) + x^n * q_1(x) + x^2n * q_2(x) + ...
+	var qs []Poly
+	for k := 0; k*n < len(q); k++ {
+		qs = append(qs, plonkCoeffs(curve, q, k*n, (k+1)*n))
+		proof.Q = append(proof.Q, KZGCommit(srs, qs[k]))
+		tr.AppendPoint("q", proof.Q[k])
+	}
+	zeta := tr.ChallengeScalar("zeta", curve.Suite.G1())
+	zetaOmega := curve.NewElement().Mul(zeta, d.Omega)
+
+	proof.FEval = f.Eval(zeta)
+	proof.TEval = t.Eval(zeta)
+	proof.H1Eval = h1.Eval(zeta)
+	proof.H2Eval = h2.Eval(zeta)
+	proof.ZEval = z.Eval(zeta)
+	proof.SEval = pk.selector.Eval(zeta)
+	proof.WEval = w.Eval(zeta)
+	for _, qk := range qs {
+		proof.QEvals = append(proof.QEvals, qk.Eval(zeta))
+	}
+	proof.TOmegaEval = t.Eval(zetaOmega)
+	proof.H1OmegaEval = h1.Eval(zetaOmega)
+	proof.H2OmegaEval = h2.Eval(zetaOmega)
+	proof.ZOmegaEval = z.Eval(zetaOmega)
+	appendPlookupEvals(tr, &proof)
+
+	tCommit, wCommit := pk.compressedCommits(theta, wCommits)
+	polys := append([]Poly{f, t, h1, h2, z, pk.selector, w}, qs...)
+	commits := append([]G1{proof.F, tCommit, proof.H1, proof.H2, proof.Z, pk.S, wCommit}, proof.Q...)
+	proof.WZeta = KZGOpenMulti(srs, tr, polys, commits, zeta).W
+	polys = []Poly{t, h1, h2, z}
+	commits = []G1{tCommit, proof.H1, proof.H2, proof.Z}
+	proof.WZetaOmega = KZGOpenMulti(srs, tr, polys, commits, zetaOmega).W
+	return proof, nil
+}
+
+// plookupVerify returns true if the proof shows that the rows of the wires
+// committed in wCommits selected by the key are in the table. The verifier
+// recomputes the identities at zeta from the evaluations and checks the
+// openings of all the polynomials.
+func plookupVerify(pk *PlookupKey, tr *transcript.Transcript, wCommits []G1, proof PlookupProof) bool {
+	d := pk.Domain
+	n := d.Size
+	curve := pk.SRS.Curve
+	if len(wCommits) != len(pk.table) || len(proof.Q) != len(proof.QEvals) || len(proof.Q) == 0 {
+		return false
+	}
+	if !proof.wellFormed(wCommits) {
+		return false
+	}
+	pk.appendKey(tr)
+	for _, c := range wCommits {
+		tr.AppendPoint("w", c)
+	}
+	theta := tr.ChallengeScalar("theta", curve.Suite.G1())
+	tr.AppendPoint("f", proof.F)
+	tr.AppendPoint("h1", proof.H1)
+	tr.AppendPoint("h2", proof.H2)
+	beta := tr.ChallengeScalar("beta", curve.Suite.G1())
+	gamma := tr.ChallengeScalar("gamma", curve.Suite.G1())
+	tr.AppendPoint("z", proof.Z)
+	alpha := tr.ChallengeScalar("alpha", curve.Suite.G1())
+	for _, q := range proof.Q {
+		tr.AppendPoint("q", q)
+	}
+	zeta := tr.ChallengeScalar("zeta", curve.Suite.G1())
+	zetaOmega := curve.NewElement().Mul(zeta, d.Omega)
+	appendPlookupEvals(tr, &proof)
+
+	onePlusBeta := curve.NewElement().Add(curve.Element(1), beta)
+	gammaBeta := curve.NewElement().Mul(gamma, onePlusBeta)
+	pairTerm := func(a, b Element) Element {
+		r := curve.NewElement().Mul(beta, b)
+		r = r.Add(r, a)
+		return r.Add(r, gammaBeta)
+	}
+	lFirst := d.EvalLagrange(0, zeta)
+	lLast := d.EvalLagrange(n-1, zeta)
+	zMinusOne := curve.NewElement().Sub(proof.ZEval, curve.Element(1))
+	id1 := curve.NewElement().Mul(proof.ZEval, onePlusBeta)
+	id1 = id1.Mul(id1, curve.NewElement().Add(gamma, proof.FEval))
+	id1 = id1.Mul(id1, pairTerm(proof.TEval, proof.TOmegaEval))
+	right := curve.NewElement().Mul(proof.ZOmegaEval, pairTerm(proof.H1Eval, proof.H1OmegaEval))
+	right = right.Mul(right, pairTerm(proof.H2Eval, proof.H2OmegaEval))
+	id1 = id1.Sub(id1, right)
+	id1 = id1.Mul(id1, curve.NewElement().Sub(zeta, d.Elements[n-1]))
+	// f - q * w - (1 - q) * t_0
+	t0 := pk.table.compress(0, theta)
+	id4 := curve.NewElement().Sub(proof.FEval, curve.NewElement().Mul(proof.SEval, proof.WEval))
+	id4 = id4.Sub(id4, curve.NewElement().Mul(curve.NewElement().Sub(curve.Element(1), proof.SEval), t0))
+	ids := []Element{
+		curve.NewElement().Mul(lFirst, zMinusOne),
+		id1,
+		curve.NewElement().Mul(lLast, curve.NewElement().Sub(proof.H1Eval, proof.H2OmegaEval)),
+		curve.NewElement().Mul(lLast, zMinusOne),
+		id4,
+	}
+	acc := curve.NewElement()
+	alphaK := curve.Element(1)
+	for _, id := range ids {
+		acc = acc.Add(acc, curve.NewElement().Mul(alphaK, id))
+		alphaK = alphaK.Mul(alphaK, alpha)
+	}
+	// q(zeta) = SUM zeta^(kn) * q_k(zeta)
+	q := curve.NewElement()
+	zetaN := curve.NewElement().Add(d.EvalVanishing(zeta), curve.Element(1))
+	zetaKN := curve.Element(1)
+	for _, qk := range proof.QEvals {
+		q = q.Add(q, curve.NewElement().Mul(zetaKN, qk))
+		zetaKN = zetaKN.Mul(zetaKN, zetaN)
+	}
+	if !acc.Equal(q.Mul(q, d.EvalVanishing(zeta))) {
+		return false
+	}
+
+	tCommit, wCommit := pk.compressedCommits(theta, wCommits)
+	commits := append([]G1{proof.F, tCommit, proof.H1, proof.H2, proof.Z, pk.S, wCommit}, proof.Q...)
+	evals := append([]Element{proof.FEval, proof.TEval, proof.H1Eval, proof.H2Eval, proof.ZEval, proof.SEval, proof.WEval}, proof.QEvals...)
+	if !KZGVerifyMulti(pk.SRS, tr, commits, KZGMultiProof{Z: zeta, Ys: evals, W: proof.WZeta}) {
+		return false
+	}
+	commits = []G1{tCommit, proof.H1, proof.H2, proof.Z}
+	evals = []Element{proof.TOmegaEval, proof.H1OmegaEval, proof.H2OmegaEval, proof.ZOmegaEval}
+	return KZGVerifyMulti(pk.SRS, tr, commits, KZGMultiProof{Z: zetaOmega, Ys: evals, W: proof.WZetaOmega})
+}
+
+// appendKey binds the transcript to the table and the selector
+func (pk *PlookupKey) appendKey(tr *transcript.Transcript) {
+	tr.AppendMessage("dom-sep", []byte("plookup"))
+	tr.AppendUint64("n", uint64(pk.Domain.Size))
+	for _, t := range pk.Tables {
+		tr.AppendPoint("table", t)
+	}
+	tr.AppendPoint("selector", pk.S)
+}
+
+// compressedCommits returns the commitments to the compressed table and wires
+// using the homomorphism of the commitments
+func (pk *PlookupKey) compressedCommits(theta Element, wCommits []G1) (G1, G1) {
+	curve := pk.SRS.Curve
+	t := curve.NewG1().Null()
+	w := curve.NewG1().Null()
+	thetaJ := curve.Element(1)
+	for j := range wCommits {
+		t = t.Add(t, curve.NewG1().Mul(thetaJ, pk.Tables[j]))
+		w = w.Add(w, curve.NewG1().Mul(thetaJ, wCommits[j]))
+		thetaJ = thetaJ.Mul(thetaJ, theta)
+	}
+	return t, w
+}
+
+func appendPlookupEvals(tr *transcript.Transcript, p *PlookupProof) {
+	for _, e := range append([]Element{p.FEval, p.TEval, p.H1Eval, p.H2Eval, p.ZEval, p.SEval, p.WEval}, p.QEvals...) {
+		tr.AppendScalar("zeta", e)
+	}
+	for _, e := range []Element{p.TOmegaEval, p.H1OmegaEval, p.H2OmegaEval, p.ZOmegaEval} {
+		tr.AppendScalar("zeta_omega", e)
+	}
+}
+
+// randomElement returns a random element of the scalar field of the curve
+func randomElement(c Curve) Element {
+	return c.NewElement().Pick(random.New())
+}
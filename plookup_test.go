@@ -0,0 +1,165 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func toElements(curve Curve, vs ...int64) []Element {
+	out := make([]Element, len(vs))
+	for i, v := range vs {
+		out[i] = curve.NewElement().SetInt64(v)
+	}
+	return out
+}
+
+func TestPlookupRange(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			table := NewRangeTableWith(curve, 8)
+			require.Equal(t, 256, table.Len())
+			srs := NewKZGSetupWith(curve, 256+2, 1)
+			pk := NewPlookupKey(srs, table, 6)
+			require.Equal(t, 256, pk.Domain.Size)
+
+			values := [][]Element{toElements(curve, 0, 1, 255, 42, 42, 128)}
+			proof, err := PlookupProve(pk, values)
+			require.NoError(t, err)
+			require.True(t, PlookupVerify(pk, proof))
+
+			// tampered evaluation
+			tampered := proof
+			tampered.H1Eval = curve.NewElement().Add(proof.H1Eval, curve.Element(1))
+			require.False(t, PlookupVerify(pk, tampered))
+			// commitment to other values
+			other, err := PlookupProve(pk, [][]Element{toElements(curve, 1, 2, 3, 4, 5, 6)})
+			require.NoError(t, err)
+			tampered = proof
+			tampered.W = other.W
+			require.False(t, PlookupVerify(pk, tampered))
+			// a proof missing any of its elements is rejected without panicking
+			missing := proof
+			for _, c := range []*G1{&missing.W[0], &missing.F, &missing.H1, &missing.H2, &missing.Z,
+				&missing.Q[0], &missing.WZeta, &missing.WZetaOmega} {
+				saved := *c
+				*c = nil
+				require.False(t, PlookupVerify(pk, missing))
+				*c = saved
+			}
+			for _, e := range []*Element{&missing.FEval, &missing.TEval, &missing.H1Eval,
+				&missing.H2Eval, &missing.ZEval, &missing.SEval, &missing.WEval, &missing.QEvals[0],
+				&missing.TOmegaEval, &missing.H1OmegaEval, &missing.H2OmegaEval, &missing.ZOmegaEval} {
+				saved := *e
+				*e = nil
+				require.False(t, PlookupVerify(pk, missing))
+				*e = saved
+			}
+			require.True(t, PlookupVerify(pk, missing))
+			require.False(t, PlookupVerify(pk, PlookupProof{}))
+
+			// 256 is not on 8 bits
+			_, err = PlookupProve(pk, [][]Element{toElements(curve, 0, 1, 256, 42, 42, 128)})
+			require.Error(t, err)
+			// neither is -1
+			_, err = PlookupProve(pk, [][]Element{toElements(curve, 0, 1, -1, 42, 42, 128)})
+			require.Error(t, err)
+			// wrong number of values
+			_, err = PlookupProve(pk, [][]Element{toElements(curve, 0, 1)})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPlookupXOR(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			table := NewXORTableWith(curve, 4)
+			require.Equal(t, 256, table.Len())
+			srs := NewKZGSetupWith(curve, 256+2, 1)
+			pk := NewPlookupKey(srs, table, 3)
+
+			a := toElements(curve, 3, 15, 9)
+			b := toElements(curve, 5, 15, 6)
+			proof, err := PlookupProve(pk, [][]Element{a, b, toElements(curve, 3^5, 0, 9^6)})
+			require.NoError(t, err)
+			require.True(t, PlookupVerify(pk, proof))
+
+			// a wrong XOR, even though each value is in its column
+			_, err = PlookupProve(pk, [][]Element{a, b, toElements(curve, 3^5, 1, 9^6)})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestPlonkLookup(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			// out = x + y with x and y on 8 bits
+			c := NewPlonkCircuitWith(curve)
+			c.NewInput("x")
+			c.NewOutput("out")
+			c.NewVar("y")
+			c.SetTable(NewRangeTableWith(curve, 8))
+			c.Lookup("x")
+			c.Lookup("y")
+			c.Add("x", "y", "out")
+			srs := NewPlonkSetupWith(curve, 256)
+			pk := PlonkPreprocess(srs, c)
+			require.Equal(t, 256, pk.Domain.Size)
+			require.NotNil(t, pk.Lookup)
+
+			s := Vector{200, 255, 55}
+			require.True(t, c.IsSatisfied(s))
+			proof := PlonkProve(pk, s)
+			require.True(t, PlonkVerify(pk.PlonkVerifyingKey, proof, s[:pk.NbPublic]))
+			// the lookup proof is required
+			tampered := proof
+			tampered.Lookup = nil
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, tampered, s[:pk.NbPublic]))
+			// and must be complete
+			lookup := *proof.Lookup
+			lookup.H1 = nil
+			tampered.Lookup = &lookup
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, tampered, s[:pk.NbPublic]))
+
+			// all gates are satisfied but y is not on 8 bits
+			s = Vector{200, 456, 256}
+			require.False(t, c.IsSatisfied(s))
+			proof = PlonkProve(pk, s)
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, proof, s[:pk.NbPublic]))
+
+			// wrong number of variables for the table
+			require.Panics(t, func() { c.Lookup("x", "y") })
+		})
+	}
+}
+
+func TestPlonkLookupXOR(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			// out = (a XOR b) + 1 with a and b on 4 bits
+			c := NewPlonkCircuitWith(curve)
+			c.NewInput("a")
+			c.NewInput("b")
+			c.NewOutput("out")
+			c.NewVar("x")
+			c.SetTable(NewXORTableWith(curve, 4))
+			c.Lookup("a", "b", "x")
+			c.AddConst("x", 1, "out")
+			pk := PlonkPreprocess(NewPlonkSetupWith(curve, 256), c)
+
+			s := Vector{12, 10, 7, 6}
+			require.True(t, c.IsSatisfied(s))
+			proof := PlonkProve(pk, s)
+			require.True(t, PlonkVerify(pk.PlonkVerifyingKey, proof, s[:pk.NbPublic]))
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, proof, Vector{12, 11, 7}))
+
+			// 12 AND 10 instead of XOR
+			s = Vector{12, 10, 9, 8}
+			require.False(t, c.IsSatisfied(s))
+			proof = PlonkProve(pk, s)
+			require.False(t, PlonkVerify(pk.PlonkVerifyingKey, proof, s[:pk.NbPublic]))
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package poseidon implements the Poseidon permutation and sponge hash over
+// the scalar field of BLS12-381, natively and as a gadget of the frontend
+// package with the same output. The state has Width elements: the first one
+// is the capacity and the other ones absorb the inputs. Each round adds
+// constants to the state, applies the S-box x^5 and multiplies the state by a
+// MDS matrix. The full rounds apply the S-box to the whole state, the partial
+// ones to its first element only, which makes Poseidon cheaper than MiMC in a
+// circuit since the linear layers are free.
+//
+// The round constants are derived from SHA-256 and the MDS matrix is the
+// Cauchy matrix 1 / (i + Width + j), so the hashes are specific to this
+// package.
+package poseidon
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+)
+
+type Element = playsnark.Element
+
+const (
+	// Width is the number of elements of the state
+	Width = 3
+	// Rate is the number of elements absorbed per permutation
+	Rate = Width - 1
+	// FullRounds is the number of full rounds, half of them before the
+	// partial rounds and half after
+	FullRounds = 8
+	// PartialRounds is the number of partial rounds, for 128 bits of security
+	// with a width of 3 and x^5 on a 255 bits field
+	PartialRounds = 57
+)
+
+// curve is the curve whose scalar field Poseidon is defined over
+var curve = playsnark.BLS12381
+
+var (
+	// constants are the Width constants of each round
+	constants = roundConstants()
+	mds       = mdsMatrix()
+)
+
+func roundConstants() [][Width]Element {
+	cs := make([][Width]Element, FullRounds+PartialRounds)
+	for r := range cs {
+		for i := range cs[r] {
+			cs[r][i] = curve.ElementFromBig(seed("poseidon", r*Width+i))
+		}
+	}
+	return cs
+}
+
+// seed returns the integer SHA-256(label || i)
+func seed(label string, i int) *big.Int {
+	h := sha256.New()
+	h.Write([]byte(label))
+	binary.Write(h, binary.BigEndian, uint32(i))
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// mdsMatrix returns the Cauchy matrix 1 / (x_i + y_j) with x_i = i and
+// y_j = Width + j: it is MDS since the x_i are distinct, the y_j are distinct
+// and all the sums are non zero.
+func mdsMatrix() [Width][Width]Element {
+	var m [Width][Width]Element
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = curve.NewElement().Inv(curve.Element(playsnark.Value(i + Width + j)))
+		}
+	}
+	return m
+}
+
+// isFull returns true if the round r is a full round
+func isFull(r int) bool {
+	return r < FullRounds/2 || r >= FullRounds/2+PartialRounds
+}
+
+// Permute returns the permutation of the state
+func Permute(state [Width]Element) [Width]Element {
+	for i := range state {
+		state[i] = state[i].Clone()
+	}
+	for r, cs := range constants {
+		for i := range state {
+			state[i] = state[i].Add(state[i], cs[i])
+		}
+		for i := range state {
+			if i == 0 || isFull(r) {
+				state[i] = pow5(state[i])
+			}
+		}
+		var mixed [Width]Element
+		for i := range mixed {
+			mixed[i] = curve.NewElement()
+			for j := range state {
+				mixed[i] = mixed[i].Add(mixed[i], curve.NewElement().Mul(mds[i][j], state[j]))
+			}
+		}
+		state = mixed
+	}
+	return state
+}
+
+func pow5(x Element) Element {
+	x2 := curve.NewElement().Mul(x, x)
+	x4 := x2.Mul(x2, x2)
+	return x4.Mul(x4, x)
+}
+
+// Hash returns the hash of the elements with the sponge construction. The
+// capacity is initialized with the number of elements, the elements are
+// absorbed Rate by Rate, the last block padded with zeros, and the hash is the
+// second element of the final state.
+func Hash(data ...Element) Element {
+	var state [Width]Element
+	state[0] = curve.Element(playsnark.Value(len(data)))
+	for i := 1; i < Width; i++ {
+		state[i] = curve.NewElement()
+	}
+	for _, block := range blocks(len(data)) {
+		for i, m := range data[block[0]:block[1]] {
+			state[1+i] = state[1+i].Add(state[1+i], m)
+		}
+		state = Permute(state)
+	}
+	return state[1]
+}
+
+// blocks returns the bounds of the blocks of Rate elements, at least one
+func blocks(n int) [][2]int {
+	var bs [][2]int
+	for i := 0; i < n || i == 0; i += Rate {
+		end := i + Rate
+		if end > n {
+			end = n
+		}
+		bs = append(bs, [2]int{i, end})
+	}
+	return bs
+}
+
+// PermuteGadget returns the permutation of the state in the circuit. It costs
+// 3 constraints per S-box, so at most 3 * (Width * FullRounds +
+// PartialRounds) = 243 constraints: the S-boxes of the constants are free.
+func PermuteGadget(api frontend.API, state [Width]frontend.Variable) [Width]frontend.Variable {
+	if api.Curve().Name != curve.Name {
+		panic("poseidon: the constants are defined over the scalar field of " + curve.Name)
+	}
+	for r, cs := range constants {
+		for i := range state {
+			state[i] = api.Add(state[i], cs[i])
+			if i == 0 || isFull(r) {
+				x2 := api.Mul(state[i], state[i])
+				state[i] = api.Mul(x2, x2, state[i])
+			}
+		}
+		var mixed [Width]frontend.Variable
+		for i := range mixed {
+			mixed[i] = 0
+			for j := range state {
+				mixed[i] = api.Add(mixed[i], api.Mul(mds[i][j], state[j]))
+			}
+		}
+		state = mixed
+	}
+	return state
+}
+
+// HashGadget returns the hash of the variables in the circuit, equal to the
+// Hash of their values. It costs at most 243 constraints per block of Rate
+// variables.
+func HashGadget(api frontend.API, data ...frontend.Variable) frontend.Variable {
+	var state [Width]frontend.Variable
+	state[0] = len(data)
+	for i := 1; i < Width; i++ {
+		state[i] = 0
+	}
+	for _, block := range blocks(len(data)) {
+		for i, m := range data[block[0]:block[1]] {
+			state[1+i] = api.Add(state[1+i], m)
+		}
+		state = PermuteGadget(api, state)
+	}
+	return state[1]
+}
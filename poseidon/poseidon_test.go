@@ -0,0 +1,93 @@
+package poseidon
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark"
+	"github.com/nikkolasg/playsnark/frontend"
+	"github.com/stretchr/testify/require"
+)
+
+type hashCircuit struct {
+	Data []frontend.Variable `snark:"secret"`
+	Hash frontend.Variable   `snark:"public"`
+}
+
+func (c *hashCircuit) Define(api frontend.API) error {
+	api.AssertIsEqual(HashGadget(api, c.Data...), c.Hash)
+	return nil
+}
+
+func elements(vs ...playsnark.Value) []Element {
+	return curve.Elements(playsnark.Vector(vs))
+}
+
+func TestMDS(t *testing.T) {
+	// the 2x2 minors of a MDS matrix of width 3 are non zero
+	zero := curve.NewElement()
+	for i := 0; i < Width; i++ {
+		for j := i + 1; j < Width; j++ {
+			for k := 0; k < Width; k++ {
+				for l := k + 1; l < Width; l++ {
+					a := curve.NewElement().Mul(mds[i][k], mds[j][l])
+					b := curve.NewElement().Mul(mds[i][l], mds[j][k])
+					require.False(t, a.Sub(a, b).Equal(zero))
+				}
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	h := Hash(elements(1, 2)...)
+	require.True(t, h.Equal(Hash(elements(1, 2)...)))
+	require.False(t, h.Equal(Hash(elements(2, 1)...)))
+	// the length in the capacity separates the zero padding
+	require.False(t, Hash(elements(1)...).Equal(Hash(elements(1, 0)...)))
+	require.False(t, Hash().Equal(Hash(elements(0)...)))
+
+	var state [Width]Element
+	for i := range state {
+		state[i] = curve.Element(playsnark.Value(i))
+	}
+	permuted := Permute(state)
+	require.True(t, state[1].Equal(curve.Element(1)), "the state is not modified")
+	state[2] = curve.Element(3)
+	require.False(t, Permute(state)[0].Equal(permuted[0]))
+}
+
+func TestHashGadget(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4} {
+		data := make([]frontend.Variable, n)
+		r1cs, err := frontend.Compile(curve, &hashCircuit{Data: data})
+		require.NoError(t, err)
+		// one permutation per block and the assertion, minus the S-boxes of
+		// the first round on the constant elements: the capacity and the
+		// padding of the first block
+		nbBlocks := (n + Rate - 1) / Rate
+		nbConstants := 1 + Rate - min(n, Rate)
+		require.Equal(t, 243*nbBlocks+1-3*nbConstants, r1cs.NbConstraints())
+
+		values := make([]Element, n)
+		for i := range data {
+			values[i] = curve.NewElement().Pick(random.New())
+			data[i] = values[i]
+		}
+		_, err = r1cs.Solve(&hashCircuit{Data: data, Hash: Hash(values...)})
+		require.NoError(t, err)
+		values[0] = curve.NewElement().Add(values[0], curve.Element(1))
+		_, err = r1cs.Solve(&hashCircuit{Data: data, Hash: Hash(values...)})
+		require.Error(t, err)
+	}
+	require.Panics(t, func() {
+		frontend.Compile(playsnark.BN254, &hashCircuit{Data: make([]frontend.Variable, 1)})
+	})
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -24,6 +24,9 @@ type QAP struct {
 	out   []Poly
 	// z is the minimal polynomial (x-1)(x-2)(x-3)...
 	z Poly
+	// curve whose scalar field contains the coefficients of the polynomials
+	// and on which the proof systems run
+	curve Curve
 }
 
 // ToQAP takes a R1CS circuit description and turns it into its polynomial QAP
@@ -32,20 +35,48 @@ type QAP struct {
 // row represents the pairs of point that we want to interpolate.
 // It thens transforms these rows into their finite field version and
 // interpolate the polynomial.
+// The QAP is defined over the scalar field of the default curve, BLS12-381.
 func ToQAP(circuit R1CS) QAP {
-	left := qapInterpolate(circuit.left)
-	right := qapInterpolate(circuit.right)
-	out := qapInterpolate(circuit.out)
-	nbVar := len(circuit.vars)
-	nbGates := len(circuit.left)
+	return ToQAPWith(BLS12381, circuit)
+}
+
+// ToQAPWith returns the QAP of the circuit over the scalar field of the given
+// curve. Groth16 and PHGR13 run on the curve of the QAP.
+func ToQAPWith(c Curve, circuit R1CS) QAP {
+	constraints := make([]Constraint, len(circuit.left))
+	for i := range constraints {
+		constraints[i] = Constraint{
+			Left:  sparseRow(c, circuit.left[i]),
+			Right: sparseRow(c, circuit.right[i]),
+			Out:   sparseRow(c, circuit.out[i]),
+		}
+	}
+	return NewQAP(c, len(circuit.vars), circuit.nbIO(), constraints)
+}
+
+// Constraint is a constraint <Left,s> * <Right,s> = <Out,s> over the scalar
+// field of a curve. The rows are sparse: they map the index of a variable in
+// the solution s to its coefficient, the missing ones being zero.
+type Constraint struct {
+	Left  map[int]Element
+	Right map[int]Element
+	Out   map[int]Element
+}
+
+// NewQAP returns the QAP of the constraints on nbVars variables, over the
+// scalar field of the given curve. As in a R1CS, the first nbIO variables are
+// "const" and the public values. It allows the constraints whose coefficients
+// don't fit in a Value.
+func NewQAP(c Curve, nbVars, nbIO int, constraints []Constraint) QAP {
+	nbGates := len(constraints)
 	var z Poly
 	for i := 1; i <= nbGates; i++ {
 		// you multiply by (x - i) with i being as high as the number of gates,
 		// because the polynomials left,right and out vanishes on these inputs
 		// -i + x
 		xi := Poly([]Element{
-			NewElement().Neg(Value(i).ToFieldElement()),
-			one.Clone(),
+			c.NewElement().Neg(c.Element(Value(i))),
+			c.NewElement().One(),
 		})
 		if z == nil {
 			z = xi
@@ -53,18 +84,23 @@ func ToQAP(circuit R1CS) QAP {
 			z = z.Mul(xi)
 		}
 	}
+	left, right, out := make([]map[int]Element, nbGates), make([]map[int]Element, nbGates), make([]map[int]Element, nbGates)
+	for i, cs := range constraints {
+		left[i], right[i], out[i] = cs.Left, cs.Right, cs.Out
+	}
 	return QAP{
-		nbVars:  nbVar,
+		nbVars:  nbVars,
 		nbGates: nbGates,
-		nbIO:    circuit.nbIO(),
-		left:    left,
-		right:   right,
-		out:     out,
+		nbIO:    nbIO,
+		left:    interpolateColumns(c, nbVars, z, left),
+		right:   interpolateColumns(c, nbVars, z, right),
+		out:     interpolateColumns(c, nbVars, z, out),
 		z:       z,
+		curve:   c,
 	}
 }
 
-func qapInterpolate(m Matrix) []Poly {
+func qapInterpolate(c Curve, m Matrix) []Poly {
 	// once transposed, a row of this matrix represents all the usage of this
 	// variable at each step of the circuit
 	// so for example, all the assignements on the lefts inputs look like this
@@ -84,7 +120,7 @@ func qapInterpolate(m Matrix) []Poly {
 	for _, variable := range t {
 		var ys = make([]Element, 0, len(t))
 		for _, v := range variable {
-			ys = append(ys, v.ToFieldElement())
+			ys = append(ys, c.Element(v))
 		}
 		poly := Interpolate(ys)
 		out = append(out, poly)
@@ -92,6 +128,70 @@ func qapInterpolate(m Matrix) []Poly {
 	return out
 }
 
+// interpolateColumns returns the polynomials interpolating the columns of the
+// sparse rows, one for each variable, on the points 1, 2, ... n where z is the
+// polynomial vanishing on these points. Since the rows are sparse, the
+// polynomials are sums of the Lagrange polynomials
+//
+//	L_j(x) = w_j * z(x) / (x - j) with w_j = 1 / PROD_{k != j} (j - k)
+//
+// for the non zero entries only, instead of one interpolation per variable.
+func interpolateColumns(c Curve, nbVars int, z Poly, rows []map[int]Element) []Poly {
+	n := len(rows)
+	polys := make([]Poly, nbVars)
+	for i := range polys {
+		polys[i] = make(Poly, n)
+		for j := range polys[i] {
+			polys[i][j] = c.NewElement()
+		}
+	}
+	// w_j = 1 / ((j-1)! * (-1)^(n-j) * (n-j)!) for j:1->n
+	fact := make([]Element, n)
+	fact[0] = c.NewElement().One()
+	for i := 1; i < n; i++ {
+		fact[i] = c.NewElement().Mul(fact[i-1], c.Element(Value(i)))
+	}
+	for j, row := range rows {
+		if len(row) == 0 {
+			continue
+		}
+		w := c.NewElement().Mul(fact[j], fact[n-1-j])
+		if (n-1-j)%2 == 1 {
+			w = w.Neg(w)
+		}
+		w = w.Inv(w)
+		// z(x) / (x - j) by synthetic division, x - j being a factor of z
+		basis := make(Poly, n)
+		xj := c.Element(Value(j + 1))
+		acc := c.NewElement()
+		for k := n; k >= 1; k-- {
+			acc = c.NewElement().Add(z[k], c.NewElement().Mul(acc, xj))
+			basis[k-1] = c.NewElement().Mul(acc, w)
+		}
+		for i, coeff := range row {
+			if isZero(coeff) {
+				continue
+			}
+			for k := range basis {
+				polys[i][k] = polys[i][k].Add(polys[i][k], c.NewElement().Mul(coeff, basis[k]))
+			}
+		}
+	}
+	return polys
+}
+
+// sparseRow returns the non zero entries of the row as elements of the scalar
+// field
+func sparseRow(c Curve, row Vector) map[int]Element {
+	sparse := make(map[int]Element)
+	for i, v := range row {
+		if v != 0 {
+			sparse[i] = c.Element(v)
+		}
+	}
+	return sparse
+}
+
 func toPoly(s *share.PriPoly) Poly {
 	pp := s.Coefficients()
 	return Poly(pp)
@@ -105,7 +205,13 @@ func toPoly(s *share.PriPoly) Poly {
 // the polynomial t vanishes on all the points corresponding to the gate since
 // z is a factor, hence the solution is correct
 func (q *QAP) IsValid(sol Vector) bool {
-	q.sanityCheck(sol)
+	return q.IsValidElements(q.curve.Elements(sol))
+}
+
+// IsValidElements is IsValid for a solution whose values are elements of the
+// scalar field of the QAP
+func (q *QAP) IsValidElements(sol []Element) bool {
+	q.sanityCheck(len(sol))
 	// We need to multiply each entry of the solution with the corresponding
 	// polynomial.
 	// The original python code is short and self explanatory:
@@ -134,7 +240,7 @@ func (q *QAP) IsValid(sol Vector) bool {
 	//   first gate, second term will give the value of the input "x" at the
 	//   first gate etc leading to the same values as in R1CS.
 	// Left one
-	left, right, out := q.computeAggregatePoly(sol)
+	left, right, out := q.aggregate(sol)
 	// Now we are using these precedent polynomials in the satisfying equation
 	// t(x) = left(x) * right(x) - out(x) = h(x)z(x)
 	t := left.Mul(right).Sub(out)
@@ -149,8 +255,12 @@ func (q *QAP) IsValid(sol Vector) bool {
 }
 
 func (q QAP) Quotient(sol Vector) Poly {
+	return q.quotient(q.curve.Elements(sol))
+}
+
+func (q QAP) quotient(sol []Element) Poly {
 	// compute h(x) then evaluate it blindly at point s
-	left, right, out := q.computeAggregatePoly(sol)
+	left, right, out := q.aggregate(sol)
 	// p(x) = t(x) * h(x)
 	px := left.Mul(right).Sub(out)
 	// h(x) = p(x) / t(x)
@@ -162,11 +272,15 @@ func (q QAP) Quotient(sol Vector) Poly {
 }
 
 func (q QAP) computeAggregatePoly(sol Vector) (left Poly, right Poly, out Poly) {
+	return q.aggregate(q.curve.Elements(sol))
+}
+
+func (q QAP) aggregate(sol []Element) (left Poly, right Poly, out Poly) {
 	left = Poly([]Element{})
 	right = Poly([]Element{})
 	out = Poly([]Element{})
 	for varIndex, val := range sol {
-		polyVal := Poly([]Element{val.ToFieldElement()})
+		polyVal := Poly([]Element{val})
 		left = left.Add(q.left[varIndex].Mul(polyVal))
 		right = right.Add(q.right[varIndex].Mul(polyVal))
 		out = out.Add(q.out[varIndex].Mul(polyVal))
@@ -174,16 +288,16 @@ func (q QAP) computeAggregatePoly(sol Vector) (left Poly, right Poly, out Poly)
 	return
 }
 
-func (q QAP) sanityCheck(sol Vector) {
-	if len(sol) != len(q.left) {
+func (q QAP) sanityCheck(nbSol int) {
+	if nbSol != len(q.left) {
 		panic("different number of solution variables than left polynomials")
 	}
 
-	if len(sol) != len(q.right) {
+	if nbSol != len(q.right) {
 		panic("different numberof solution variables than right polynomials")
 	}
 
-	if len(sol) != len(q.out) {
+	if nbSol != len(q.out) {
 		panic("different numbers of solutions variables than out polynomials")
 	}
 }
@@ -2,6 +2,7 @@ package playsnark
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -74,7 +75,31 @@ func TestQAPInterpolate(t *testing.T) {
 	r1cs := createR1CS()
 	_ = createWitness(r1cs)
 	fmt.Println(r1cs.out)
-	polys := qapInterpolate(r1cs.out)
+	polys := qapInterpolate(BLS12381, r1cs.out)
 	fmt.Println(polys)
 
 }
+
+func TestNewQAP(t *testing.T) {
+	for _, c := range Curves {
+		// x * (r - 1) = out where r is the order of the field, i.e. out = -x
+		minusOne := c.ElementFromBig(new(big.Int).Sub(c.Modulus(), big.NewInt(1)))
+		require.True(t, minusOne.Equal(c.Element(-1)))
+		require.Equal(t, c.Modulus(), new(big.Int).Add(c.BigInt(minusOne), big.NewInt(1)))
+		qap := NewQAP(c, 3, 3, []Constraint{{
+			Left:  map[int]Element{1: c.Element(1)},
+			Right: map[int]Element{0: minusOne},
+			Out:   map[int]Element{2: c.Element(1)},
+		}})
+		require.True(t, qap.IsValidElements([]Element{c.Element(1), c.Element(7), c.Element(-7)}))
+		require.False(t, qap.IsValidElements([]Element{c.Element(1), c.Element(7), c.Element(7)}))
+
+		// the same polynomials as ToQAPWith
+		r1cs := createR1CS()
+		qap = ToQAPWith(c, r1cs)
+		for i, p := range qapInterpolate(c, r1cs.left) {
+			require.True(t, p.Normalize().Equal(qap.left[i].Normalize()))
+		}
+		require.True(t, qap.IsValidElements(c.Elements(createWitness(r1cs))))
+	}
+}
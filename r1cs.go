@@ -1,5 +1,12 @@
 package playsnark
 
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
 // let's construct the r1cs matrix A_l, A_r A_o for the equation
 // x^3 + x + 5 = 35
 
@@ -124,6 +131,123 @@ func (r *R1CS) NewVar(name string) {
 	r.mergeVars()
 }
 
+// Inputs returns the names of the input variables
+func (r *R1CS) Inputs() []string {
+	return r.inputs
+}
+
+// Outputs returns the names of the output variables
+func (r *R1CS) Outputs() []string {
+	return r.outputs
+}
+
+// Intermediates returns the names of the intermediate variables
+func (r *R1CS) Intermediates() []string {
+	return r.intermediates
+}
+
+// NbConstraints returns the number of constraints, the rows of the matrices
+func (r *R1CS) NbConstraints() int {
+	return len(r.left)
+}
+
+// Solution returns the solution vector for the values of all the variables
+// given by name, "const" being always 1.
+func (r *R1CS) Solution(values map[string]Value) (Vector, error) {
+	return r.assign(r.vars, values)
+}
+
+// PublicValues returns "const" followed by the values of the inputs and
+// outputs given by name, as expected by the verifiers. The other values are
+// ignored.
+func (r *R1CS) PublicValues(values map[string]Value) (Vector, error) {
+	return r.assign(r.vars[:r.nbIO()], values)
+}
+
+func (r *R1CS) assign(vars Variables, values map[string]Value) (Vector, error) {
+	sol := make(Vector, len(vars))
+	for i, v := range vars {
+		if i == 0 {
+			sol[i] = 1
+			continue
+		}
+		value, ok := values[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for variable %q", v.Name)
+		}
+		sol[i] = value
+	}
+	return sol, nil
+}
+
+// r1csJSON is the JSON representation of a R1CS
+type r1csJSON struct {
+	Inputs        []string `json:"inputs"`
+	Outputs       []string `json:"outputs"`
+	Intermediates []string `json:"intermediates"`
+	Left          Matrix   `json:"left"`
+	Right         Matrix   `json:"right"`
+	Out           Matrix   `json:"out"`
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (r R1CS) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r1csJSON{
+		Inputs:        r.inputs,
+		Outputs:       r.outputs,
+		Intermediates: r.intermediates,
+		Left:          r.left,
+		Right:         r.right,
+		Out:           r.out,
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. It checks that
+// the matrices have one column per variable.
+func (r *R1CS) UnmarshalJSON(buff []byte) error {
+	var c r1csJSON
+	if err := json.Unmarshal(buff, &c); err != nil {
+		return err
+	}
+	res := R1CS{
+		inputs:        c.Inputs,
+		outputs:       c.Outputs,
+		intermediates: c.Intermediates,
+		left:          c.Left,
+		right:         c.Right,
+		out:           c.Out,
+	}
+	res.mergeVars()
+	if len(res.left) == 0 || len(res.left) != len(res.right) || len(res.left) != len(res.out) {
+		return fmt.Errorf("r1cs: invalid number of constraints")
+	}
+	for _, m := range []Matrix{res.left, res.right, res.out} {
+		for _, row := range m {
+			if len(row) != len(res.vars) {
+				return fmt.Errorf("r1cs: constraint with %d variables instead of %d", len(row), len(res.vars))
+			}
+		}
+	}
+	*r = res
+	return nil
+}
+
+// appendTo binds the transcript to the R1CS by absorbing the non zero entries
+// of its matrices
+func (r *R1CS) appendTo(tr *transcript.Transcript) {
+	for _, matrix := range []Matrix{r.left, r.right, r.out} {
+		for i := range matrix {
+			for j, v := range matrix[i] {
+				if v != 0 {
+					tr.AppendUint64("i", uint64(i))
+					tr.AppendUint64("j", uint64(j))
+					tr.AppendScalar("v", v.ToFieldElement())
+				}
+			}
+		}
+	}
+}
+
 // mergeVars ensure that the variables are in order as in the following:
 // - first the "const" variable
 // - then the inputs variables
@@ -173,6 +297,26 @@ func (r *R1CS) AddConst(var1 string, add int, out string) {
 	r.out = append(r.out, r.vars.ConstraintOn(out))
 }
 
+// LinearCombination maps the names of variables to their coefficients, the
+// constant term being the coefficient of "const"
+type LinearCombination map[string]Value
+
+// AddConstraint wires the linear combinations of the variables such that
+// left * right = out. All the variables must be declared before.
+func (r *R1CS) AddConstraint(left, right, out LinearCombination) {
+	r.left = append(r.left, r.row(left))
+	r.right = append(r.right, r.row(right))
+	r.out = append(r.out, r.row(out))
+}
+
+func (r *R1CS) row(lc LinearCombination) Vector {
+	row := make(Vector, len(r.vars))
+	for name, coeff := range lc {
+		row[r.vars.IndexOf(name)] += coeff
+	}
+	return row
+}
+
 // createR1CS returns the R1CS for the problem we consider
 // x^3 + x + 5 = 35 using the variables described as in "createVariables"
 func createR1CS() R1CS {
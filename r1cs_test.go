@@ -1,6 +1,7 @@
 package playsnark
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -28,3 +29,51 @@ func TestR1CSEquation(t *testing.T) {
 	require.Len(t, r1cs.left[0], len(s))
 	fmt.Println(r1cs.right)
 }
+
+func TestR1CSJSON(t *testing.T) {
+	r := createR1CS()
+	buff, err := json.Marshal(r)
+	require.NoError(t, err)
+	var r2 R1CS
+	require.NoError(t, json.Unmarshal(buff, &r2))
+	require.Equal(t, r, r2)
+	require.Equal(t, 4, r2.NbConstraints())
+
+	// one column is missing in the first constraint
+	r.left[0] = r.left[0][1:]
+	buff, err = json.Marshal(r)
+	require.NoError(t, err)
+	require.Error(t, json.Unmarshal(buff, &r2))
+}
+
+func TestR1CSSolution(t *testing.T) {
+	r := createR1CS()
+	values := map[string]Value{"x": 3, "out": 35, "u": 9, "v": 27, "w": 30}
+	sol, err := r.Solution(values)
+	require.NoError(t, err)
+	require.Equal(t, createWitness(r), sol)
+	io, err := r.PublicValues(values)
+	require.NoError(t, err)
+	require.Equal(t, Vector{1, 3, 35}, io)
+
+	delete(values, "u")
+	_, err = r.Solution(values)
+	require.Error(t, err)
+	_, err = r.PublicValues(values)
+	require.NoError(t, err)
+}
+
+func TestR1CSAddConstraint(t *testing.T) {
+	// x^3 + x + 5 = out with two constraints thanks to the linear
+	// combinations: u = x * x and (u + 1) * x + 5 = out
+	c := NewR1CS()
+	c.NewInput("x")
+	c.NewOutput("out")
+	c.NewVar("u")
+	c.AddConstraint(LinearCombination{"x": 1}, LinearCombination{"x": 1}, LinearCombination{"u": 1})
+	c.AddConstraint(LinearCombination{"u": 1, "const": 1}, LinearCombination{"x": 1}, LinearCombination{"out": 1, "const": -5})
+	require.Equal(t, 2, c.NbConstraints())
+	qap := ToQAP(c)
+	require.True(t, qap.IsValid(Vector{1, 3, 35, 9}))
+	require.False(t, qap.IsValid(Vector{1, 3, 36, 9}))
+}
@@ -0,0 +1,176 @@
+// Package solidity generates Solidity contracts verifying proofs of the
+// playsnark package on Ethereum, along with the calldata to call them.
+//
+// The contracts rely on the precompiles of the EVM for the BN254 curve: 0x06
+// for the addition and 0x07 for the scalar multiplication in G1, 0x08 for the
+// pairing check. Only proofs on BN254 can thus be verified on chain.
+package solidity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"text/template"
+
+	"github.com/nikkolasg/playsnark"
+	"golang.org/x/crypto/sha3"
+)
+
+// ScalarField is the order of the scalar field of BN254: the public inputs
+// must be lower
+var ScalarField, _ = new(big.Int).SetString("21888242871839275222246405745257275088548364400416034343698204186575808495617", 10)
+
+// BaseField is the order of the base field of BN254, over which the
+// coordinates of the points are defined
+var BaseField, _ = new(big.Int).SetString("21888242871839275222246405745257275088696311157297823662689037894645226208583", 10)
+
+// g1Point contains the affine coordinates of a point of G1
+type g1Point struct {
+	X, Y *big.Int
+}
+
+// g2Point contains the affine coordinates of a point of G2, each coordinate
+// being an element a * i + b of the quadratic extension written [a, b] as
+// expected by the pairing precompile
+type g2Point struct {
+	X, Y [2]*big.Int
+}
+
+// groth16Template is filled with the verifying key
+type groth16Template struct {
+	Alpha     g1Point
+	Beta      g2Point
+	Gamma     g2Point
+	Delta     g2Point
+	IC        []g1Point
+	NbInputs  int
+	BaseField *big.Int
+	Field     *big.Int
+}
+
+// ExportGroth16 writes the source of a Solidity contract verifying Groth16
+// proofs for the verifying key, which is embedded in the contract. The key
+// must be on BN254 and the circuit must have at least one input or output.
+// The contract exposes
+//
+//	verifyProof(uint256[2] a, uint256[2][2] b, uint256[2] c, uint256[n] input)
+//
+// where input contains the n inputs and outputs, without "const".
+func ExportGroth16(w io.Writer, vk playsnark.Groth16VerifyingKey) error {
+	if vk.Curve.Name != playsnark.BN254.Name {
+		return fmt.Errorf("solidity: verifying key on %s instead of BN254", vk.Curve.Name)
+	}
+	if len(vk.IoLP) < 2 {
+		return errors.New("solidity: the circuit has no public input")
+	}
+	var err error
+	t := groth16Template{
+		NbInputs:  len(vk.IoLP) - 1,
+		BaseField: BaseField,
+		Field:     ScalarField,
+	}
+	if t.Alpha, err = encodeG1(vk.Alpha); err != nil {
+		return err
+	}
+	for _, p := range []struct {
+		dst *g2Point
+		src playsnark.G2
+	}{{&t.Beta, vk.Beta2}, {&t.Gamma, vk.Gamma}, {&t.Delta, vk.Delta2}} {
+		if *p.dst, err = encodeG2(p.src); err != nil {
+			return err
+		}
+	}
+	for _, p := range vk.IoLP {
+		ic, err := encodeG1(p)
+		if err != nil {
+			return err
+		}
+		t.IC = append(t.IC, ic)
+	}
+	tmpl, err := template.New("groth16").Parse(groth16Contract)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, t)
+}
+
+// Groth16Calldata returns the calldata calling verifyProof for the proof and
+// the public values, given as for playsnark.Groth16Verify: "const" first, then
+// the inputs and outputs. The calldata is the function selector followed by
+// the ABI encoding of the arguments, 32 bytes big endian words since all the
+// arrays have a fixed size.
+func Groth16Calldata(proof playsnark.Groth16Proof, io playsnark.Vector) ([]byte, error) {
+	if len(io) < 2 || io[0] != 1 {
+		return nil, errors.New("solidity: public values must start with const = 1")
+	}
+	a, err := encodeG1(proof.A)
+	if err != nil {
+		return nil, err
+	}
+	b, err := encodeG2(proof.B)
+	if err != nil {
+		return nil, err
+	}
+	c, err := encodeG1(proof.C)
+	if err != nil {
+		return nil, err
+	}
+	inputs := io[1:]
+	signature := fmt.Sprintf("verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[%d])", len(inputs))
+	var out bytes.Buffer
+	out.Write(selector(signature))
+	words := []*big.Int{a.X, a.Y, b.X[0], b.X[1], b.Y[0], b.Y[1], c.X, c.Y}
+	for _, v := range inputs {
+		words = append(words, new(big.Int).Mod(big.NewInt(int64(v)), ScalarField))
+	}
+	for _, w := range words {
+		var buff [32]byte
+		out.Write(w.FillBytes(buff[:]))
+	}
+	return out.Bytes(), nil
+}
+
+// selector returns the first 4 bytes of the Keccak-256 hash of the signature
+func selector(signature string) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(signature))
+	return h.Sum(nil)[:4]
+}
+
+// encodeG1 returns the affine coordinates of the point, (0,0) being the point
+// at infinity as for the precompiles
+func encodeG1(p playsnark.G1) (g1Point, error) {
+	buff, err := p.MarshalBinary()
+	if err != nil {
+		return g1Point{}, err
+	}
+	if len(buff) != 64 {
+		return g1Point{}, errors.New("solidity: point not on BN254")
+	}
+	return g1Point{
+		X: new(big.Int).SetBytes(buff[:32]),
+		Y: new(big.Int).SetBytes(buff[32:]),
+	}, nil
+}
+
+// encodeG2 returns the affine coordinates of the point: the BN254 encoding
+// already orders the coordinates as the pairing precompile expects them
+func encodeG2(p playsnark.G2) (g2Point, error) {
+	buff, err := p.MarshalBinary()
+	if err != nil {
+		return g2Point{}, err
+	}
+	if len(buff) != 128 {
+		return g2Point{}, errors.New("solidity: point not on BN254")
+	}
+	var words [4]*big.Int
+	for i := range words {
+		words[i] = new(big.Int).SetBytes(buff[32*i : 32*(i+1)])
+	}
+	return g2Point{
+		X: [2]*big.Int{words[0], words[1]},
+		Y: [2]*big.Int{words[2], words[3]},
+	}, nil
+}
@@ -0,0 +1,170 @@
+package solidity
+
+import (
+	"bytes"
+	"encoding/hex"
+	"flag"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nikkolasg/playsnark"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// golden compares the output to the golden file, or updates it
+func golden(t *testing.T, name string, output []byte) {
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, ioutil.WriteFile(path, output, 0644))
+	}
+	expected, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(output))
+}
+
+// g1 and g2 return k times the generators, to get deterministic keys
+func g1(k int64) playsnark.G1 {
+	return playsnark.BN254.NewG1().Mul(playsnark.BN254.Element(playsnark.Value(k)), nil)
+}
+
+func g2(k int64) playsnark.G2 {
+	return playsnark.BN254.NewG2().Mul(playsnark.BN254.Element(playsnark.Value(k)), nil)
+}
+
+func testVerifyingKey() playsnark.Groth16VerifyingKey {
+	return playsnark.Groth16VerifyingKey{
+		Curve:  playsnark.BN254,
+		Alpha:  g1(2),
+		Beta2:  g2(3),
+		Gamma:  g2(5),
+		Delta2: g2(7),
+		IoLP:   []playsnark.G1{g1(11), g1(13), g1(17)},
+	}
+}
+
+func TestEncodeGenerators(t *testing.T) {
+	p, err := encodeG1(g1(1))
+	require.NoError(t, err)
+	require.Equal(t, "1", p.X.String())
+	require.Equal(t, "2", p.Y.String())
+	// generator of G2 as used by the Ethereum precompiles (EIP-197)
+	q, err := encodeG2(g2(1))
+	require.NoError(t, err)
+	require.Equal(t, "11559732032986387107991004021392285783925812861821192530917403151452391805634", q.X[0].String())
+	require.Equal(t, "10857046999023057135944570762232829481370756359578518086990519993285655852781", q.X[1].String())
+	require.Equal(t, "4082367875863433681332203403145435568316851327593401208105741076214120093531", q.Y[0].String())
+	require.Equal(t, "8495653923123431417604973247489272438418190587263600148770280649306958101930", q.Y[1].String())
+	// the point at infinity is (0,0)
+	p, err = encodeG1(playsnark.BN254.NewG1().Null())
+	require.NoError(t, err)
+	require.Zero(t, p.X.Sign())
+	require.Zero(t, p.Y.Sign())
+
+	_, err = encodeG1(playsnark.BLS12381.NewG1())
+	require.Error(t, err)
+}
+
+func TestSelector(t *testing.T) {
+	require.Equal(t, "a9059cbb", hex.EncodeToString(selector("transfer(address,uint256)")))
+}
+
+func TestExportGroth16(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, ExportGroth16(&out, testVerifyingKey()))
+	golden(t, "Groth16Verifier.sol", out.Bytes())
+	require.Contains(t, out.String(), "uint256[2] memory input")
+
+	// only BN254 keys can be verified on chain
+	vk := testVerifyingKey()
+	vk.Curve = playsnark.BLS12381
+	require.Error(t, ExportGroth16(&out, vk))
+	vk = testVerifyingKey()
+	vk.IoLP = vk.IoLP[:1]
+	require.Error(t, ExportGroth16(&out, vk))
+}
+
+func TestGroth16Calldata(t *testing.T) {
+	proof := playsnark.Groth16Proof{A: g1(19), B: g2(23), C: g1(29)}
+	calldata, err := Groth16Calldata(proof, playsnark.Vector{1, 3, -1})
+	require.NoError(t, err)
+	// selector, 8 words for the proof and 2 for the inputs
+	require.Len(t, calldata, 4+10*32)
+	require.Equal(t, selector("verifyProof(uint256[2],uint256[2][2],uint256[2],uint256[2])"), calldata[:4])
+	// -1 is encoded modulo the order of the scalar field
+	last := new(big.Int).SetBytes(calldata[4+9*32:])
+	require.Equal(t, new(big.Int).Sub(ScalarField, big.NewInt(1)), last)
+	golden(t, "groth16_calldata.hex", []byte(hex.EncodeToString(calldata)+"\n"))
+
+	_, err = Groth16Calldata(proof, playsnark.Vector{3, 35})
+	require.Error(t, err)
+}
+
+// cubicCircuit returns the circuit x^3 + x + 5 = out and its solution for x = 3
+func cubicCircuit() (playsnark.R1CS, playsnark.Vector) {
+	c := playsnark.NewR1CS()
+	c.NewInput("x")
+	c.NewOutput("out")
+	c.NewVar("u")
+	c.NewVar("v")
+	c.NewVar("w")
+	c.Mul("x", "x", "u")
+	c.Mul("u", "x", "v")
+	c.Add("v", "x", "w")
+	c.AddConst("w", 5, "out")
+	// const, x, out, u, v, w
+	return c, playsnark.Vector{1, 3, 35, 9, 27, 30}
+}
+
+// verifyCalldata runs the same checks as the generated contract on the
+// calldata, with the BN254 curve instead of the precompiles
+func verifyCalldata(t *testing.T, vk playsnark.Groth16VerifyingKey, calldata []byte) bool {
+	c := playsnark.BN254
+	word := func(i int) []byte {
+		return calldata[4+32*i : 4+32*(i+1)]
+	}
+	point := func(p playsnark.G1, from, to int) playsnark.G1 {
+		var buff []byte
+		for i := from; i < to; i++ {
+			buff = append(buff, word(i)...)
+		}
+		require.NoError(t, p.UnmarshalBinary(buff))
+		return p
+	}
+	a := point(c.NewG1(), 0, 2)
+	b := point(c.NewG2(), 2, 6)
+	cc := point(c.NewG1(), 6, 8)
+	vkX := vk.IoLP[0].Clone()
+	for i := range vk.IoLP[1:] {
+		input := c.NewElement().SetBytes(word(8 + i))
+		vkX = vkX.Add(vkX, c.NewG1().Mul(input, vk.IoLP[i+1]))
+	}
+	res := c.Pair(c.NewG1().Neg(a), b)
+	res = res.Add(res, c.Pair(vk.Alpha, vk.Beta2))
+	res = res.Add(res, c.Pair(vkX, vk.Gamma))
+	res = res.Add(res, c.Pair(cc, vk.Delta2))
+	return res.Equal(c.NewGT())
+}
+
+func TestGroth16CalldataProof(t *testing.T) {
+	r1cs, sol := cubicCircuit()
+	qap := playsnark.ToQAPWith(playsnark.BN254, r1cs)
+	setup := playsnark.NewGroth16TrustedSetup(qap)
+	vk := setup.VerifyingKey()
+	proof := playsnark.Groth16Prove(setup, qap, sol)
+	require.True(t, playsnark.Groth16Verify(setup, qap, proof, sol[:3]))
+
+	var out strings.Builder
+	require.NoError(t, ExportGroth16(&out, vk))
+	calldata, err := Groth16Calldata(proof, sol[:3])
+	require.NoError(t, err)
+	require.True(t, verifyCalldata(t, vk, calldata))
+
+	calldata, err = Groth16Calldata(proof, playsnark.Vector{1, 3, 36})
+	require.NoError(t, err)
+	require.False(t, verifyCalldata(t, vk, calldata))
+}
@@ -0,0 +1,136 @@
+package solidity
+
+// groth16Contract is the template of the Groth16 verifier. The verification
+// equation e(A,B) = e(alpha,beta) * e(vk_x,gamma) * e(C,delta) is checked as
+// e(-A,B) * e(alpha,beta) * e(vk_x,gamma) * e(C,delta) = 1 with a single call
+// to the pairing precompile.
+const groth16Contract = `// SPDX-License-Identifier: MIT
+// Code generated by playsnark. DO NOT EDIT.
+pragma solidity ^0.8.0;
+
+/// @title Groth16 verifier on BN254
+/// @notice The verifying key is embedded in the contract
+contract Groth16Verifier {
+    // order of the scalar field
+    uint256 constant SCALAR_FIELD = {{.Field}};
+    // order of the base field
+    uint256 constant BASE_FIELD = {{.BaseField}};
+
+    struct G1Point {
+        uint256 X;
+        uint256 Y;
+    }
+
+    // coordinates of G2 are written [a, b] for a * i + b
+    struct G2Point {
+        uint256[2] X;
+        uint256[2] Y;
+    }
+
+    struct VerifyingKey {
+        G1Point alpha;
+        G2Point beta;
+        G2Point gamma;
+        G2Point delta;
+        G1Point[] ic;
+    }
+
+    function verifyingKey() internal pure returns (VerifyingKey memory vk) {
+        vk.alpha = G1Point({{.Alpha.X}}, {{.Alpha.Y}});
+        vk.beta = G2Point(
+            [{{index .Beta.X 0}}, {{index .Beta.X 1}}],
+            [{{index .Beta.Y 0}}, {{index .Beta.Y 1}}]
+        );
+        vk.gamma = G2Point(
+            [{{index .Gamma.X 0}}, {{index .Gamma.X 1}}],
+            [{{index .Gamma.Y 0}}, {{index .Gamma.Y 1}}]
+        );
+        vk.delta = G2Point(
+            [{{index .Delta.X 0}}, {{index .Delta.X 1}}],
+            [{{index .Delta.Y 0}}, {{index .Delta.Y 1}}]
+        );
+        vk.ic = new G1Point[]({{len .IC}});
+{{- range $i, $p := .IC}}
+        vk.ic[{{$i}}] = G1Point({{$p.X}}, {{$p.Y}});
+{{- end}}
+    }
+
+    /// @return the opposite of the point p
+    function negate(G1Point memory p) internal pure returns (G1Point memory) {
+        if (p.X == 0 && p.Y == 0) {
+            return G1Point(0, 0);
+        }
+        return G1Point(p.X, BASE_FIELD - (p.Y % BASE_FIELD));
+    }
+
+    /// @return r the sum of p1 and p2, with the precompile 0x06
+    function add(G1Point memory p1, G1Point memory p2) internal view returns (G1Point memory r) {
+        uint256[4] memory input = [p1.X, p1.Y, p2.X, p2.Y];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 0x06, input, 0x80, r, 0x40)
+        }
+        require(success, "ec add failed");
+    }
+
+    /// @return r the point p multiplied by s, with the precompile 0x07
+    function mul(G1Point memory p, uint256 s) internal view returns (G1Point memory r) {
+        uint256[3] memory input = [p.X, p.Y, s];
+        bool success;
+        assembly {
+            success := staticcall(gas(), 0x07, input, 0x60, r, 0x40)
+        }
+        require(success, "ec mul failed");
+    }
+
+    /// @return true if the product of the pairings e(p1[i], p2[i]) is one,
+    /// with the precompile 0x08
+    function pairing(G1Point[4] memory p1, G2Point[4] memory p2) internal view returns (bool) {
+        uint256[24] memory input;
+        for (uint256 i = 0; i < 4; i++) {
+            input[i * 6 + 0] = p1[i].X;
+            input[i * 6 + 1] = p1[i].Y;
+            input[i * 6 + 2] = p2[i].X[0];
+            input[i * 6 + 3] = p2[i].X[1];
+            input[i * 6 + 4] = p2[i].Y[0];
+            input[i * 6 + 5] = p2[i].Y[1];
+        }
+        uint256[1] memory out;
+        bool success;
+        assembly {
+            success := staticcall(gas(), 0x08, input, 0x300, out, 0x20)
+        }
+        require(success, "pairing failed");
+        return out[0] == 1;
+    }
+
+    /// @notice Verifies a Groth16 proof
+    /// @param input the public inputs and outputs of the circuit
+    /// @return true if the proof is valid
+    function verifyProof(
+        uint256[2] memory a,
+        uint256[2][2] memory b,
+        uint256[2] memory c,
+        uint256[{{.NbInputs}}] memory input
+    ) public view returns (bool) {
+        VerifyingKey memory vk = verifyingKey();
+        // vk_x = ic[0] + SUM input[i] * ic[i+1]
+        G1Point memory vkX = vk.ic[0];
+        for (uint256 i = 0; i < input.length; i++) {
+            require(input[i] < SCALAR_FIELD, "input not in the scalar field");
+            vkX = add(vkX, mul(vk.ic[i + 1], input[i]));
+        }
+        G1Point[4] memory p1;
+        G2Point[4] memory p2;
+        p1[0] = negate(G1Point(a[0], a[1]));
+        p2[0] = G2Point(b[0], b[1]);
+        p1[1] = vk.alpha;
+        p2[1] = vk.beta;
+        p1[2] = vkX;
+        p2[2] = vk.gamma;
+        p1[3] = G1Point(c[0], c[1]);
+        p2[3] = vk.delta;
+        return pairing(p1, p2);
+    }
+}
+`
@@ -0,0 +1,355 @@
+package playsnark
+
+import (
+	"fmt"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/sumcheck"
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements Spartan https://eprint.iacr.org/2019/550.pdf
+// Spartan proves the satisfiability of a R1CS without any trusted setup: the
+// matrices A, B, C (left, right and out) and the vector z of all the
+// variables are seen as multilinear extensions over the boolean hypercube,
+// A(x,y) being the entry of the row x and column y. The R1CS is satisfied if
+// and only if for all rows x
+//
+//	F(x) = (SUM_y A(x,y) * z(y)) * (SUM_y B(x,y) * z(y)) - SUM_y C(x,y) * z(y) = 0
+//
+// which holds with high probability if SUM_x eq(tau,x) * F(x) = 0 for a random
+// tau. The prover shows it with a first sumcheck, which reduces it to the
+// evaluations Az(rx), Bz(rx) and Cz(rx) for a random rx. The three are
+// combined with random coefficients and proven with a second sumcheck on
+//
+//	SUM_y (rA * A(rx,y) + rB * B(rx,y) + rC * C(rx,y)) * z(y)
+//
+// which reduces to a single evaluation z(ry). The verifier evaluates the
+// matrices at (rx,ry) itself and gets z(ry) from the public values and a
+// commitment to the witness.
+//
+// The vector z is laid out as [public values | witness] where each half has
+// the same power of two length, such that
+//
+//	z(ry) = (1 - ry_top) * x(ry') + ry_top * w(ry')
+//
+// where ry_top is the last coordinate of ry, ry' the others and x the
+// extension of the public values. The witness is committed with a simple
+// Hyrax-style commitment: its evaluations are arranged as a matrix whose rows
+// are committed with Pedersen vector commitments, and an evaluation is opened
+// by sending the combination of the rows with the Lagrange basis of the row
+// coordinates. This opening has the size of a row, i.e. the square root of the
+// witness, and reveals a combination of the witness: this simplified version
+// is not zero knowledge.
+
+// SpartanSetup contains the public parameters of Spartan for a R1CS. There is
+// no trapdoor: the generators are derived by hashing.
+type SpartanSetup struct {
+	// Curve is the curve of the commitments to the witness
+	Curve Curve
+	// Gs are the generators of the Pedersen vector commitments to the rows of
+	// the witness and H the generator of the blinding factor
+	Gs []G1
+	H  G1
+	// log2 of the number of rows and columns of the padded matrices
+	rowVars int
+	colVars int
+	// number of columns of the witness matrix
+	witnessCols int
+}
+
+// NewSpartanSetup returns the public parameters for the R1CS on the default
+// curve
+func NewSpartanSetup(r R1CS) SpartanSetup {
+	return NewSpartanSetupWith(BLS12381, r)
+}
+
+// NewSpartanSetupWith returns the public parameters for the R1CS on the given
+// curve
+func NewSpartanSetupWith(c Curve, r R1CS) SpartanSetup {
+	half := nextPowerOfTwo(len(r.vars) - r.nbIO())
+	if p := nextPowerOfTwo(r.nbIO()); p > half {
+		half = p
+	}
+	s := SpartanSetup{Curve: c}
+	s.rowVars = log2(nextPowerOfTwo(len(r.left)))
+	s.colVars = log2(half) + 1
+	// the witness matrix has 2^ceil(v/2) columns with v = log2(half)
+	v := s.colVars - 1
+	s.witnessCols = 1 << uint((v+1)/2)
+	for i := 0; i < s.witnessCols; i++ {
+		s.Gs = append(s.Gs, hashToG1(c, fmt.Sprintf("spartan.G.%d", i)))
+	}
+	s.H = hashToG1(c, "spartan.H")
+	return s
+}
+
+// SpartanProof contains the commitment to the witness, the two sumchecks and
+// the opening of the witness
+type SpartanProof struct {
+	// Commitments to the rows of the witness matrix
+	W []G1
+	// First sumcheck and the claimed Az(rx), Bz(rx) and Cz(rx)
+	Outer sumcheck.Proof
+	AEval Element
+	BEval Element
+	CEval Element
+	// Second sumcheck and the opening of w(ry'): the combination of the rows
+	// and of their blinding factors
+	Inner    sumcheck.Proof
+	Row      []Element
+	Blinding Element
+}
+
+// SpartanProve returns a proof that the prover knows a solution of the R1CS.
+// As for Groth16, the solution contains all the variables in order, starting
+// with "const". If it doesn't satisfy the R1CS, the first sumcheck fails and
+// the proof is invalid.
+func SpartanProve(s SpartanSetup, r R1CS, sol Vector) SpartanProof {
+	curve := s.Curve
+	var proof SpartanProof
+	nbIO := r.nbIO()
+	z := s.zVector(r, sol)
+	half := len(z) / 2
+	witness := z[half:]
+
+	// commit to the rows of the witness matrix
+	nbRows := half / s.witnessCols
+	blindings := make([]Element, nbRows)
+	for i := 0; i < nbRows; i++ {
+		blindings[i] = curve.NewElement().Pick(random.New())
+		row := witness[i*s.witnessCols : (i+1)*s.witnessCols]
+		proof.W = append(proof.W, s.commitRow(row, blindings[i]))
+	}
+	tr := s.transcript(r, sol[:nbIO], proof.W)
+
+	// first sumcheck: SUM_x eq(tau,x) * (Az(x) * Bz(x) - Cz(x)) = 0
+	tau := spartanChallenges(curve, tr, "tau", s.rowVars)
+	var mz [3]sumcheck.MLE
+	for m, matrix := range []Matrix{r.left, r.right, r.out} {
+		var evals []Element
+		for _, v := range matrix.Mul(sol) {
+			evals = append(evals, curve.Element(v))
+		}
+		mz[m] = s.rowMLE(evals)
+	}
+	negC := make(sumcheck.MLE, len(mz[2]))
+	for i := range negC {
+		negC[i] = curve.NewElement().Neg(mz[2][i])
+	}
+	eq := sumcheck.EqMLEWith(curve.Suite.G1(), tau)
+	var rx []Element
+	proof.Outer, rx, _ = sumcheck.Prove(tr, sumcheck.Polynomial{{eq, mz[0], mz[1]}, {eq, negC}})
+	proof.AEval = mz[0].Evaluate(rx)
+	proof.BEval = mz[1].Evaluate(rx)
+	proof.CEval = mz[2].Evaluate(rx)
+	coeffs := spartanEvals(curve, tr, &proof)
+
+	// second sumcheck: SUM_y M(rx,y) * z(y) with M = rA * A + rB * B + rC * C
+	mrx := s.combinedRow(r, rx, coeffs)
+	var ry []Element
+	proof.Inner, ry, _ = sumcheck.Prove(tr, sumcheck.Polynomial{{mrx, sumcheck.NewMLE(z)}})
+
+	// open w(ry'): the rows are combined with the Lagrange basis of the row
+	// coordinates of ry'
+	rowCoords := ry[log2(s.witnessCols) : s.colVars-1]
+	eqRows := sumcheck.EqMLEWith(curve.Suite.G1(), rowCoords)
+	proof.Blinding = curve.NewElement()
+	for j := 0; j < s.witnessCols; j++ {
+		acc := curve.NewElement()
+		for i := 0; i < nbRows; i++ {
+			acc = acc.Add(acc, curve.NewElement().Mul(eqRows[i], witness[i*s.witnessCols+j]))
+		}
+		proof.Row = append(proof.Row, acc)
+	}
+	for i := 0; i < nbRows; i++ {
+		proof.Blinding = proof.Blinding.Add(proof.Blinding, curve.NewElement().Mul(eqRows[i], blindings[i]))
+	}
+	return proof
+}
+
+// SpartanVerify returns true if the proof is valid for the public values:
+// "const" followed by the inputs and outputs.
+func SpartanVerify(s SpartanSetup, r R1CS, p SpartanProof, io Vector) bool {
+	curve := s.Curve
+	half := 1 << uint(s.colVars-1)
+	nbRows := half / s.witnessCols
+	if len(io) != r.nbIO() || len(p.W) != nbRows || len(p.Row) != s.witnessCols {
+		return false
+	}
+	tr := s.transcript(r, io, p.W)
+	tau := spartanChallenges(curve, tr, "tau", s.rowVars)
+	rx, claim, err := sumcheck.Verify(tr, curve.NewElement(), s.rowVars, 3, p.Outer)
+	if err != nil {
+		return false
+	}
+	// eq(tau,rx) * (Az(rx) * Bz(rx) - Cz(rx))
+	expected := curve.NewElement().Mul(p.AEval, p.BEval)
+	expected = expected.Sub(expected, p.CEval)
+	if !claim.Equal(expected.Mul(expected, sumcheck.EqWith(curve.Suite.G1(), tau, rx))) {
+		return false
+	}
+	coeffs := spartanEvals(curve, tr, &p)
+	claim = curve.NewElement()
+	for m, e := range []Element{p.AEval, p.BEval, p.CEval} {
+		claim = claim.Add(claim, curve.NewElement().Mul(coeffs[m], e))
+	}
+	ry, claim, err := sumcheck.Verify(tr, claim, s.colVars, 2, p.Inner)
+	if err != nil {
+		return false
+	}
+
+	// check the opening of the witness: SUM eq(rows, i) * W_i is the
+	// commitment to the combined row
+	ryw := ry[:s.colVars-1]
+	eqRows := sumcheck.EqMLEWith(curve.Suite.G1(), ryw[log2(s.witnessCols):])
+	left := curve.NewG1().Null()
+	for i, c := range p.W {
+		left = left.Add(left, curve.NewG1().Mul(eqRows[i], c))
+	}
+	if !left.Equal(s.commitRow(p.Row, p.Blinding)) {
+		return false
+	}
+	eqCols := sumcheck.EqMLEWith(curve.Suite.G1(), ryw[:log2(s.witnessCols)])
+	w := curve.NewElement()
+	for j, v := range p.Row {
+		w = w.Add(w, curve.NewElement().Mul(eqCols[j], v))
+	}
+	// z(ry) = (1 - ry_top) * x(ry') + ry_top * w(ry')
+	var xs []Element
+	for _, v := range io {
+		xs = append(xs, curve.Element(v))
+	}
+	x := make(sumcheck.MLE, half)
+	for i := range x {
+		x[i] = curve.NewElement()
+		if i < len(xs) {
+			x[i] = xs[i]
+		}
+	}
+	top := ry[s.colVars-1]
+	zry := curve.NewElement().Mul(curve.NewElement().Sub(curve.Element(1), top), x.Evaluate(ryw))
+	zry = zry.Add(zry, curve.NewElement().Mul(top, w))
+	// the verifier evaluates the matrices by itself
+	m := s.evalMatrices(r, rx, ry, coeffs)
+	return claim.Equal(m.Mul(m, zry))
+}
+
+// zVector returns [public values | witness] with both halves padded with
+// zeros to the same power of two length
+func (s SpartanSetup) zVector(r R1CS, sol Vector) []Element {
+	curve := s.Curve
+	half := 1 << uint(s.colVars-1)
+	z := make([]Element, 2*half)
+	for i := range z {
+		z[i] = curve.NewElement()
+	}
+	for j, v := range sol {
+		z[s.column(r, j)] = curve.Element(v)
+	}
+	return z
+}
+
+// column returns the index of the variable j in z
+func (s SpartanSetup) column(r R1CS, j int) int {
+	if j < r.nbIO() {
+		return j
+	}
+	return 1<<uint(s.colVars-1) + j - r.nbIO()
+}
+
+// rowMLE returns the extension of the values over the padded rows
+func (s SpartanSetup) rowMLE(evals []Element) sumcheck.MLE {
+	m := make(sumcheck.MLE, 1<<uint(s.rowVars))
+	for i := range m {
+		m[i] = s.Curve.NewElement()
+		if i < len(evals) {
+			m[i] = evals[i]
+		}
+	}
+	return m
+}
+
+// combinedRow returns the extension of y -> SUM_M r_M * M(rx,y)
+func (s SpartanSetup) combinedRow(r R1CS, rx []Element, coeffs [3]Element) sumcheck.MLE {
+	curve := s.Curve
+	eqx := sumcheck.EqMLEWith(curve.Suite.G1(), rx)
+	out := make(sumcheck.MLE, 1<<uint(s.colVars))
+	for i := range out {
+		out[i] = curve.NewElement()
+	}
+	for m, matrix := range []Matrix{r.left, r.right, r.out} {
+		for i := range matrix {
+			for j, v := range matrix[i] {
+				if v == 0 {
+					continue
+				}
+				t := curve.NewElement().Mul(eqx[i], curve.Element(v))
+				col := s.column(r, j)
+				out[col] = out[col].Add(out[col], t.Mul(t, coeffs[m]))
+			}
+		}
+	}
+	return out
+}
+
+// evalMatrices returns SUM_M r_M * M(rx,ry) from the non zero entries
+func (s SpartanSetup) evalMatrices(r R1CS, rx, ry []Element, coeffs [3]Element) Element {
+	return s.combinedRow(r, rx, coeffs).Evaluate(ry)
+}
+
+// commitRow returns SUM v_j * G_j + blinding * H
+func (s SpartanSetup) commitRow(row []Element, blinding Element) G1 {
+	curve := s.Curve
+	c := curve.NewG1().Mul(blinding, s.H)
+	for j, v := range row {
+		c = c.Add(c, curve.NewG1().Mul(v, s.Gs[j]))
+	}
+	return c
+}
+
+// transcript returns the transcript bound to the R1CS, the public values and
+// the commitment to the witness
+func (s SpartanSetup) transcript(r R1CS, io Vector, w []G1) *transcript.Transcript {
+	tr := transcript.New("spartan")
+	tr.AppendUint64("rows", uint64(s.rowVars))
+	tr.AppendUint64("cols", uint64(s.colVars))
+	r.appendTo(tr)
+	for _, v := range io {
+		tr.AppendScalar("public", s.Curve.Element(v))
+	}
+	for _, c := range w {
+		tr.AppendPoint("w", c)
+	}
+	return tr
+}
+
+// spartanEvals absorbs the claimed evaluations of Az, Bz and Cz and returns
+// the coefficients combining them
+func spartanEvals(c Curve, tr *transcript.Transcript, p *SpartanProof) [3]Element {
+	tr.AppendScalar("az", p.AEval)
+	tr.AppendScalar("bz", p.BEval)
+	tr.AppendScalar("cz", p.CEval)
+	var coeffs [3]Element
+	for m := range coeffs {
+		coeffs[m] = tr.ChallengeScalar("r", c.Suite.G1())
+	}
+	return coeffs
+}
+
+func spartanChallenges(c Curve, tr *transcript.Transcript, label string, n int) []Element {
+	var out []Element
+	for i := 0; i < n; i++ {
+		out = append(out, tr.ChallengeScalar(label, c.Suite.G1()))
+	}
+	return out
+}
+
+// log2 returns the logarithm of a power of two
+func log2(n int) int {
+	v := 0
+	for 1<<uint(v) < n {
+		v++
+	}
+	return v
+}
@@ -0,0 +1,79 @@
+package playsnark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpartanSetup(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			s := NewSpartanSetupWith(curve, r1cs)
+			// 4 constraints, 3 public values and 3 intermediate variables
+			require.Equal(t, 2, s.rowVars)
+			require.Equal(t, 3, s.colVars)
+			require.Equal(t, 2, s.witnessCols)
+			z := s.zVector(r1cs, createWitness(r1cs))
+			require.Len(t, z, 8)
+			require.True(t, z[4].Equal(curve.NewElement().SetInt64(9)))
+			// the generators are deterministic
+			require.True(t, NewSpartanSetupWith(curve, r1cs).Gs[1].Equal(s.Gs[1]))
+			require.False(t, s.Gs[0].Equal(s.Gs[1]))
+		})
+	}
+}
+
+func TestSpartanProof(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createR1CS()
+			sol := createWitness(r1cs)
+			s := NewSpartanSetupWith(curve, r1cs)
+			io := sol[:r1cs.nbIO()]
+			proof := SpartanProve(s, r1cs, sol)
+			require.True(t, SpartanVerify(s, r1cs, proof, io))
+
+			// wrong public output
+			require.False(t, SpartanVerify(s, r1cs, proof, Vector{1, 3, 36}))
+			// missing public output
+			require.False(t, SpartanVerify(s, r1cs, proof, Vector{1, 3}))
+			// tampered evaluation and opening
+			tampered := proof
+			tampered.AEval = curve.NewElement().Add(proof.AEval, curve.Element(1))
+			require.False(t, SpartanVerify(s, r1cs, tampered, io))
+			tampered = proof
+			tampered.Row = append([]Element{curve.NewElement().Add(proof.Row[0], curve.Element(1))}, proof.Row[1:]...)
+			require.False(t, SpartanVerify(s, r1cs, tampered, io))
+			// another circuit
+			other := createLinearR1CS()
+			require.False(t, SpartanVerify(NewSpartanSetupWith(curve, other), other, proof, Vector{1, 3, 35}))
+
+			// invalid witness
+			sol[r1cs.vars.IndexOf("u")] = 10
+			proof = SpartanProve(s, r1cs, sol)
+			require.False(t, SpartanVerify(s, r1cs, proof, io))
+		})
+	}
+}
+
+func TestSpartanSameCircuitAsGroth16(t *testing.T) {
+	for _, curve := range Curves {
+		t.Run(curve.Name, func(t *testing.T) {
+			r1cs := createLinearR1CS()
+			sol := make(Vector, len(r1cs.vars))
+			for name, v := range map[string]Value{"const": 1, "x": 2, "y": 3, "a": 22, "out": 28, "b": 112} {
+				sol[r1cs.vars.IndexOf(name)] = v
+			}
+			s := NewSpartanSetupWith(curve, r1cs)
+			proof := SpartanProve(s, r1cs, sol)
+			require.True(t, SpartanVerify(s, r1cs, proof, sol[:r1cs.nbIO()]))
+
+			qap := ToQAPWith(curve, r1cs)
+			diff := qap.nbIO
+			tr := NewGroth16TrustedSetup(qap)
+			require.True(t, Groth16Verify(tr, qap, Groth16Prove(tr, qap, sol), sol[:diff]))
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package stark
+
+// AIR is an Algebraic Intermediate Representation of a computation: its
+// execution trace is a table with Width columns and Length rows, and it is
+// valid when the transition constraints hold between every pair of
+// consecutive rows and the boundary constraints fix some cells to public
+// values.
+type AIR interface {
+	// Width returns the number of columns of the trace
+	Width() int
+	// Length returns the number of rows of the trace, a power of two
+	Length() int
+	// Degree returns the maximum degree of the transition constraints in the
+	// cells of the trace
+	Degree() int
+	// Transition returns the evaluations of the transition constraints on two
+	// consecutive rows: they must all be zero for every row but the last.
+	Transition(current, next []Element) []Element
+	// Boundaries returns the cells of the trace fixed to public values
+	Boundaries() []Boundary
+}
+
+// Boundary fixes the cell of the given row and column of the trace
+type Boundary struct {
+	Column int
+	Row    int
+	Value  Element
+}
+
+// Fibonacci is the AIR of the Fibonacci sequence starting with A and B: each
+// row of the trace holds two consecutive elements (x_i, x_(i+1)) of the
+// sequence, so the transition constraints are
+//
+//	x'_0 = x_1
+//	x'_1 = x_0 + x_1
+//
+// and the second column of the last row is the public Result.
+type Fibonacci struct {
+	A, B   Element
+	Result Element
+	Steps  int
+}
+
+// NewFibonacci returns the AIR of the Fibonacci sequence with the given number
+// of rows, a power of two, along with its result
+func NewFibonacci(a, b Element, steps int) Fibonacci {
+	f := Fibonacci{A: a, B: b, Steps: steps}
+	trace := f.Trace()
+	f.Result = trace[1][steps-1]
+	return f
+}
+
+// Trace returns the execution trace as a list of columns
+func (f Fibonacci) Trace() [][]Element {
+	trace := [][]Element{make([]Element, f.Steps), make([]Element, f.Steps)}
+	a, b := f.A, f.B
+	for i := 0; i < f.Steps; i++ {
+		trace[0][i] = a
+		trace[1][i] = b
+		a, b = b, a.Add(b)
+	}
+	return trace
+}
+
+func (f Fibonacci) Width() int {
+	return 2
+}
+
+func (f Fibonacci) Length() int {
+	return f.Steps
+}
+
+func (f Fibonacci) Degree() int {
+	return 1
+}
+
+func (f Fibonacci) Transition(current, next []Element) []Element {
+	return []Element{
+		next[0].Sub(current[1]),
+		next[1].Sub(current[0].Add(current[1])),
+	}
+}
+
+func (f Fibonacci) Boundaries() []Boundary {
+	return []Boundary{
+		{Column: 0, Row: 0, Value: f.A},
+		{Column: 1, Row: 0, Value: f.B},
+		{Column: 1, Row: f.Steps - 1, Value: f.Result},
+	}
+}
@@ -0,0 +1,119 @@
+package stark
+
+// Domain is the coset Offset * <Root> of the subgroup of order Size. The
+// trace is interpolated over the subgroup itself (Offset = 1) and extended over
+// a larger coset disjoint from it, such that the vanishing polynomial of the
+// trace domain never vanishes on the evaluation domain.
+type Domain struct {
+	Size   int
+	Offset Element
+	Root   Element
+}
+
+// NewDomain returns the coset offset * <w> with w a root of unity of order
+// size
+func NewDomain(size int, offset Element) Domain {
+	return Domain{
+		Size:   size,
+		Offset: offset,
+		Root:   RootOfUnity(size),
+	}
+}
+
+// Element returns the i-th element of the domain, offset * w^i
+func (d Domain) Element(i int) Element {
+	return d.Offset.Mul(d.Root.Exp(uint64(i)))
+}
+
+// Elements returns all the elements of the domain in order
+func (d Domain) Elements() []Element {
+	xs := make([]Element, d.Size)
+	x := d.Offset
+	for i := range xs {
+		xs[i] = x
+		x = x.Mul(d.Root)
+	}
+	return xs
+}
+
+// Square returns the domain of the squares of the elements, half as big: the
+// domain of the next layer of FRI.
+func (d Domain) Square() Domain {
+	return Domain{
+		Size:   d.Size / 2,
+		Offset: d.Offset.Mul(d.Offset),
+		Root:   d.Root.Mul(d.Root),
+	}
+}
+
+// Evaluate returns the evaluations over the domain of the polynomial given by
+// its coefficients, from low to high degree. There must be at most Size of
+// them.
+func (d Domain) Evaluate(coeffs []Element) []Element {
+	if len(coeffs) > d.Size {
+		panic("too many coefficients for the domain")
+	}
+	// p(offset * x) has the coefficients c_i * offset^i
+	scaled := make([]Element, d.Size)
+	o := Element(1)
+	for i, c := range coeffs {
+		scaled[i] = c.Mul(o)
+		o = o.Mul(d.Offset)
+	}
+	return fft(scaled, d.Root)
+}
+
+// Interpolate returns the coefficients of the unique polynomial of degree
+// less than Size taking the given values over the domain
+func (d Domain) Interpolate(evals []Element) []Element {
+	if len(evals) != d.Size {
+		panic("wrong number of evaluations for the domain")
+	}
+	coeffs := fft(evals, d.Root.Inv())
+	nInv := NewElement(uint64(d.Size)).Inv()
+	oInv := d.Offset.Inv()
+	o := Element(1)
+	for i := range coeffs {
+		coeffs[i] = coeffs[i].Mul(nInv).Mul(o)
+		o = o.Mul(oInv)
+	}
+	return coeffs
+}
+
+// EvalPoly evaluates the polynomial at x with Horner's rule
+func EvalPoly(coeffs []Element, x Element) Element {
+	var res Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res = res.Mul(x).Add(coeffs[i])
+	}
+	return res
+}
+
+// fft returns the evaluations of the polynomial at the powers of the root,
+// whose order is the number of coefficients, with the recursive radix 2
+// Cooley-Tukey algorithm.
+func fft(coeffs []Element, root Element) []Element {
+	n := len(coeffs)
+	if n == 1 {
+		return []Element{coeffs[0]}
+	}
+	even := make([]Element, n/2)
+	odd := make([]Element, n/2)
+	for i := 0; i < n/2; i++ {
+		even[i] = coeffs[2*i]
+		odd[i] = coeffs[2*i+1]
+	}
+	square := root.Mul(root)
+	evenEvals := fft(even, square)
+	oddEvals := fft(odd, square)
+	// p(x) = even(x^2) + x * odd(x^2) and w^(i + n/2) = -w^i
+	out := make([]Element, n)
+	w := Element(1)
+	for i := 0; i < n/2; i++ {
+		t := w.Mul(oddEvals[i])
+		out[i] = evenEvals[i].Add(t)
+		out[i+n/2] = evenEvals[i].Sub(t)
+		w = w.Mul(root)
+	}
+	return out
+}
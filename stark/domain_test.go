@@ -0,0 +1,33 @@
+package stark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainEvaluate(t *testing.T) {
+	// 3 + 2x + x^3
+	coeffs := []Element{3, 2, 0, 1}
+	for _, offset := range []Element{1, Offset} {
+		d := NewDomain(8, offset)
+		evals := d.Evaluate(coeffs)
+		for i, x := range d.Elements() {
+			require.Equal(t, EvalPoly(coeffs, x), evals[i])
+			require.Equal(t, d.Element(i), x)
+		}
+		require.Equal(t, append(coeffs, 0, 0, 0, 0), d.Interpolate(evals))
+	}
+}
+
+func TestDomainSquare(t *testing.T) {
+	d := NewDomain(16, Offset)
+	s := d.Square()
+	require.Equal(t, 8, s.Size)
+	for i := 0; i < s.Size; i++ {
+		x := d.Element(i)
+		require.Equal(t, x.Mul(x), s.Element(i))
+		// the second half of the domain is the opposite of the first one
+		require.Equal(t, x.Neg(), d.Element(i+s.Size))
+	}
+}
@@ -0,0 +1,120 @@
+// Package stark implements a transparent, hash based proof system: the FRI
+// low degree test and a minimal STARK for computations described by an AIR
+// (Algebraic Intermediate Representation). Unlike the rest of the repository,
+// nothing here relies on pairings or on a trusted setup, only on a hash
+// function (SHA-256) used for the Merkle commitments and the Fiat-Shamir
+// transcript.
+//
+// Everything works over the Goldilocks field of order p = 2^64 - 2^32 + 1
+// whose multiplicative group contains a subgroup of order 2^32, which gives
+// domains suited to FFTs, and whose elements fit in a single machine word.
+// The challenges are drawn from the base field directly: real systems draw
+// them from an extension field since 64 bits limit the security, this
+// implementation is only meant to be read.
+package stark
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// P is the Goldilocks prime 2^64 - 2^32 + 1
+const P uint64 = 0xffffffff00000001
+
+// TwoAdicity is the largest k such that 2^k divides p - 1: the field contains
+// roots of unity of order up to 2^32.
+const TwoAdicity = 32
+
+// Generator generates the multiplicative group of the field
+const Generator Element = 7
+
+// Element is an element of the Goldilocks field, always kept reduced in [0,p)
+type Element uint64
+
+// NewElement returns v modulo p
+func NewElement(v uint64) Element {
+	return Element(v % P)
+}
+
+func (a Element) Add(b Element) Element {
+	s, carry := bits.Add64(uint64(a), uint64(b), 0)
+	// in case of carry, s + 2^64 - p is computed by wrapping around
+	if carry != 0 || s >= P {
+		s -= P
+	}
+	return Element(s)
+}
+
+func (a Element) Sub(b Element) Element {
+	if a >= b {
+		return a - b
+	}
+	return Element(uint64(a) + (P - uint64(b)))
+}
+
+func (a Element) Neg() Element {
+	return Element(0).Sub(a)
+}
+
+func (a Element) Mul(b Element) Element {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return Element(bits.Rem64(hi, lo, P))
+}
+
+// Exp returns a^e by square and multiply
+func (a Element) Exp(e uint64) Element {
+	res := Element(1)
+	for base := a; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = res.Mul(base)
+		}
+		base = base.Mul(base)
+	}
+	return res
+}
+
+// Inv returns the inverse of a by Fermat's little theorem, a^(p-2). The
+// inverse of zero is zero.
+func (a Element) Inv() Element {
+	return a.Exp(P - 2)
+}
+
+// Bytes returns the 8 bytes little endian encoding of the element
+func (a Element) Bytes() []byte {
+	var buff [8]byte
+	binary.LittleEndian.PutUint64(buff[:], uint64(a))
+	return buff[:]
+}
+
+func (a Element) String() string {
+	return fmt.Sprintf("%d", uint64(a))
+}
+
+// RootOfUnity returns a primitive n-th root of unity, n being a power of two
+// at most 2^TwoAdicity.
+func RootOfUnity(n int) Element {
+	if n <= 0 || n&(n-1) != 0 || log2(n) > TwoAdicity {
+		panic(fmt.Sprintf("no root of unity of order %d", n))
+	}
+	return Generator.Exp((P - 1) / uint64(n))
+}
+
+// log2 returns the logarithm of a power of two
+func log2(n int) int {
+	return bits.TrailingZeros(uint(n))
+}
+
+// isPowerOfTwo returns true if n is a strictly positive power of two
+func isPowerOfTwo(n int) bool {
+	return n > 0 && n&(n-1) == 0
+}
+
+// nextPowerOfTwo returns the smallest power of two greater or equal to n
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
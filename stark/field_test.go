@@ -0,0 +1,36 @@
+package stark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldArithmetic(t *testing.T) {
+	minusOne := Element(P - 1)
+	require.Equal(t, Element(0), minusOne.Add(1))
+	require.Equal(t, minusOne, Element(0).Sub(1))
+	require.Equal(t, Element(1), minusOne.Mul(minusOne))
+	require.Equal(t, minusOne, Element(1).Neg())
+	require.Equal(t, Element(0), Element(0).Neg())
+	// both operands close to p: the sum overflows 64 bits
+	require.Equal(t, Element(P-3), minusOne.Add(Element(P-2)))
+	require.Equal(t, Element(5), NewElement(P+5))
+
+	for _, a := range []Element{1, 2, 7, 1 << 40, minusOne} {
+		require.Equal(t, Element(1), a.Mul(a.Inv()))
+	}
+	// 2^64 = 2^32 - 1 mod p
+	require.Equal(t, Element(1<<32-1), Element(1<<32).Mul(1<<32))
+	require.Equal(t, Element(1<<32-1), Element(2).Exp(64))
+}
+
+func TestRootOfUnity(t *testing.T) {
+	for _, n := range []int{2, 8, 1 << 10, 1 << TwoAdicity} {
+		w := RootOfUnity(n)
+		require.Equal(t, Element(1), w.Exp(uint64(n)))
+		require.Equal(t, Element(P-1), w.Exp(uint64(n/2)))
+	}
+	require.Panics(t, func() { RootOfUnity(3) })
+	require.Panics(t, func() { RootOfUnity(1 << (TwoAdicity + 1)) })
+}
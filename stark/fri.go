@@ -0,0 +1,273 @@
+package stark
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// Implements the FRI protocol https://eccc.weizmann.ac.il/report/2017/134/
+// FRI proves that a function given by its evaluations over a domain D of size
+// N is close to a polynomial of degree less than N / Blowup. Writing
+//
+//	f(x) = f_even(x^2) + x * f_odd(x^2)
+//
+// the prover folds f with a random beta into
+//
+//	f'(x^2) = f_even(x^2) + beta * f_odd(x^2)
+//	        = (f(x) + f(-x)) / 2 + beta * (f(x) - f(-x)) / (2x)
+//
+// a function over the domain of the squares, half as big, whose degree is half
+// the degree of f. The prover commits to each layer and folds until the degree
+// is small enough to send the remaining polynomial in the clear. The verifier
+// then checks the folding at random positions: each query opens f(x) and
+// f(-x) at each layer, recomputes the folded value and compares it to the
+// opening of the next layer, and to the remaining polynomial at the end.
+//
+// Each layer is committed in a Merkle tree whose leaf j contains the pair
+// (f(x_j), f(-x_j)) with x_j the j-th element of the domain, such that both
+// values needed for folding are opened with a single path.
+
+// Params are the parameters of FRI which determine its soundness
+type Params struct {
+	// Blowup is the inverse of the rate: the ratio between the size of the
+	// domain and the degree bound, a power of two.
+	Blowup int
+	// Queries is the number of positions checked by the verifier
+	Queries int
+	// RemainderSize is the maximum number of coefficients of the polynomial
+	// sent in the clear at the end of the folding, a power of two.
+	RemainderSize int
+}
+
+// DefaultParams returns parameters giving 96 bits of conjectured security
+// from the queries, the overall security being capped by the field as
+// explained in SecurityBits.
+func DefaultParams() Params {
+	return Params{
+		Blowup:        8,
+		Queries:       32,
+		RemainderSize: 4,
+	}
+}
+
+// Validate returns an error if the parameters are not usable
+func (p Params) Validate() error {
+	if p.Blowup < 2 || !isPowerOfTwo(p.Blowup) {
+		return fmt.Errorf("fri: blowup %d is not a power of two greater than 1", p.Blowup)
+	}
+	if p.Queries < 1 {
+		return errors.New("fri: at least one query is required")
+	}
+	if !isPowerOfTwo(p.RemainderSize) {
+		return fmt.Errorf("fri: remainder size %d is not a power of two", p.RemainderSize)
+	}
+	return nil
+}
+
+// SecurityBits returns the conjectured security in bits of FRI over a domain
+// of the given size. Each query catches a cheating prover with probability
+// about 1 - 1/Blowup, which gives Queries * log2(Blowup) bits, but the
+// challenges are drawn from the 64 bits base field which caps the security to
+// about 64 - log2(size) bits.
+func (p Params) SecurityBits(size int) int {
+	queries := p.Queries * log2(p.Blowup)
+	field := 64 - log2(size)
+	if queries < field {
+		return queries
+	}
+	return field
+}
+
+// FRIQuery contains the openings of all the layers at one position
+type FRIQuery struct {
+	// Values[i] is the pair (f_i(x), f_i(-x)) of the layer i
+	Values [][2]Element
+	Paths  []MerklePath
+}
+
+// FRIProof is a non interactive FRI proof
+type FRIProof struct {
+	// Roots are the commitments to the layers
+	Roots []Hash
+	// Remainder is the polynomial of the last layer, given by its
+	// coefficients
+	Remainder []Element
+	Queries   []FRIQuery
+}
+
+// ProveFRI returns a proof that the evaluations over the domain are those of a
+// polynomial of degree less than domain.Size / Blowup. It also returns the
+// positions queried, all in [0, domain.Size/2), such that a protocol built on
+// top of FRI, like the STARK, can open its own commitments at the same
+// positions: the query at position j opens the first layer at x_j and -x_j.
+// It returns an error if the evaluations are not of a low degree polynomial.
+func ProveFRI(tr *transcript.Transcript, params Params, domain Domain, evals []Element) (FRIProof, []int, error) {
+	var proof FRIProof
+	if err := checkFRI(params, domain); err != nil {
+		return proof, nil, err
+	}
+	if len(evals) != domain.Size {
+		return proof, nil, errors.New("fri: wrong number of evaluations")
+	}
+	size := domain.Size
+	var layers [][]Element
+	var trees []*MerkleTree
+	for len(layers) == 0 || domain.Size > params.RemainderSize*params.Blowup {
+		half := domain.Size / 2
+		leaves := make([][]Element, half)
+		for j := range leaves {
+			leaves[j] = []Element{evals[j], evals[j+half]}
+		}
+		tree := NewMerkleTree(leaves)
+		root := tree.Root()
+		tr.AppendMessage("fri.root", root[:])
+		beta := challengeElement(tr, "fri.beta")
+		layers = append(layers, evals)
+		trees = append(trees, tree)
+		proof.Roots = append(proof.Roots, root)
+
+		next := make([]Element, half)
+		x := domain.Offset
+		for j := range next {
+			next[j] = fold(evals[j], evals[j+half], x, beta)
+			x = x.Mul(domain.Root)
+		}
+		evals = next
+		domain = domain.Square()
+	}
+
+	// the last layer must be a polynomial of degree less than size / blowup
+	coeffs := domain.Interpolate(evals)
+	degree := domain.Size / params.Blowup
+	for _, c := range coeffs[degree:] {
+		if c != 0 {
+			return proof, nil, errors.New("fri: evaluations are not of a low degree polynomial")
+		}
+	}
+	proof.Remainder = coeffs[:degree]
+	appendElements(tr, "fri.remainder", proof.Remainder)
+
+	positions := queryPositions(tr, params, size)
+	for _, pos := range positions {
+		var q FRIQuery
+		for i, layer := range layers {
+			half := len(layer) / 2
+			j := pos % half
+			q.Values = append(q.Values, [2]Element{layer[j], layer[j+half]})
+			q.Paths = append(q.Paths, trees[i].Open(j))
+		}
+		proof.Queries = append(proof.Queries, q)
+	}
+	return proof, positions, nil
+}
+
+// VerifyFRI verifies the proof that evaluations over the domain are those of a
+// low degree polynomial. If it is valid, it returns the positions queried and,
+// for each of them, the opened values (f(x_j), f(-x_j)) of the first layer,
+// that the caller must check against its own definition of f.
+func VerifyFRI(tr *transcript.Transcript, params Params, domain Domain, proof FRIProof) ([]int, [][2]Element, error) {
+	if err := checkFRI(params, domain); err != nil {
+		return nil, nil, err
+	}
+	var betas []Element
+	domains := []Domain{domain}
+	for _, root := range proof.Roots {
+		tr.AppendMessage("fri.root", root[:])
+		betas = append(betas, challengeElement(tr, "fri.beta"))
+		domain = domain.Square()
+		domains = append(domains, domain)
+	}
+	// the number of layers is fixed by the parameters: the prover folds at
+	// least once and until the size is at most RemainderSize * Blowup
+	limit := params.RemainderSize * params.Blowup
+	nbLayers := len(proof.Roots)
+	if nbLayers == 0 || domain.Size > limit || nbLayers > 1 && domains[nbLayers-1].Size <= limit {
+		return nil, nil, errors.New("fri: wrong number of layers")
+	}
+	if len(proof.Remainder) != domain.Size/params.Blowup {
+		return nil, nil, errors.New("fri: wrong remainder size")
+	}
+	appendElements(tr, "fri.remainder", proof.Remainder)
+
+	positions := queryPositions(tr, params, domains[0].Size)
+	if len(proof.Queries) != len(positions) {
+		return nil, nil, errors.New("fri: wrong number of queries")
+	}
+	var firsts [][2]Element
+	for k, pos := range positions {
+		q := proof.Queries[k]
+		if len(q.Values) != nbLayers || len(q.Paths) != nbLayers {
+			return nil, nil, errors.New("fri: wrong number of openings")
+		}
+		// pos is the position of x in the current layer and the folded value
+		// f'(x^2) is at position pos mod size/2 in the next layer
+		var folded Element
+		for i, d := range domains[:nbLayers] {
+			half := d.Size / 2
+			j := pos % half
+			values := q.Values[i]
+			if !VerifyPath(proof.Roots[i], j, values[:], q.Paths[i]) {
+				return nil, nil, fmt.Errorf("fri: invalid opening in layer %d", i)
+			}
+			if i > 0 && values[pos/half] != folded {
+				return nil, nil, fmt.Errorf("fri: invalid folding in layer %d", i)
+			}
+			folded = fold(values[0], values[1], d.Element(j), betas[i])
+			pos = j
+		}
+		// the last folding must agree with the remainder
+		if folded != EvalPoly(proof.Remainder, domain.Element(pos)) {
+			return nil, nil, errors.New("fri: invalid folding in the remainder")
+		}
+		firsts = append(firsts, q.Values[0])
+	}
+	return positions, firsts, nil
+}
+
+// fold returns (f(x) + f(-x)) / 2 + beta * (f(x) - f(-x)) / (2x)
+func fold(fx, fmx, x, beta Element) Element {
+	twoInv := Element(2).Inv()
+	even := fx.Add(fmx).Mul(twoInv)
+	odd := fx.Sub(fmx).Mul(twoInv).Mul(x.Inv())
+	return even.Add(beta.Mul(odd))
+}
+
+func checkFRI(params Params, domain Domain) error {
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	if !isPowerOfTwo(domain.Size) || domain.Size < 2*params.Blowup {
+		return fmt.Errorf("fri: domain of size %d too small for a blowup of %d", domain.Size, params.Blowup)
+	}
+	return nil
+}
+
+// queryPositions derives the positions of the queries in [0, size/2)
+func queryPositions(tr *transcript.Transcript, params Params, size int) []int {
+	positions := make([]int, params.Queries)
+	for i := range positions {
+		buff := tr.ChallengeBytes("fri.query", 8)
+		positions[i] = int(binary.LittleEndian.Uint64(buff) % uint64(size/2))
+	}
+	return positions
+}
+
+// challengeElement squeezes a field element out of the transcript: 16 bytes
+// are reduced modulo p such that the result is statistically close to uniform
+func challengeElement(tr *transcript.Transcript, label string) Element {
+	buff := tr.ChallengeBytes(label, 16)
+	hi := binary.LittleEndian.Uint64(buff[8:]) % P
+	lo := binary.LittleEndian.Uint64(buff[:8])
+	return Element(bits.Rem64(hi, lo, P))
+}
+
+func appendElements(tr *transcript.Transcript, label string, elements []Element) {
+	tr.AppendUint64(label, uint64(len(elements)))
+	for _, e := range elements {
+		tr.AppendUint64(label, uint64(e))
+	}
+}
@@ -0,0 +1,104 @@
+package stark
+
+import (
+	"testing"
+
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFRI(t *testing.T) {
+	params := DefaultParams()
+	domain := NewDomain(64*params.Blowup, Offset)
+	coeffs := make([]Element, 64)
+	for i := range coeffs {
+		coeffs[i] = NewElement(uint64(i*i + 1))
+	}
+	evals := domain.Evaluate(coeffs)
+	proof, positions, err := ProveFRI(transcript.New("test"), params, domain, evals)
+	require.NoError(t, err)
+	// 512 -> 256 -> ... -> 32 = RemainderSize * Blowup
+	require.Len(t, proof.Roots, 4)
+	require.Len(t, proof.Remainder, 4)
+	require.Len(t, positions, params.Queries)
+
+	vPositions, values, err := VerifyFRI(transcript.New("test"), params, domain, proof)
+	require.NoError(t, err)
+	require.Equal(t, positions, vPositions)
+	for k, pos := range positions {
+		require.Equal(t, evals[pos], values[k][0])
+		require.Equal(t, evals[pos+domain.Size/2], values[k][1])
+	}
+
+	// different transcript
+	_, _, err = VerifyFRI(transcript.New("other"), params, domain, proof)
+	require.Error(t, err)
+	// tampered opening
+	tampered := proof
+	tampered.Queries = append([]FRIQuery{}, proof.Queries...)
+	values1 := append([][2]Element{}, proof.Queries[0].Values...)
+	values1[1][0] = values1[1][0].Add(1)
+	tampered.Queries[0].Values = values1
+	_, _, err = VerifyFRI(transcript.New("test"), params, domain, tampered)
+	require.Error(t, err)
+	// tampered remainder
+	tampered = proof
+	tampered.Remainder = append([]Element{proof.Remainder[0].Add(1)}, proof.Remainder[1:]...)
+	_, _, err = VerifyFRI(transcript.New("test"), params, domain, tampered)
+	require.Error(t, err)
+	// missing layer
+	tampered = proof
+	tampered.Roots = proof.Roots[1:]
+	_, _, err = VerifyFRI(transcript.New("test"), params, domain, tampered)
+	require.Error(t, err)
+}
+
+func TestFRIHighDegree(t *testing.T) {
+	params := DefaultParams()
+	domain := NewDomain(64*params.Blowup, Offset)
+	// degree 64 is one too many
+	coeffs := make([]Element, 65)
+	coeffs[64] = 1
+	_, _, err := ProveFRI(transcript.New("test"), params, domain, domain.Evaluate(coeffs))
+	require.Error(t, err)
+
+	// a prover cheating on a single evaluation of a low degree polynomial
+	// is caught if one of the queries hits it, or by the remainder check
+	evals := domain.Evaluate(coeffs[:64])
+	for i := range evals {
+		evals[i] = evals[i].Add(Element(i))
+	}
+	_, _, err = ProveFRI(transcript.New("test"), params, domain, evals)
+	require.Error(t, err)
+}
+
+func TestFRIParams(t *testing.T) {
+	params := DefaultParams()
+	require.NoError(t, params.Validate())
+	require.Equal(t, 20, Params{Blowup: 4, Queries: 10, RemainderSize: 1}.SecurityBits(1<<10))
+	// capped by the size of the field
+	require.Equal(t, 54, params.SecurityBits(1<<10))
+	require.Equal(t, 44, params.SecurityBits(1<<20))
+	for _, p := range []Params{
+		{Blowup: 1, Queries: 1, RemainderSize: 1},
+		{Blowup: 6, Queries: 1, RemainderSize: 1},
+		{Blowup: 2, Queries: 0, RemainderSize: 1},
+		{Blowup: 2, Queries: 1, RemainderSize: 3},
+	} {
+		require.Error(t, p.Validate())
+	}
+	// the domain must allow at least one folding
+	_, _, err := ProveFRI(transcript.New("test"), params, NewDomain(8, Offset), make([]Element, 8))
+	require.Error(t, err)
+
+	// a single layer when the degree is already small
+	params = Params{Blowup: 4, Queries: 8, RemainderSize: 8}
+	domain := NewDomain(16, Offset)
+	evals := domain.Evaluate([]Element{1, 2, 3, 4})
+	proof, _, err := ProveFRI(transcript.New("test"), params, domain, evals)
+	require.NoError(t, err)
+	require.Len(t, proof.Roots, 1)
+	require.Len(t, proof.Remainder, 2)
+	_, _, err = VerifyFRI(transcript.New("test"), params, domain, proof)
+	require.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package stark
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// Hash is the output of SHA-256, used for the nodes of the Merkle trees
+type Hash [sha256.Size]byte
+
+// Leaves and internal nodes are hashed with a different prefix such that a
+// leaf can never be confused with a node.
+const (
+	leafPrefix byte = iota
+	nodePrefix
+)
+
+// MerkleTree commits to a vector of leaves, each leaf being a list of field
+// elements. The number of leaves must be a power of two.
+type MerkleTree struct {
+	// layers[0] contains the hashes of the leaves and the last layer the root
+	layers [][]Hash
+}
+
+// MerklePath contains the siblings of the nodes from a leaf up to the root
+type MerklePath []Hash
+
+// HashLeaf returns the hash of a leaf
+func HashLeaf(values []Element) Hash {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	for _, v := range values {
+		h.Write(v.Bytes())
+	}
+	var out Hash
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+func hashNode(left, right Hash) Hash {
+	h := sha256.New()
+	h.Write([]byte{nodePrefix})
+	h.Write(left[:])
+	h.Write(right[:])
+	var out Hash
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// NewMerkleTree returns the tree committing to the leaves
+func NewMerkleTree(leaves [][]Element) *MerkleTree {
+	if !isPowerOfTwo(len(leaves)) {
+		panic("the number of leaves must be a power of two")
+	}
+	layer := make([]Hash, len(leaves))
+	for i, l := range leaves {
+		layer[i] = HashLeaf(l)
+	}
+	t := &MerkleTree{layers: [][]Hash{layer}}
+	for len(layer) > 1 {
+		next := make([]Hash, len(layer)/2)
+		for i := range next {
+			next[i] = hashNode(layer[2*i], layer[2*i+1])
+		}
+		t.layers = append(t.layers, next)
+		layer = next
+	}
+	return t
+}
+
+// Root returns the commitment to the leaves
+func (t *MerkleTree) Root() Hash {
+	return t.layers[len(t.layers)-1][0]
+}
+
+// Open returns the path authenticating the leaf of the given index
+func (t *MerkleTree) Open(index int) MerklePath {
+	var path MerklePath
+	for _, layer := range t.layers[:len(t.layers)-1] {
+		path = append(path, layer[index^1])
+		index >>= 1
+	}
+	return path
+}
+
+// VerifyPath returns true if the path authenticates the leaf at the given
+// index against the root
+func VerifyPath(root Hash, index int, leaf []Element, path MerklePath) bool {
+	if index < 0 || index>>uint(len(path)) != 0 {
+		return false
+	}
+	h := HashLeaf(leaf)
+	for _, sibling := range path {
+		if index&1 == 0 {
+			h = hashNode(h, sibling)
+		} else {
+			h = hashNode(sibling, h)
+		}
+		index >>= 1
+	}
+	return bytes.Equal(h[:], root[:])
+}
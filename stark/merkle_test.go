@@ -0,0 +1,31 @@
+package stark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleTree(t *testing.T) {
+	var leaves [][]Element
+	for i := 0; i < 8; i++ {
+		leaves = append(leaves, []Element{Element(i), Element(i * i)})
+	}
+	tree := NewMerkleTree(leaves)
+	root := tree.Root()
+	for i, leaf := range leaves {
+		path := tree.Open(i)
+		require.Len(t, path, 3)
+		require.True(t, VerifyPath(root, i, leaf, path))
+		require.False(t, VerifyPath(root, i^1, leaf, path))
+		require.False(t, VerifyPath(root, i+8, leaf, path))
+	}
+	require.False(t, VerifyPath(root, 0, []Element{0, 1}, tree.Open(0)))
+	require.False(t, VerifyPath(root, 0, leaves[0], tree.Open(0)[:2]))
+
+	// a single leaf is its own root
+	single := NewMerkleTree(leaves[:1])
+	require.Equal(t, HashLeaf(leaves[0]), single.Root())
+	require.True(t, VerifyPath(single.Root(), 0, leaves[0], single.Open(0)))
+	require.Panics(t, func() { NewMerkleTree(leaves[:3]) })
+}
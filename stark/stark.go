@@ -0,0 +1,210 @@
+package stark
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// The STARK proves that the prover knows a valid execution trace of an AIR.
+// Each column of the trace is interpolated over the subgroup H of order
+// Length, H = <g>, and extended over a coset D of a larger subgroup, the low
+// degree extension, which is committed row by row in a Merkle tree.
+// The constraints become polynomial identities: a transition constraint t
+// holds on every row but the last iff
+//
+//	t(x) * (x - g^(n-1)) / (x^n - 1)
+//
+// is a polynomial, where t(x) is evaluated on the columns at x and g*x (the
+// next row). Likewise a boundary constraint fixing the column c at row r holds
+// iff (c(x) - v) / (x - g^r) is a polynomial. The prover combines all these
+// quotients with the powers of a random alpha into a composition polynomial
+// and proves with FRI that it has a low degree. The verifier recomputes the
+// composition at the positions queried by FRI from the openings of the trace
+// at x, -x and at the next rows g*x and -g*x.
+// This minimal STARK is not zero knowledge: the openings reveal the low
+// degree extension of the trace.
+
+// Offset is the offset of the evaluation domain, such that it is disjoint from
+// the trace domain
+const Offset = Generator
+
+// TraceQuery contains the openings of the low degree extension of the trace at
+// the positions needed to recompute the composition at x and -x: the rows at
+// x, -x, g*x and -g*x in this order.
+type TraceQuery struct {
+	Rows  [4][]Element
+	Paths [4]MerklePath
+}
+
+// Proof is a STARK proof
+type Proof struct {
+	// TraceRoot is the commitment to the low degree extension of the trace
+	TraceRoot Hash
+	// FRI proves the composition polynomial is of low degree
+	FRI   FRIProof
+	Trace []TraceQuery
+}
+
+// Prove returns a proof that the trace, given as a list of columns, is a
+// valid execution of the AIR. It returns an error if the trace is not valid.
+func Prove(params Params, air AIR, trace [][]Element) (Proof, error) {
+	var proof Proof
+	traceDomain, domain, err := domains(params, air)
+	if err != nil {
+		return proof, err
+	}
+	if len(trace) != air.Width() {
+		return proof, errors.New("stark: wrong number of columns")
+	}
+	// low degree extension of the trace, committed row by row
+	lde := make([][]Element, air.Width())
+	for c, column := range trace {
+		if len(column) != air.Length() {
+			return proof, fmt.Errorf("stark: wrong length of column %d", c)
+		}
+		lde[c] = domain.Evaluate(traceDomain.Interpolate(column))
+	}
+	rows := make([][]Element, domain.Size)
+	for i := range rows {
+		rows[i] = make([]Element, air.Width())
+		for c := range lde {
+			rows[i][c] = lde[c][i]
+		}
+	}
+	tree := NewMerkleTree(rows)
+	proof.TraceRoot = tree.Root()
+	tr := newTranscript(params, air, proof.TraceRoot)
+	alpha := challengeElement(tr, "stark.alpha")
+
+	// the next row of x is g*x, at blowup positions further in D
+	step := domain.Size / traceDomain.Size
+	composition := make([]Element, domain.Size)
+	for i, x := range domain.Elements() {
+		composition[i] = compose(air, traceDomain, alpha, x, rows[i], rows[(i+step)%domain.Size])
+	}
+	var positions []int
+	proof.FRI, positions, err = ProveFRI(tr, params, domain, composition)
+	if err != nil {
+		return proof, fmt.Errorf("stark: invalid trace: %v", err)
+	}
+	for _, pos := range positions {
+		var q TraceQuery
+		for k, i := range tracePositions(pos, domain.Size, step) {
+			q.Rows[k] = rows[i]
+			q.Paths[k] = tree.Open(i)
+		}
+		proof.Trace = append(proof.Trace, q)
+	}
+	return proof, nil
+}
+
+// Verify returns nil if the proof shows the prover knows a valid execution
+// trace of the AIR, and an error otherwise
+func Verify(params Params, air AIR, proof Proof) error {
+	traceDomain, domain, err := domains(params, air)
+	if err != nil {
+		return err
+	}
+	tr := newTranscript(params, air, proof.TraceRoot)
+	alpha := challengeElement(tr, "stark.alpha")
+	positions, values, err := VerifyFRI(tr, params, domain, proof.FRI)
+	if err != nil {
+		return err
+	}
+	if len(proof.Trace) != len(positions) {
+		return errors.New("stark: wrong number of trace openings")
+	}
+	step := domain.Size / traceDomain.Size
+	for k, pos := range positions {
+		q := proof.Trace[k]
+		for j, i := range tracePositions(pos, domain.Size, step) {
+			if len(q.Rows[j]) != air.Width() || !VerifyPath(proof.TraceRoot, i, q.Rows[j], q.Paths[j]) {
+				return fmt.Errorf("stark: invalid trace opening at position %d", i)
+			}
+		}
+		x := domain.Element(pos)
+		if compose(air, traceDomain, alpha, x, q.Rows[0], q.Rows[2]) != values[k][0] ||
+			compose(air, traceDomain, alpha, x.Neg(), q.Rows[1], q.Rows[3]) != values[k][1] {
+			return fmt.Errorf("stark: composition mismatch at position %d", pos)
+		}
+	}
+	return nil
+}
+
+// domains returns the trace domain and the evaluation domain. The composition
+// has degree less than max(1, Degree - 1) * Length and the evaluation domain
+// is Blowup times bigger.
+func domains(params Params, air AIR) (Domain, Domain, error) {
+	if err := params.Validate(); err != nil {
+		return Domain{}, Domain{}, err
+	}
+	n := air.Length()
+	if n < 2 || !isPowerOfTwo(n) {
+		return Domain{}, Domain{}, fmt.Errorf("stark: trace length %d is not a power of two", n)
+	}
+	factor := 1
+	if air.Degree() > 2 {
+		factor = nextPowerOfTwo(air.Degree() - 1)
+	}
+	size := factor * n * params.Blowup
+	if log2(size) > TwoAdicity {
+		return Domain{}, Domain{}, errors.New("stark: trace too long")
+	}
+	for _, b := range air.Boundaries() {
+		if b.Row < 0 || b.Row >= n || b.Column < 0 || b.Column >= air.Width() {
+			return Domain{}, Domain{}, errors.New("stark: boundary outside of the trace")
+		}
+	}
+	return NewDomain(n, 1), NewDomain(size, Offset), nil
+}
+
+// compose returns the evaluation at x of the composition polynomial
+//
+//	SUM alpha^k * t_k(x) * (x - g^(n-1)) / (x^n - 1) + SUM alpha^k * (c(x) - v) / (x - g^r)
+//
+// given the rows of the trace at x and g*x
+func compose(air AIR, traceDomain Domain, alpha, x Element, current, next []Element) Element {
+	var res Element
+	coeff := Element(1)
+	last := traceDomain.Element(traceDomain.Size - 1)
+	zInv := x.Exp(uint64(traceDomain.Size)).Sub(1).Inv()
+	transitionFactor := x.Sub(last).Mul(zInv)
+	for _, t := range air.Transition(current, next) {
+		res = res.Add(coeff.Mul(t).Mul(transitionFactor))
+		coeff = coeff.Mul(alpha)
+	}
+	for _, b := range air.Boundaries() {
+		q := current[b.Column].Sub(b.Value).Mul(x.Sub(traceDomain.Element(b.Row)).Inv())
+		res = res.Add(coeff.Mul(q))
+		coeff = coeff.Mul(alpha)
+	}
+	return res
+}
+
+// tracePositions returns the positions in the evaluation domain of x, -x, g*x
+// and -g*x where x is at position pos
+func tracePositions(pos, size, step int) [4]int {
+	half := size / 2
+	return [4]int{pos, pos + half, (pos + step) % size, (pos + half + step) % size}
+}
+
+// newTranscript returns the transcript bound to the parameters, the statement
+// and the commitment to the trace
+func newTranscript(params Params, air AIR, root Hash) *transcript.Transcript {
+	tr := transcript.New("stark")
+	tr.AppendUint64("blowup", uint64(params.Blowup))
+	tr.AppendUint64("queries", uint64(params.Queries))
+	tr.AppendUint64("remainder", uint64(params.RemainderSize))
+	tr.AppendUint64("width", uint64(air.Width()))
+	tr.AppendUint64("length", uint64(air.Length()))
+	tr.AppendUint64("degree", uint64(air.Degree()))
+	for _, b := range air.Boundaries() {
+		tr.AppendUint64("column", uint64(b.Column))
+		tr.AppendUint64("row", uint64(b.Row))
+		tr.AppendUint64("value", uint64(b.Value))
+	}
+	tr.AppendMessage("trace", root[:])
+	return tr
+}
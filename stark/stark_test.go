@@ -0,0 +1,92 @@
+package stark
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFibonacciTrace(t *testing.T) {
+	f := NewFibonacci(1, 1, 8)
+	trace := f.Trace()
+	require.Equal(t, []Element{1, 1, 2, 3, 5, 8, 13, 21}, trace[0])
+	require.Equal(t, Element(34), f.Result)
+	for i := 0; i+1 < f.Steps; i++ {
+		current := []Element{trace[0][i], trace[1][i]}
+		next := []Element{trace[0][i+1], trace[1][i+1]}
+		require.Equal(t, []Element{0, 0}, f.Transition(current, next))
+	}
+}
+
+func TestStarkFibonacci(t *testing.T) {
+	params := DefaultParams()
+	f := NewFibonacci(1, 1, 64)
+	proof, err := Prove(params, f, f.Trace())
+	require.NoError(t, err)
+	require.NoError(t, Verify(params, f, proof))
+
+	// wrong claimed result
+	wrong := f
+	wrong.Result = f.Result.Add(1)
+	require.Error(t, Verify(params, wrong, proof))
+	// wrong parameters
+	require.Error(t, Verify(Params{Blowup: 8, Queries: 31, RemainderSize: 4}, f, proof))
+	// tampered trace opening
+	tampered := proof
+	tampered.Trace = append([]TraceQuery{}, proof.Trace...)
+	tampered.Trace[0].Rows[2] = []Element{1, 2}
+	require.Error(t, Verify(params, f, tampered))
+}
+
+func TestStarkInvalidTrace(t *testing.T) {
+	params := DefaultParams()
+	f := NewFibonacci(1, 1, 32)
+	// the prover can't prove a wrong result
+	wrong := f
+	wrong.Result = f.Result.Add(1)
+	_, err := Prove(params, wrong, f.Trace())
+	require.Error(t, err)
+	// nor a trace that doesn't follow the transitions
+	trace := f.Trace()
+	trace[0][10] = trace[0][10].Add(1)
+	_, err = Prove(params, f, trace)
+	require.Error(t, err)
+
+	// malformed traces
+	_, err = Prove(params, f, trace[:1])
+	require.Error(t, err)
+	_, err = Prove(params, NewFibonacci(1, 1, 12), trace)
+	require.Error(t, err)
+}
+
+// squares is an AIR with a degree 2 transition constraint x' = x^2
+type squares struct {
+	start, result Element
+	steps         int
+}
+
+func (s squares) Width() int  { return 1 }
+func (s squares) Length() int { return s.steps }
+func (s squares) Degree() int { return 2 }
+func (s squares) Transition(current, next []Element) []Element {
+	return []Element{next[0].Sub(current[0].Mul(current[0]))}
+}
+func (s squares) Boundaries() []Boundary {
+	return []Boundary{{Row: 0, Value: s.start}, {Row: s.steps - 1, Value: s.result}}
+}
+
+func TestStarkQuadratic(t *testing.T) {
+	params := Params{Blowup: 4, Queries: 16, RemainderSize: 2}
+	s := squares{start: 3, steps: 16}
+	column := make([]Element, s.steps)
+	column[0] = s.start
+	for i := 1; i < s.steps; i++ {
+		column[i] = column[i-1].Mul(column[i-1])
+	}
+	s.result = column[s.steps-1]
+	proof, err := Prove(params, s, [][]Element{column})
+	require.NoError(t, err)
+	require.NoError(t, Verify(params, s, proof))
+	s.result = s.result.Add(1)
+	require.Error(t, Verify(params, s, proof))
+}
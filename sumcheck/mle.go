@@ -0,0 +1,166 @@
+// Package sumcheck implements multilinear extensions and the sumcheck protocol
+// of Lund, Fortnow, Karloff and Nisan, made non interactive with the
+// Fiat-Shamir transform. It works over the scalar field of the elements it is
+// given, by default the one of BLS12-381 as the Element of the playsnark
+// package, and doesn't require any setup.
+package sumcheck
+
+import (
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+)
+
+type Element = kyber.Scalar
+
+// Group is the group whose scalars are used by the package, the scalars of
+// G1 and G2 of BLS12-381 are the same.
+var Group = bls.NewBLS12381Suite().G1()
+
+func NewElement() Element {
+	return Group.Scalar().Zero()
+}
+
+// Field creates the elements of a scalar field, kyber.Group is one
+type Field interface {
+	Scalar() kyber.Scalar
+}
+
+// elementField is the field of an element
+type elementField struct {
+	e Element
+}
+
+func (f elementField) Scalar() kyber.Scalar {
+	return f.e.Clone().Zero()
+}
+
+// fieldOf returns the field of the elements, the default one if there are none
+func fieldOf(es ...Element) Field {
+	if len(es) == 0 {
+		return Group
+	}
+	return elementField{es[0]}
+}
+
+func zero(f Field) Element {
+	return f.Scalar().Zero()
+}
+
+func one(f Field) Element {
+	return f.Scalar().One()
+}
+
+// MLE is the multilinear extension of a function f from {0,1}^v to the field,
+// given by its evaluations: f(b_0, ..., b_(v-1)) is the entry of index
+// SUM b_i * 2^i. The extension is the unique polynomial of degree at most one
+// in each variable agreeing with f on the hypercube:
+//
+//	f~(x) = SUM_b f(b) * eq(x, b) with eq(x, b) = PROD x_i * b_i + (1 - x_i) * (1 - b_i)
+type MLE []Element
+
+// NewMLE returns the multilinear extension of the evaluations, padded with
+// zeros up to the next power of two.
+func NewMLE(evals []Element) MLE {
+	n := 1
+	for n < len(evals) {
+		n <<= 1
+	}
+	m := make(MLE, n)
+	for i := range m {
+		m[i] = zero(fieldOf(evals...))
+		if i < len(evals) {
+			m[i] = evals[i].Clone()
+		}
+	}
+	return m
+}
+
+// NumVars returns the number of variables v
+func (m MLE) NumVars() int {
+	v := 0
+	for 1<<uint(v) < len(m) {
+		v++
+	}
+	return v
+}
+
+// Fix returns the extension with its first variable fixed to r, with one
+// variable less:
+//
+//	f~(r, x_1, ...) = (1 - r) * f~(0, x_1, ...) + r * f~(1, x_1, ...)
+func (m MLE) Fix(r Element) MLE {
+	out := make(MLE, len(m)/2)
+	for i := range out {
+		// f(0,..) + r * (f(1,..) - f(0,..))
+		d := r.Clone().Sub(m[2*i+1], m[2*i])
+		out[i] = d.Add(m[2*i], d.Mul(d, r))
+	}
+	return out
+}
+
+// Evaluate returns f~(point), the point must have one coordinate per variable
+func (m MLE) Evaluate(point []Element) Element {
+	if len(point) != m.NumVars() {
+		panic("sumcheck: wrong number of coordinates")
+	}
+	cur := m
+	for _, r := range point {
+		cur = cur.Fix(r)
+	}
+	return cur[0].Clone()
+}
+
+// Clone returns a deep copy of the extension
+func (m MLE) Clone() MLE {
+	out := make(MLE, len(m))
+	for i := range m {
+		out[i] = m[i].Clone()
+	}
+	return out
+}
+
+// EqMLE returns the extension of b -> eq(point, b), i.e. the evaluations of the
+// Lagrange basis of the hypercube at the point.
+func EqMLE(point []Element) MLE {
+	return EqMLEWith(fieldOf(point...), point)
+}
+
+// EqMLEWith returns EqMLE(point) over the given field, which matters when the
+// point has no coordinate.
+func EqMLEWith(f Field, point []Element) MLE {
+	evals := MLE{one(f)}
+	for i, r := range point {
+		next := make(MLE, 2*len(evals))
+		oneMinus := f.Scalar().Sub(one(f), r)
+		for b := range evals {
+			// the bit i of the index is the coordinate i
+			next[b] = f.Scalar().Mul(evals[b], oneMinus)
+			next[b+1<<uint(i)] = f.Scalar().Mul(evals[b], r)
+		}
+		evals = next
+	}
+	return evals
+}
+
+// Eq returns eq(x, y) = PROD x_i * y_i + (1 - x_i) * (1 - y_i)
+func Eq(x, y []Element) Element {
+	return EqWith(fieldOf(x...), x, y)
+}
+
+// EqWith returns Eq(x, y) over the given field, which matters when the points
+// have no coordinate.
+func EqWith(f Field, x, y []Element) Element {
+	if len(x) != len(y) {
+		panic("sumcheck: points of different dimensions")
+	}
+	acc := one(f)
+	for i := range x {
+		xy := f.Scalar().Mul(x[i], y[i])
+		t := f.Scalar().Sub(one(f), x[i])
+		t = t.Sub(t, y[i])
+		t = t.Add(t, xy)
+		t = t.Add(t, xy)
+		acc = acc.Mul(acc, t)
+	}
+	return acc
+}
@@ -0,0 +1,54 @@
+package sumcheck
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/stretchr/testify/require"
+)
+
+func randomElements(n int) []Element {
+	out := make([]Element, n)
+	for i := range out {
+		out[i] = NewElement().Pick(random.New())
+	}
+	return out
+}
+
+func TestMLE(t *testing.T) {
+	evals := randomElements(5)
+	m := NewMLE(evals)
+	require.Len(t, m, 8)
+	require.Equal(t, 3, m.NumVars())
+
+	// the extension agrees with the function on the hypercube
+	for b := range m {
+		var point []Element
+		for i := 0; i < 3; i++ {
+			point = append(point, NewElement().SetInt64(int64((b>>uint(i))&1)))
+		}
+		require.True(t, m.Evaluate(point).Equal(m[b]))
+	}
+
+	// f~(x) = SUM f(b) * eq(x, b)
+	x := randomElements(3)
+	eq := EqMLE(x)
+	acc := NewElement()
+	for b := range m {
+		acc = acc.Add(acc, NewElement().Mul(m[b], eq[b]))
+	}
+	require.True(t, m.Evaluate(x).Equal(acc))
+	require.True(t, eq.Evaluate(x).Equal(Eq(x, x)))
+	y := randomElements(3)
+	require.True(t, eq.Evaluate(y).Equal(Eq(x, y)))
+
+	// the extension is linear in each variable
+	two := NewElement().SetInt64(2)
+	f0 := m.Evaluate([]Element{NewElement(), x[1], x[2]})
+	f1 := m.Evaluate([]Element{one(Group), x[1], x[2]})
+	f2 := m.Evaluate([]Element{two, x[1], x[2]})
+	require.True(t, NewElement().Sub(f2, f1).Equal(NewElement().Sub(f1, f0)))
+
+	require.Panics(t, func() { m.Evaluate(x[:2]) })
+	require.Equal(t, 0, NewMLE(randomElements(1)).NumVars())
+}
@@ -0,0 +1,186 @@
+package sumcheck
+
+import (
+	"errors"
+
+	"github.com/nikkolasg/playsnark/transcript"
+)
+
+// The sumcheck protocol lets a prover convince a verifier that
+//
+//	SUM_(b in {0,1}^v) g(b) = H
+//
+// for a v-variate polynomial g of degree at most d in each variable, while the
+// verifier only evaluates g once, at a random point. In round i, the prover
+// sends the univariate polynomial obtained by summing over the remaining
+// variables:
+//
+//	g_i(X) = SUM_(b_(i+1), ...) g(r_0, ..., r_(i-1), X, b_(i+1), ...)
+//
+// The verifier checks g_i(0) + g_i(1) is the claim of the previous round,
+// sends a random r_i and the new claim is g_i(r_i). After v rounds, the claim
+// is g(r_0, ..., r_(v-1)), which the verifier checks by itself or with another
+// protocol.
+//
+// Here g is a sum of products of multilinear extensions, which is the shape of
+// the polynomials in GKR and Spartan, and the univariate polynomials are sent
+// as their evaluations at 0, 1, ..., d. The challenges are derived from a
+// transcript.
+
+// Product is the product of the multilinear extensions, all with the same
+// number of variables
+type Product []MLE
+
+// Polynomial is the sum of the products, all with the same number of variables
+type Polynomial []Product
+
+// NumVars returns the number of variables of the polynomial
+func (p Polynomial) NumVars() int {
+	return p[0][0].NumVars()
+}
+
+// Degree returns the maximum degree of the polynomial in each variable, i.e.
+// the number of extensions of the longest product
+func (p Polynomial) Degree() int {
+	d := 0
+	for _, prod := range p {
+		if len(prod) > d {
+			d = len(prod)
+		}
+	}
+	return d
+}
+
+// Sum returns the sum of the polynomial over the hypercube
+func (p Polynomial) Sum() Element {
+	f := fieldOf(p[0][0]...)
+	acc := zero(f)
+	for _, prod := range p {
+		for b := range prod[0] {
+			t := one(f)
+			for _, m := range prod {
+				t = t.Mul(t, m[b])
+			}
+			acc = acc.Add(acc, t)
+		}
+	}
+	return acc
+}
+
+// Proof contains the evaluations of the univariate polynomial of each round at
+// 0, 1, ..., d
+type Proof struct {
+	Rounds [][]Element
+}
+
+// Prove runs the prover on the polynomial and returns the proof, the random
+// point r chosen by the verifier and the evaluations at r of all the
+// extensions, product by product, which the caller usually sends to the
+// verifier as well.
+func Prove(tr *transcript.Transcript, p Polynomial) (Proof, []Element, [][]Element) {
+	var proof Proof
+	v := p.NumVars()
+	d := p.Degree()
+	f := fieldOf(p[0][0]...)
+	appendHeader(tr, v, d)
+	// the extensions are fixed one variable at a time, we work on copies
+	cur := make(Polynomial, len(p))
+	for i, prod := range p {
+		for _, m := range prod {
+			cur[i] = append(cur[i], m.Clone())
+		}
+	}
+	var point []Element
+	for round := 0; round < v; round++ {
+		// g_i(t) for t:0->d, each extension is linear in X so its value at t
+		// is m(0,..) + t * (m(1,..) - m(0,..))
+		evals := make([]Element, d+1)
+		for t := range evals {
+			evals[t] = zero(f)
+			te := f.Scalar().SetInt64(int64(t))
+			for _, prod := range cur {
+				for b := 0; b < len(prod[0])/2; b++ {
+					acc := one(f)
+					for _, m := range prod {
+						e := f.Scalar().Sub(m[2*b+1], m[2*b])
+						e = e.Add(m[2*b], e.Mul(e, te))
+						acc = acc.Mul(acc, e)
+					}
+					evals[t] = evals[t].Add(evals[t], acc)
+				}
+			}
+		}
+		proof.Rounds = append(proof.Rounds, evals)
+		r := roundChallenge(tr, evals)
+		point = append(point, r)
+		for i, prod := range cur {
+			for j, m := range prod {
+				cur[i][j] = m.Fix(r)
+			}
+		}
+	}
+	finals := make([][]Element, len(cur))
+	for i, prod := range cur {
+		for _, m := range prod {
+			finals[i] = append(finals[i], m[0])
+		}
+	}
+	return proof, point, finals
+}
+
+// Verify checks the rounds of the proof for the claimed sum of a polynomial
+// with v variables and degree d. It returns the random point r and the claim
+// g(r) that the caller must check, or an error if a round is invalid.
+func Verify(tr *transcript.Transcript, claim Element, v, d int, proof Proof) ([]Element, Element, error) {
+	if len(proof.Rounds) != v {
+		return nil, nil, errors.New("sumcheck: wrong number of rounds")
+	}
+	appendHeader(tr, v, d)
+	claim = claim.Clone()
+	var point []Element
+	for _, evals := range proof.Rounds {
+		if len(evals) != d+1 {
+			return nil, nil, errors.New("sumcheck: wrong degree")
+		}
+		if !claim.Clone().Add(evals[0], evals[1]).Equal(claim) {
+			return nil, nil, errors.New("sumcheck: invalid round")
+		}
+		r := roundChallenge(tr, evals)
+		point = append(point, r)
+		claim = interpolateAt(evals, r)
+	}
+	return point, claim, nil
+}
+
+func appendHeader(tr *transcript.Transcript, v, d int) {
+	tr.AppendMessage("dom-sep", []byte("sumcheck"))
+	tr.AppendUint64("v", uint64(v))
+	tr.AppendUint64("d", uint64(d))
+}
+
+func roundChallenge(tr *transcript.Transcript, evals []Element) Element {
+	for _, e := range evals {
+		tr.AppendScalar("g", e)
+	}
+	return tr.ChallengeScalar("r", fieldOf(evals...))
+}
+
+// interpolateAt returns p(r) for the polynomial p with p(i) = evals[i] with
+// the Lagrange basis on 0, 1, ..., d
+func interpolateAt(evals []Element, r Element) Element {
+	f := fieldOf(r)
+	acc := zero(f)
+	for i := range evals {
+		num := one(f)
+		den := one(f)
+		for j := range evals {
+			if i == j {
+				continue
+			}
+			num = num.Mul(num, f.Scalar().Sub(r, f.Scalar().SetInt64(int64(j))))
+			den = den.Mul(den, f.Scalar().SetInt64(int64(i-j)))
+		}
+		acc = acc.Add(acc, num.Mul(num, evals[i]).Div(num, den))
+	}
+	return acc
+}
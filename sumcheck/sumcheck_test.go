@@ -0,0 +1,83 @@
+package sumcheck
+
+import (
+	"testing"
+
+	"github.com/drand/kyber/util/random"
+	"github.com/nikkolasg/playsnark/bn254"
+	"github.com/nikkolasg/playsnark/transcript"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumcheck(t *testing.T) {
+	// g(x) = a(x) * b(x) * c(x) + d(x) on 4 variables
+	a, b, c, d := NewMLE(randomElements(16)), NewMLE(randomElements(16)), NewMLE(randomElements(16)), NewMLE(randomElements(16))
+	p := Polynomial{{a, b, c}, {d}}
+	require.Equal(t, 4, p.NumVars())
+	require.Equal(t, 3, p.Degree())
+	sum := p.Sum()
+
+	proof, point, finals := Prove(transcript.New("test"), p)
+	require.Len(t, proof.Rounds, 4)
+	require.True(t, finals[0][0].Equal(a.Evaluate(point)))
+	require.True(t, finals[1][0].Equal(d.Evaluate(point)))
+
+	vpoint, claim, err := Verify(transcript.New("test"), sum, 4, 3, proof)
+	require.NoError(t, err)
+	require.Equal(t, point, vpoint)
+	// the verifier checks the final claim with the evaluations of the oracles
+	expected := NewElement().Mul(a.Evaluate(point), b.Evaluate(point))
+	expected = expected.Mul(expected, c.Evaluate(point))
+	expected = expected.Add(expected, d.Evaluate(point))
+	require.True(t, claim.Equal(expected))
+
+	// wrong claim
+	_, _, err = Verify(transcript.New("test"), NewElement().Add(sum, one(Group)), 4, 3, proof)
+	require.Error(t, err)
+	// wrong number of variables or degree
+	_, _, err = Verify(transcript.New("test"), sum, 3, 3, proof)
+	require.Error(t, err)
+	_, _, err = Verify(transcript.New("test"), sum, 4, 2, proof)
+	require.Error(t, err)
+	// a tampered round is caught by the next round or the final check
+	tampered := Proof{Rounds: append([][]Element{}, proof.Rounds...)}
+	tampered.Rounds[3] = []Element{proof.Rounds[3][0], proof.Rounds[3][1], proof.Rounds[3][2], NewElement().Add(proof.Rounds[3][3], one(Group))}
+	vpoint, claim, err = Verify(transcript.New("test"), sum, 4, 3, tampered)
+	require.NoError(t, err)
+	require.False(t, claim.Equal(p.evaluate(vpoint)))
+	// changing g_3(0) is caught right away
+	tampered.Rounds[3][0] = NewElement().Add(proof.Rounds[3][0], one(Group))
+	_, _, err = Verify(transcript.New("test"), sum, 4, 3, tampered)
+	require.Error(t, err)
+}
+
+func TestSumcheckField(t *testing.T) {
+	// the protocol runs over the field of the extensions
+	g := bn254.NewSuite().G1()
+	evals := make([]Element, 8)
+	for i := range evals {
+		evals[i] = g.Scalar().Pick(random.New())
+	}
+	a := NewMLE(evals)
+	p := Polynomial{{a, a}}
+	proof, point, _ := Prove(transcript.New("test"), p)
+	vpoint, claim, err := Verify(transcript.New("test"), p.Sum(), 3, 2, proof)
+	require.NoError(t, err)
+	require.Equal(t, point, vpoint)
+	require.True(t, claim.Equal(g.Scalar().Mul(a.Evaluate(point), a.Evaluate(point))))
+	require.True(t, EqWith(g, nil, nil).Equal(g.Scalar().One()))
+	require.IsType(t, g.Scalar(), EqMLEWith(g, nil)[0])
+}
+
+// evaluate returns g(point)
+func (p Polynomial) evaluate(point []Element) Element {
+	acc := NewElement()
+	for _, prod := range p {
+		t := one(Group)
+		for _, m := range prod {
+			t = t.Mul(t, m.Evaluate(point))
+		}
+		acc = acc.Add(acc, t)
+	}
+	return acc
+}
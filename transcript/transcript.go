@@ -0,0 +1,138 @@
+// Package transcript implements the Fiat-Shamir transformation shared by all
+// the interactive protocols of this repository.
+//
+// In an interactive protocol, the verifier sends random challenges to the
+// prover after each of its messages. To make the protocol non interactive, the
+// prover instead derives each challenge by hashing all the messages exchanged
+// so far, the "transcript". The verifier re-computes the same transcript from
+// the proof and thus obtains the same challenges.
+//
+// The construction is a simple hash chain based on SHA-256 in the spirit of
+// Merlin: every operation replaces the state by
+//
+//	state = SHA256(state || op || len(label) || label || len(data) || data)
+//
+// where op distinguishes appending a message from squeezing a challenge. All
+// lengths are prefixed so two different sequences of operations can never lead
+// to the same hash input. Labels act as domain separators: the first one, given
+// to New, identifies the protocol and all the other ones identify each message
+// or challenge inside the protocol.
+package transcript
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/drand/kyber"
+)
+
+const (
+	opInit byte = iota
+	opAppend
+	opChallenge
+	opExpand
+)
+
+// challengeScalarLen is the number of bytes squeezed before reducing them
+// modulo the order of a group: taking twice as many bytes as the size of the
+// order makes the resulting scalar statistically close to uniform.
+const challengeScalarLen = 64
+
+// Transcript accumulates the messages of a protocol and derives challenges
+// from them. Prover and verifier must append exactly the same messages with the
+// same labels in the same order to derive the same challenges.
+type Transcript struct {
+	state [sha256.Size]byte
+}
+
+// New returns a transcript for the protocol identified by label
+func New(label string) *Transcript {
+	t := new(Transcript)
+	t.update(opInit, label, nil)
+	return t
+}
+
+// Clone returns an independent copy of the transcript in its current state
+func (t *Transcript) Clone() *Transcript {
+	return &Transcript{state: t.state}
+}
+
+// AppendMessage absorbs the given message under the given label
+func (t *Transcript) AppendMessage(label string, msg []byte) {
+	t.update(opAppend, label, msg)
+}
+
+// AppendUint64 absorbs the 8 bytes little endian encoding of v
+func (t *Transcript) AppendUint64(label string, v uint64) {
+	var buff [8]byte
+	binary.LittleEndian.PutUint64(buff[:], v)
+	t.AppendMessage(label, buff[:])
+}
+
+// AppendScalar absorbs the canonical encoding of the scalar. It works for any
+// kyber scalar, whether it comes from the BLS12-381 suite or from the curve of
+// the bproof package.
+func (t *Transcript) AppendScalar(label string, s kyber.Scalar) {
+	buff, err := s.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	t.AppendMessage(label, buff)
+}
+
+// AppendPoint absorbs the canonical (compressed) encoding of the point
+func (t *Transcript) AppendPoint(label string, p kyber.Point) {
+	buff, err := p.MarshalBinary()
+	if err != nil {
+		panic(err)
+	}
+	t.AppendMessage(label, buff)
+}
+
+// ChallengeBytes squeezes n bytes out of the transcript. The challenge itself
+// is absorbed back in the state such that two consecutive challenges are
+// different.
+func (t *Transcript) ChallengeBytes(label string, n int) []byte {
+	t.update(opChallenge, label, nil)
+	// expand the state in as many blocks as necessary:
+	// SHA256(state || opExpand || counter)
+	out := make([]byte, 0, n+sha256.Size)
+	var counter uint64
+	for len(out) < n {
+		var buff [1 + 8]byte
+		buff[0] = opExpand
+		binary.LittleEndian.PutUint64(buff[1:], counter)
+		h := sha256.New()
+		h.Write(t.state[:])
+		h.Write(buff[:])
+		out = h.Sum(out)
+		counter++
+	}
+	out = out[:n]
+	t.update(opAppend, label, out)
+	return out
+}
+
+// ScalarGroup creates the scalars of a group, kyber.Group is one
+type ScalarGroup interface {
+	Scalar() kyber.Scalar
+}
+
+// ChallengeScalar squeezes a scalar of the given group out of the transcript
+func (t *Transcript) ChallengeScalar(label string, g ScalarGroup) kyber.Scalar {
+	return g.Scalar().SetBytes(t.ChallengeBytes(label, challengeScalarLen))
+}
+
+func (t *Transcript) update(op byte, label string, data []byte) {
+	var lengths [8]byte
+	h := sha256.New()
+	h.Write(t.state[:])
+	h.Write([]byte{op})
+	binary.LittleEndian.PutUint64(lengths[:], uint64(len(label)))
+	h.Write(lengths[:])
+	h.Write([]byte(label))
+	binary.LittleEndian.PutUint64(lengths[:], uint64(len(data)))
+	h.Write(lengths[:])
+	h.Write(data)
+	copy(t.state[:], h.Sum(nil))
+}
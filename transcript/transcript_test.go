@@ -0,0 +1,79 @@
+package transcript
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/drand/kyber"
+	bls "github.com/drand/kyber-bls12381"
+	"github.com/drand/kyber/group/edwards25519"
+	"github.com/stretchr/testify/require"
+)
+
+func scalarHex(t *testing.T, s kyber.Scalar) string {
+	buff, err := s.MarshalBinary()
+	require.NoError(t, err)
+	return hex.EncodeToString(buff)
+}
+
+// TestTranscriptVectors makes sure the transcript output never changes
+// silently: any change in the construction would break all existing proofs.
+func TestTranscriptVectors(t *testing.T) {
+	tr := New("playsnark.test")
+	tr.AppendMessage("msg", []byte("hello"))
+	tr.AppendUint64("n", 42)
+	require.Equal(t, "5388fd8083080fd2c5cb91e8cced409fcad1c4bd2c1f86cb3376c1ab16e00a2c",
+		hex.EncodeToString(tr.ChallengeBytes("c", 32)))
+	// longer challenges than one hash output
+	require.Equal(t, "98f6a5fcf1c09e02adc36d2d65b6a79808a6542b1fa597043aa2b6aab8656baa"+
+		"89b43967072e92c58c145c2bf863619f0cca30193b8a9f9b6db62766124f32fe"+
+		"05f2d8fc60bc8253e45667bb752ef78cf7ee2fb48562c668a91161ae3b3d47a8"+
+		"efa5e694", hex.EncodeToString(tr.ChallengeBytes("c", 100)))
+
+	// elements of the BLS12-381 suite
+	g1 := bls.NewBLS12381Suite().G1()
+	tr.AppendScalar("s", g1.Scalar().SetInt64(7))
+	tr.AppendPoint("p", g1.Point().Base())
+	require.Equal(t, "2ebb89a36ee7dc53ae5a3216c9f80f9def99d3e15648b6275dd7ddf3b566a223",
+		scalarHex(t, tr.ChallengeScalar("x", g1)))
+
+	// elements of the curve used by bproof
+	ed := edwards25519.NewBlakeSHA256Ed25519()
+	tr.AppendScalar("s", ed.Scalar().SetInt64(7))
+	tr.AppendPoint("p", ed.Point().Base())
+	require.Equal(t, "9dd4459c0bb4b930fd5f5a806c2304906adf9d62371b805cf0126ab637c70c0e",
+		scalarHex(t, tr.ChallengeScalar("x", ed)))
+}
+
+func TestTranscriptDomainSeparation(t *testing.T) {
+	g := edwards25519.NewBlakeSHA256Ed25519()
+	challenge := func(f func(tr *Transcript)) kyber.Scalar {
+		tr := New("protocol")
+		f(tr)
+		return tr.ChallengeScalar("c", g)
+	}
+	base := challenge(func(tr *Transcript) {
+		tr.AppendMessage("a", []byte("bc"))
+	})
+	// same operations give the same challenge
+	require.True(t, base.Equal(challenge(func(tr *Transcript) {
+		tr.AppendMessage("a", []byte("bc"))
+	})))
+	// the label and the message are not simply concatenated
+	require.False(t, base.Equal(challenge(func(tr *Transcript) {
+		tr.AppendMessage("ab", []byte("c"))
+	})))
+	// the protocol label matters
+	tr := New("other protocol")
+	tr.AppendMessage("a", []byte("bc"))
+	require.False(t, base.Equal(tr.ChallengeScalar("c", g)))
+
+	// two consecutive challenges are different and a clone gives the same
+	// challenges as the original
+	tr = New("protocol")
+	clone := tr.Clone()
+	c1 := tr.ChallengeScalar("c", g)
+	c2 := tr.ChallengeScalar("c", g)
+	require.False(t, c1.Equal(c2))
+	require.True(t, c1.Equal(clone.ChallengeScalar("c", g)))
+}